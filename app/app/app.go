@@ -9,6 +9,8 @@ import (
 	"github.com/odwrtw/polochon/app/auth"
 	"github.com/odwrtw/polochon/app/dm"
 	"github.com/odwrtw/polochon/app/downloader"
+	"github.com/odwrtw/polochon/app/librarywatcher"
+	"github.com/odwrtw/polochon/app/metadatarefresher"
 	"github.com/odwrtw/polochon/app/organizer"
 	"github.com/odwrtw/polochon/app/safeguard"
 	"github.com/odwrtw/polochon/app/server"
@@ -74,9 +76,22 @@ func (a *App) init() error {
 
 	library := library.New(config)
 
-	// Build the library index
-	if err := library.RebuildIndex(log); err != nil {
-		log.WithField("function", "rebuild_index").Error(err)
+	// Load the saved library index and refresh it in the background, the
+	// whole library is scanned if no index has been saved
+	if err := library.LoadIndex(log); err == nil {
+		go func() {
+			if _, err := library.RefreshIndex(log); err != nil {
+				log.WithField("function", "refresh_index").Error(err)
+			}
+		}()
+	} else {
+		if config.Library.IndexPath != "" && !os.IsNotExist(err) {
+			log.WithField("function", "load_index").Warn(err)
+		}
+
+		if err := library.RebuildIndex(log); err != nil {
+			log.WithField("function", "rebuild_index").Error(err)
+		}
 	}
 
 	a.subApps = []subapp.App{}
@@ -95,6 +110,16 @@ func (a *App) init() error {
 		a.subApps = append(a.subApps, dm.New(config, library))
 	}
 
+	if config.Library.FsNotifier != nil {
+		// Add the library watcher
+		a.subApps = append(a.subApps, librarywatcher.New(config, library))
+	}
+
+	if config.Library.MetadataRefresh.Enabled {
+		// Add the metadata refresher
+		a.subApps = append(a.subApps, metadatarefresher.New(config, library))
+	}
+
 	// Only run the HTTP server if specified
 	if config.HTTPServer.Enable {
 		// Read the config of the auth manager
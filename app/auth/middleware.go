@@ -27,12 +27,22 @@ func rightForRequest(r *http.Request) Right {
 	if strings.HasPrefix(r.URL.Path, "/debug/") || r.URL.Path == "/metrics" {
 		return RightDebug
 	}
-	if r.Method == http.MethodGet {
+	if r.Method == http.MethodGet || isProgressRequest(r) {
 		return RightRead
 	}
 	return RightWrite
 }
 
+// isProgressRequest returns true if the request is made on the progress of a
+// video, the progress is kept per token so the readers can save their own
+func isProgressRequest(r *http.Request) bool {
+	if !strings.HasSuffix(r.URL.Path, "/progress") {
+		return false
+	}
+
+	return strings.HasPrefix(r.URL.Path, "/movies/") || strings.HasPrefix(r.URL.Path, "/shows/")
+}
+
 // ServeHTTP implements the negroni middleware interface.
 func (m *Middleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	token := r.Header.Get("X-Auth-Token")
@@ -36,6 +36,12 @@ func TestMiddleWare(t *testing.T) {
 	mux.HandleFunc("/private/write", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
+	mux.HandleFunc("/movies/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/shows/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
 
 	n := negroni.New()
 	n.Use(NewMiddleware(manager))
@@ -76,6 +82,24 @@ func TestMiddleWare(t *testing.T) {
 			token:          "token1",
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name: "reader can save a movie progress",
+			path: "/movies/tt0133093/progress", method: "PUT",
+			token:          "token1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "reader can save an episode progress",
+			path: "/shows/tt0944947/seasons/1/episodes/2/progress", method: "PUT",
+			token:          "token1",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "reader cannot delete a movie",
+			path: "/movies/tt0133093", method: "DELETE",
+			token:          "token1",
+			expectedStatus: http.StatusNotFound,
+		},
 		{
 			name: "reader cannot debug",
 			path: "/debug/pprof/", method: "GET",
@@ -34,7 +34,7 @@ func (dm *DownloadManager) cleanTorrent(torrent *polochon.Torrent, log *logrus.E
 		// Check extension
 		ext := path.Ext(filePath)
 		if !slices.Contains(dm.config.File.AllowedExtensionsToDelete, ext) {
-			if !file.IsSymlink() {
+			if !isImported(file) {
 				// Not allowed to delete these types of files
 				log.WithFields(logrus.Fields{
 					"extension":     ext,
@@ -42,7 +42,14 @@ func (dm *DownloadManager) cleanTorrent(torrent *polochon.Torrent, log *logrus.E
 				}).Debug("protected extension")
 				continue
 			} else {
-				log.Debugf("file %q is a symlink, delete it", filePath)
+				log.Debugf("file %q has been imported, delete it", filePath)
+			}
+		}
+
+		// Remove the mark of the files imported without being moved
+		if file.IsIgnored() {
+			if err := dm.remove(file.IgnorePath(), log); err != nil {
+				log.Warnf("got error while removing file %q", err)
 			}
 		}
 
@@ -1,12 +1,15 @@
 package dm
 
 import (
+	"cmp"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/odwrtw/polochon/app/subapp"
 	polochon "github.com/odwrtw/polochon/lib"
 	"github.com/odwrtw/polochon/lib/configuration"
+	"github.com/odwrtw/polochon/lib/history"
 	"github.com/odwrtw/polochon/lib/library"
 	"github.com/sirupsen/logrus"
 )
@@ -14,6 +17,10 @@ import (
 // AppName is the application name
 const AppName = "download_manager"
 
+// minSecondaryVideoSize is the size under which the secondary files of a movie
+// torrent are considered as samples
+const minSecondaryVideoSize = 100 * 1024 * 1024
+
 // DownloadManager represents the download manager
 type DownloadManager struct {
 	*subapp.Base
@@ -102,78 +109,247 @@ func (dm *DownloadManager) run(log *logrus.Entry) {
 			continue
 		}
 
-		video := torrent.Video()
-		if video == nil {
+		if !torrent.HasVideo() && !torrent.IsSeasonPack() {
 			tlog.Debugf("torrent is not a video")
+			dm.publishFinished(torrent)
 			dm.moveToWatcherDirectory(torrent, tlog)
 			continue
 		}
 
-		file := dm.findVideoFile(torrent)
-		if file == nil {
-			tlog.Debugf("torrent video file not found")
-			dm.moveToWatcherDirectory(torrent, tlog)
-			continue
+		dm.organizeTorrent(torrent, tlog)
+	}
+}
+
+// organizeTorrent organizes every video file of a finished torrent along with
+// the subtitles bundled in the torrent
+func (dm *DownloadManager) organizeTorrent(torrent *polochon.Torrent, log *logrus.Entry) {
+	files := dm.findVideoFiles(torrent)
+	if len(files) == 0 {
+		log.Debugf("torrent video file not found")
+		dm.resolveHistory(dm.config.Downloader.History.Reject(torrent, "video file not found"), log)
+		dm.publishFinished(torrent)
+		dm.moveToWatcherDirectory(torrent, log)
+		return
+	}
+
+	// Skip the files already in the library
+	files = slices.DeleteFunc(files, isImported)
+	if len(files) == 0 {
+		if torrent.RatioReached(dm.config.DownloadManager.Ratio) {
+			dm.cleanTorrent(torrent, log)
 		}
-		video.SetFile(*file)
+		return
+	}
 
-		if file.IsSymlink() {
-			if torrent.RatioReached(dm.config.DownloadManager.Ratio) {
-				dm.cleanTorrent(torrent, tlog)
-			}
+	dm.publishFinished(torrent)
+
+	videos := dm.torrentVideos(torrent, files, log)
+	if !slices.ContainsFunc(videos, func(v polochon.Video) bool { return v != nil }) {
+		log.Debugf("no video found in the torrent")
+		// The release may be fine, the guessers only failed to match its files
+		dm.resolveHistory(dm.config.Downloader.History.Fail(torrent, "no video found in the torrent"), log)
+		dm.moveToWatcherDirectory(torrent, log)
+		return
+	}
+
+	subtitles := dm.findSubtitleFiles(torrent)
+
+	organized := make([]bool, len(files))
+	count := 0
+	for i, file := range files {
+		if videos[i] == nil {
 			continue
 		}
 
-		metadata, err := file.GuessMetadata(tlog)
-		if err != nil {
-			tlog.Warnf("failed to guess metadata: %s", err.Error())
+		flog := log.WithField("file", file.Path)
+		subs := bundledSubtitles(file, subtitles, len(files) == 1)
+		if dm.organize(videos[i], file, subs, flog) {
+			organized[i] = true
+			count++
 		}
-		video.SetMetadata(metadata)
+	}
 
-		// TODO: update the lib to handle this
-		switch v := video.(type) {
-		case *polochon.Movie:
-			v.MovieConfig = dm.config.Movie
-		case *polochon.ShowEpisode:
-			v.ShowConfig = dm.config.Show
-		default:
-			dm.moveToWatcherDirectory(torrent, tlog)
+	if count == 0 {
+		dm.resolveHistory(dm.config.Downloader.History.Fail(torrent, "no video organized"), log)
+		dm.moveToWatcherDirectory(torrent, log)
+		return
+	}
+
+	// Let the organizer handle the files not matching any video
+	for i, file := range files {
+		if organized[i] {
 			continue
 		}
 
-		// Get the video details
-		if err := polochon.GetDetails(video, tlog); err != nil {
-			if err != polochon.ErrGettingDetails {
-				tlog.Error(err)
+		newPath := filepath.Join(dm.config.Watcher.Dir, filepath.Base(file.Path))
+		log.Debugf("moving %s to %s", file.Path, newPath)
+		if err := library.MoveFile(file.Path, newPath); err != nil {
+			log.Errorf("error while moving torrent file: %s", err.Error())
+		}
+	}
+
+	dm.resolveHistory(dm.config.Downloader.History.Complete(torrent), log)
+
+	log.WithField("videos", count).Debugf("torrent organized")
+}
+
+// torrentVideos returns the videos of the torrent video files, nil is
+// returned for the files not matching any video, the files are sorted from
+// the largest to the smallest
+func (dm *DownloadManager) torrentVideos(torrent *polochon.Torrent, files []*polochon.File, log *logrus.Entry) []polochon.Video {
+	slices.SortStableFunc(files, func(a, b *polochon.File) int {
+		return cmp.Compare(b.Size, a.Size)
+	})
+
+	videos := make([]polochon.Video, len(files))
+
+	// The largest file of a movie torrent is the movie, the others are
+	// guessed as any other video unless they're samples or extras of the
+	// movie
+	if torrent.Type == polochon.TypeMovie {
+		videos[0] = torrent.Video()
+		for i, file := range files[1:] {
+			flog := log.WithField("file", file.Path)
+			if file.Size < minSecondaryVideoSize {
+				flog.Debug("file too small to be another video")
+				continue
 			}
 
-			dm.moveToWatcherDirectory(torrent, tlog)
+			video, err := file.Guess(dm.config.Movie, dm.config.Show, flog)
+			if err != nil {
+				flog.Debugf("failed to guess the video: %s", err.Error())
+				continue
+			}
+
+			if m, ok := video.(*polochon.Movie); ok && m.ImdbID == torrent.ImdbID {
+				flog.Debug("file is an extra of the torrent movie")
+				continue
+			}
+
+			videos[i+1] = video
+		}
+
+		return videos
+	}
+
+	// The episodes are guessed from the file names, a file can only hold one
+	// episode
+	found := map[int]bool{}
+	for i, file := range files {
+		flog := log.WithField("file", file.Path)
+		guess, err := file.Guess(dm.config.Movie, dm.config.Show, flog)
+		if err != nil {
+			flog.Debugf("failed to guess the episode: %s", err.Error())
+			continue
+		}
+
+		episode, ok := guess.(*polochon.ShowEpisode)
+		if !ok || episode.Season != torrent.Season || found[episode.Episode] {
+			flog.Debugf("file is not an episode of the season %d", torrent.Season)
 			continue
 		}
 
-		// Get the video subtitles
-		for _, lang := range dm.config.SubtitleLanguages {
-			_, err := polochon.GetSubtitle(video, lang, tlog)
-			if err != nil && err != polochon.ErrNoSubtitleFound {
-				tlog.Error(err)
+		videos[i] = torrent.EpisodeVideo(episode.Episode)
+		found[episode.Episode] = videos[i] != nil
+	}
+
+	// Without any file matching the torrent episode, the largest unknown file
+	// is the episode
+	if torrent.HasVideo() && !found[torrent.Episode] {
+		if i := slices.Index(videos, nil); i != -1 {
+			videos[i] = torrent.Video()
+		}
+	}
+
+	return videos
+}
+
+// organize gets the details and the subtitles of a downloaded video and adds
+// it to the library, it returns false if the video has not been added
+func (dm *DownloadManager) organize(video polochon.Video, file *polochon.File, subtitles map[polochon.Language]*polochon.File, log *logrus.Entry) bool {
+	video.SetFile(*file)
+
+	metadata, err := file.GuessMetadata(log)
+	if err != nil {
+		log.Warnf("failed to guess metadata: %s", err.Error())
+	}
+	video.SetMetadata(metadata)
+
+	// TODO: update the lib to handle this
+	switch v := video.(type) {
+	case *polochon.Movie:
+		v.MovieConfig = dm.config.Movie
+	case *polochon.ShowEpisode:
+		v.ShowConfig = dm.config.Show
+	default:
+		return false
+	}
+
+	// Get the video details
+	if err := polochon.GetDetails(video, log); err != nil {
+		if err != polochon.ErrGettingDetails {
+			log.Error(err)
+		}
+
+		return false
+	}
+
+	// Get the video subtitles, the subtitles bundled in the torrent come
+	// first
+	for _, lang := range dm.config.SubtitleLanguages {
+		if sub, ok := subtitles[lang]; ok {
+			err := importSubtitle(video, sub, lang)
+			if err == nil {
+				log.WithField("lang", lang).Debugf("bundled subtitle imported")
+				continue
 			}
+
+			log.Warnf("failed to import the bundled subtitle: %s", err.Error())
 		}
 
-		// Store the video
-		if err := dm.library.Add(video, tlog); err != nil {
-			tlog.Error(err)
-			dm.moveToWatcherDirectory(torrent, tlog)
-			continue
+		_, err := polochon.GetSubtitle(video, lang, log)
+		if err != nil && err != polochon.ErrNoSubtitleFound {
+			log.Error(err)
 		}
+	}
 
-		// Notify
-		dm.Notify(video, tlog)
+	// Keep the quality of the video being replaced if any
+	previous, err := dm.library.IndexedQuality(video)
+	replaced := err == nil
 
-		tlog.Debugf("torrent organized")
+	// Store the video
+	mode := dm.config.DownloadManager.ImportMode
+	if err := dm.library.Import(video, mode, log); err != nil {
+		log.Error(err)
+		return false
 	}
+
+	// The file is left in place to keep seeding, mark it as imported
+	if mode.KeepsSource() {
+		if err := file.Ignore(); err != nil {
+			log.Warnf("failed to mark the file as imported: %s", err.Error())
+		}
+	}
+
+	// Notify
+	dm.Notify(video, log)
+	if replaced {
+		polochon.PublishVideoReplaced(dm.config.Notifiers, video, previous, log)
+	}
+
+	return true
 }
 
-func (dm *DownloadManager) findVideoFile(torrent *polochon.Torrent) *polochon.File {
+// isImported returns true if the file has already been imported into the
+// library, the moved files are replaced by symlinks and the others are marked
+// as ignored
+func isImported(file *polochon.File) bool {
+	return file.IsSymlink() || file.IsIgnored()
+}
+
+// findVideoFiles returns the video files of a torrent
+func (dm *DownloadManager) findVideoFiles(torrent *polochon.Torrent) []*polochon.File {
+	files := []*polochon.File{}
 	for _, tPath := range torrent.Status.FilePaths {
 		filePath := filepath.Join(dm.config.DownloadManager.Dir, tPath)
 		file := polochon.NewFileWithConfig(filePath, dm.config.File)
@@ -183,11 +359,11 @@ func (dm *DownloadManager) findVideoFile(torrent *polochon.Torrent) *polochon.Fi
 		}
 
 		if file.IsVideo() {
-			return file
+			files = append(files, file)
 		}
 	}
 
-	return nil
+	return files
 }
 
 func (dm *DownloadManager) moveToWatcherDirectory(torrent *polochon.Torrent, log *logrus.Entry) {
@@ -215,6 +391,23 @@ func (dm *DownloadManager) moveToWatcherDirectory(torrent *polochon.Torrent, log
 	dm.cleanTorrent(torrent, log)
 }
 
+// resolveHistory logs the errors returned while updating the history
+func (dm *DownloadManager) resolveHistory(err error, log *logrus.Entry) {
+	switch err {
+	case nil:
+	case history.ErrNotFound:
+		log.Debug("torrent not found in the download history")
+	default:
+		log.Errorf("failed to update the download history: %q", err)
+	}
+}
+
+// publishFinished publishes the event of a torrent being finished
+func (dm *DownloadManager) publishFinished(torrent *polochon.Torrent) {
+	e := polochon.NewTorrentEvent(polochon.EventTorrentFinished, torrent)
+	polochon.PublishEvent(dm.config.Notifiers, e)
+}
+
 // Notify sends video to the notifiers
 func (dm *DownloadManager) Notify(v polochon.Video, log *logrus.Entry) {
 	log = log.WithField("function", "notify")
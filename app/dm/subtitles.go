@@ -0,0 +1,80 @@
+package dm
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+// subtitleExtension is the extension of the subtitles handled by the library
+const subtitleExtension = ".srt"
+
+// findSubtitleFiles returns the subtitle files bundled in a torrent
+func (dm *DownloadManager) findSubtitleFiles(torrent *polochon.Torrent) []*polochon.File {
+	files := []*polochon.File{}
+	for _, tPath := range torrent.Status.FilePaths {
+		if !strings.EqualFold(filepath.Ext(tPath), subtitleExtension) {
+			continue
+		}
+
+		file := polochon.NewFile(filepath.Join(dm.config.DownloadManager.Dir, tPath))
+		if file.Exists() {
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
+
+// bundledSubtitles returns the subtitles of a video file by language, the
+// subtitles of a torrent holding many videos must contain the name of the
+// video file in their path e.g. "Subs/Show.S01E01/English.srt", the largest
+// subtitle is kept for each language as the smallest ones are usually forced
+// subtitles
+func bundledSubtitles(video *polochon.File, subtitles []*polochon.File, single bool) map[polochon.Language]*polochon.File {
+	name := strings.ToLower(filepath.Base(video.PathWithoutExt()))
+
+	found := map[polochon.Language]*polochon.File{}
+	for _, sub := range subtitles {
+		if !single && !strings.Contains(strings.ToLower(sub.Path), name) {
+			continue
+		}
+
+		lang, err := polochon.LanguageFromFilename(sub.Path)
+		if err != nil {
+			continue
+		}
+
+		if current, ok := found[lang]; !ok || sub.Size > current.Size {
+			found[lang] = sub
+		}
+	}
+
+	return found
+}
+
+// importSubtitle adds a subtitle file to the video, the subtitle is saved
+// next to the video when it's added to the library
+func importSubtitle(video polochon.Video, file *polochon.File, lang polochon.Language) error {
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return err
+	}
+
+	sub := &polochon.Subtitle{
+		File:  *file,
+		Data:  data,
+		Lang:  lang,
+		Video: video,
+	}
+
+	subtitles := slices.DeleteFunc(video.GetSubtitles(), func(s *polochon.Subtitle) bool {
+		return s.Lang == lang
+	})
+	video.SetSubtitles(append(subtitles, sub))
+
+	return nil
+}
@@ -1,9 +1,13 @@
 package downloader
 
 import (
+	"slices"
+	"time"
+
 	"github.com/odwrtw/polochon/app/subapp"
 	polochon "github.com/odwrtw/polochon/lib"
 	"github.com/odwrtw/polochon/lib/configuration"
+	"github.com/odwrtw/polochon/lib/history"
 	"github.com/odwrtw/polochon/lib/library"
 	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
@@ -104,6 +108,10 @@ func (d *Downloader) downloader(log *logrus.Entry) {
 }
 
 func (d *Downloader) downloadMissingVideos(log *logrus.Entry) {
+	if d.config.Downloader.DeleteWatched.Enabled {
+		d.deleteWatchedEpisodes(log)
+	}
+
 	// Fetch wishlist
 	wl := polochon.NewWishlist(d.config.Wishlist, log)
 	if err := wl.Fetch(); err != nil {
@@ -127,8 +135,26 @@ func (d *Downloader) downloadMissingMovies(wl *polochon.Wishlist, log *logrus.En
 			continue
 		}
 
+		qualities := wantedMovie.Qualities
 		if ok {
-			log.Debugf("movie %q already in the video store", wantedMovie.ImdbID)
+			indexed, err := d.library.GetIndexedMovie(wantedMovie.ImdbID)
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+
+			// Only look for the qualities that would upgrade the movie
+			qualities = polochon.UpgradeQualities(indexed.Quality, wantedMovie.Qualities)
+			if len(qualities) == 0 {
+				log.Debugf("movie %q already in the video store", wantedMovie.ImdbID)
+				continue
+			}
+
+			log.Infof("looking for an upgrade of the %q movie", indexed.Quality)
+		}
+
+		if !d.config.Downloader.History.CanAttempt(wantedMovie.ImdbID, 0, 0) {
+			log.Debug("waiting before trying to download the movie again")
 			continue
 		}
 
@@ -143,27 +169,28 @@ func (d *Downloader) downloadMissingMovies(wl *polochon.Wishlist, log *logrus.En
 		}
 
 		log = log.WithField("title", m.Title)
+		wanted := &polochon.Torrent{ImdbID: m.ImdbID, Type: polochon.TypeMovie}
 
 		if err := polochon.GetTorrents(m, log); err != nil {
+			// Only record the missing torrents, the torrenters may be down
 			if err == polochon.ErrTorrentNotFound {
-				continue
+				d.record(wanted, nil, log)
+			} else {
+				log.Error(err)
 			}
-
-			log.Error(err)
+			continue
 		}
 
-		torrent := polochon.ChooseTorrentFromQualities(m.Torrents, wantedMovie.Qualities)
+		torrent := d.config.Downloader.TorrentPolicy.Choose(m.Torrents, qualities)
 		if torrent == nil {
 			log.Debug("no torrent found")
+			d.record(wanted, nil, log)
 			continue
 		}
 
 		torrent.Type = polochon.TypeMovie
 		torrent.ImdbID = m.ImdbID
-		if err := d.config.Downloader.Client.Download(torrent); err != nil {
-			log.Error(err)
-			continue
-		}
+		d.download(torrent, log)
 	}
 }
 
@@ -184,7 +211,7 @@ func (d *Downloader) downloadMissingShows(wl *polochon.Wishlist, log *logrus.Ent
 			continue
 		}
 
-		calendar, err := s.GetCalendar(log)
+		calendar, err := d.library.ShowCalendar(s, log)
 		if err != nil {
 			if err == polochon.ErrCalendarNotFound {
 				log.Info("calendar not found")
@@ -194,14 +221,39 @@ func (d *Downloader) downloadMissingShows(wl *polochon.Wishlist, log *logrus.Ent
 			continue
 		}
 
+		// The episodes of the daily shows are released by air date
+		s.Daily = wishedShow.Daily
+
+		// Download the fully missing seasons as season packs, there is no
+		// season pack for the daily shows
+		packs := map[int]bool{}
+		if !s.Daily {
+			packs = d.downloadSeasonPacks(s, wishedShow, calendar, log)
+		}
+
 		for _, calEpisode := range calendar.Episodes {
-			if calEpisode.Season == 0 {
-				// Skip the show "Specials" episodes
+			// Only the last days of the daily shows are wanted
+			if s.Daily && !calEpisode.IsRecent(wishedShow.WantedDays()) {
+				continue
+			}
+
+			// Skip the show "Specials" episodes unless they're wanted
+			if calEpisode.IsSpecial() && !wishedShow.Specials {
+				continue
+			}
+
+			// The episodes of the season are in a season pack
+			if packs[calEpisode.Season] {
 				continue
 			}
 
 			// Check if the episode should be downloaded
-			if calEpisode.IsOlder(wishedShow) {
+			if !s.Daily && calEpisode.IsOlder(wishedShow) {
+				continue
+			}
+
+			// The watched episodes may have been deleted on purpose
+			if d.watched(wishedShow.ImdbID, calEpisode.Season, calEpisode.Episode) {
 				continue
 			}
 
@@ -212,7 +264,22 @@ func (d *Downloader) downloadMissingShows(wl *polochon.Wishlist, log *logrus.Ent
 				continue
 			}
 
+			qualities := wishedShow.Qualities
 			if ok {
+				indexed, err := d.library.GetIndexedEpisode(wishedShow.ImdbID, calEpisode.Season, calEpisode.Episode)
+				if err != nil {
+					log.Error(err)
+					continue
+				}
+
+				// Only look for the qualities that would upgrade the episode
+				qualities = polochon.UpgradeQualities(indexed.Quality, wishedShow.Qualities)
+				if len(qualities) == 0 {
+					continue
+				}
+			}
+
+			if !d.config.Downloader.History.CanAttempt(wishedShow.ImdbID, calEpisode.Season, calEpisode.Episode) {
 				continue
 			}
 
@@ -222,6 +289,11 @@ func (d *Downloader) downloadMissingShows(wl *polochon.Wishlist, log *logrus.Ent
 			e.ShowTitle = s.Title
 			e.Season = calEpisode.Season
 			e.Episode = calEpisode.Episode
+			e.AbsoluteNumber = calEpisode.AbsoluteNumber
+			e.Show = s
+			if calEpisode.AiredDate != nil && !calEpisode.AiredDate.IsZero() {
+				e.Aired = calEpisode.AiredDate.Format(polochon.AiredDateLayout)
+			}
 			log = log.WithFields(logrus.Fields{
 				"show_imdb_id": e.ShowImdbID,
 				"show_title":   e.ShowTitle,
@@ -229,18 +301,28 @@ func (d *Downloader) downloadMissingShows(wl *polochon.Wishlist, log *logrus.Ent
 				"episode":      e.Episode,
 			})
 
+			wanted := &polochon.Torrent{
+				ImdbID:  e.ShowImdbID,
+				Type:    polochon.TypeEpisode,
+				Season:  e.Season,
+				Episode: e.Episode,
+			}
+
 			err = polochon.GetTorrents(e, log)
 			if err != nil {
-				if err != polochon.ErrTorrentNotFound {
+				// Only record the missing torrents, the torrenters may be down
+				if err == polochon.ErrTorrentNotFound {
+					d.record(wanted, nil, log)
+				} else {
 					log.Error(err)
 				}
-
 				continue
 			}
 
-			torrent := polochon.ChooseTorrentFromQualities(e.Torrents, wishedShow.Qualities)
+			torrent := d.config.Downloader.TorrentPolicy.Choose(e.Torrents, qualities)
 			if torrent == nil {
 				log.Debug("no torrent found")
+				d.record(wanted, nil, log)
 				continue
 			}
 
@@ -248,10 +330,209 @@ func (d *Downloader) downloadMissingShows(wl *polochon.Wishlist, log *logrus.Ent
 			torrent.ImdbID = e.ShowImdbID
 			torrent.Season = e.Season
 			torrent.Episode = e.Episode
-			if err := d.config.Downloader.Client.Download(torrent); err != nil {
+			d.download(torrent, log)
+		}
+	}
+}
+
+// missingSeasons returns the seasons of which every aired episode is wanted
+// and missing from the library
+func (d *Downloader) missingSeasons(wishedShow *polochon.WishedShow, calendar *polochon.ShowCalendar) ([]int, error) {
+	seasons := []int{}
+	missing := map[int]bool{}
+	episodes := map[int]int{}
+
+	for _, calEpisode := range calendar.Episodes {
+		if calEpisode.IsSpecial() || !calEpisode.IsAvailable() {
+			continue
+		}
+
+		season := calEpisode.Season
+		if _, ok := missing[season]; !ok {
+			seasons = append(seasons, season)
+			missing[season] = true
+		}
+
+		if !missing[season] {
+			continue
+		}
+
+		if calEpisode.IsOlder(wishedShow) || d.watched(wishedShow.ImdbID, season, calEpisode.Episode) {
+			missing[season] = false
+			continue
+		}
+
+		ok, err := d.library.HasShowEpisode(wishedShow.ImdbID, season, calEpisode.Episode)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			missing[season] = false
+			continue
+		}
+
+		episodes[season]++
+	}
+
+	// A single episode is not worth a season pack
+	return slices.DeleteFunc(seasons, func(season int) bool {
+		return !missing[season] || episodes[season] < 2
+	}), nil
+}
+
+// watched returns true if the episode has been watched and the watched
+// episodes are deleted
+func (d *Downloader) watched(imdbID string, season, episode int) bool {
+	if !d.config.Downloader.DeleteWatched.Enabled {
+		return false
+	}
+
+	_, ok := d.config.Library.Progress.WatchedAt(polochon.VideoKey(imdbID, season, episode))
+	return ok
+}
+
+// deleteWatchedEpisodes deletes the episodes of the library watched before
+// the retention delay
+func (d *Downloader) deleteWatchedEpisodes(log *logrus.Entry) {
+	log = log.WithField("function", "delete_watched_episodes")
+	limit := time.Now().Add(-d.config.Downloader.DeleteWatched.Retention)
+
+	for _, id := range d.library.EpisodeIDs() {
+		watchedAt, ok := d.config.Library.Progress.WatchedAt(polochon.VideoKey(id.ImdbID, id.Season, id.Episode))
+		if !ok || watchedAt.After(limit) {
+			continue
+		}
+
+		log := log.WithFields(logrus.Fields{
+			"show_imdb_id": id.ImdbID,
+			"season":       id.Season,
+			"episode":      id.Episode,
+		})
+
+		e, err := d.library.GetEpisode(id.ImdbID, id.Season, id.Episode)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+
+		log.Info("deleting watched episode")
+		if err := d.library.Delete(e, log); err != nil {
+			log.Error(err)
+			continue
+		}
+
+		polochon.PublishEvent(d.config.Notifiers, polochon.NewVideoDeletedEvent(e))
+	}
+}
+
+// downloadSeasonPacks downloads the missing seasons of a show as season packs
+// and returns the seasons handled by a season pack
+func (d *Downloader) downloadSeasonPacks(s *polochon.Show, wishedShow *polochon.WishedShow, calendar *polochon.ShowCalendar, log *logrus.Entry) map[int]bool {
+	packs := map[int]bool{}
+
+	seasons, err := d.missingSeasons(wishedShow, calendar)
+	if err != nil {
+		log.Error(err)
+		return packs
+	}
+
+	for _, season := range seasons {
+		log := log.WithField("season", season)
+
+		if d.config.Downloader.History.IsPending(wishedShow.ImdbID, season, 0) {
+			log.Debug("waiting for the season pack to be downloaded")
+			packs[season] = true
+			continue
+		}
+
+		// Download the episodes one by one while waiting for the next attempt
+		if !d.config.Downloader.History.CanAttempt(wishedShow.ImdbID, season, 0) {
+			continue
+		}
+
+		ss := polochon.NewShowSeason(d.config.Show)
+		ss.ShowImdbID = wishedShow.ImdbID
+		ss.ShowTitle = s.Title
+		ss.Season = season
+
+		wanted := &polochon.Torrent{
+			ImdbID: ss.ShowImdbID,
+			Type:   polochon.TypeEpisode,
+			Season: ss.Season,
+		}
+
+		if err := polochon.GetTorrents(ss, log); err != nil {
+			// Only record the missing torrents, the torrenters may be down
+			if err == polochon.ErrTorrentNotFound {
+				d.record(wanted, nil, log)
+			} else {
 				log.Error(err)
-				continue
 			}
+			continue
+		}
+
+		torrent := d.config.Downloader.TorrentPolicy.Choose(ss.Torrents, wishedShow.Qualities)
+		if torrent == nil {
+			log.Debug("no season pack found")
+			d.record(wanted, nil, log)
+			continue
 		}
+
+		torrent.Type = polochon.TypeEpisode
+		torrent.ImdbID = ss.ShowImdbID
+		torrent.Season = ss.Season
+		torrent.Episode = 0
+
+		log.Info("downloading a season pack")
+		packs[season] = d.download(torrent, log)
+	}
+
+	return packs
+}
+
+// download sends the torrent to the downloader and records the attempt, it
+// returns true if the torrent is being downloaded
+func (d *Downloader) download(torrent *polochon.Torrent, log *logrus.Entry) bool {
+	err := d.config.Downloader.Client.Download(torrent)
+	switch err {
+	case nil:
+		d.publishAdded(torrent)
+	case polochon.ErrDuplicateTorrent:
+		// The torrent is already downloading, nothing to record
+		log.Debug("torrent already added")
+		return true
+	default:
+		log.Error(err)
+	}
+
+	d.record(torrent, err, log)
+	return err == nil
+}
+
+// record records a grab attempt in the history, a torrent without result
+// means that no torrent was found
+func (d *Downloader) record(torrent *polochon.Torrent, err error, log *logrus.Entry) {
+	outcome := history.OutcomeGrabbed
+	switch {
+	case torrent.Result == nil:
+		outcome = history.OutcomeNotFound
+	case err != nil:
+		outcome = history.OutcomeFailed
+	}
+
+	entry := history.NewEntry(torrent, outcome)
+	if err != nil {
+		entry.Error = err.Error()
 	}
+
+	if err := d.config.Downloader.History.Record(entry); err != nil {
+		log.Errorf("failed to record the download history: %q", err)
+	}
+}
+
+// publishAdded publishes the event of a torrent being added
+func (d *Downloader) publishAdded(torrent *polochon.Torrent) {
+	e := polochon.NewTorrentEvent(polochon.EventTorrentAdded, torrent)
+	polochon.PublishEvent(d.config.Notifiers, e)
 }
@@ -0,0 +1,127 @@
+package librarywatcher
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/odwrtw/polochon/app/subapp"
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/configuration"
+	"github.com/odwrtw/polochon/lib/library"
+	"github.com/sirupsen/logrus"
+)
+
+// AppName is the application name
+const AppName = "library_watcher"
+
+// delay is the time to wait without any change before refreshing the index,
+// a video being added changes many files in a row
+const delay = 2 * time.Second
+
+// LibraryWatcher keeps the library index up to date with the changes made
+// on the library directories
+type LibraryWatcher struct {
+	*subapp.Base
+
+	config  *configuration.Config
+	library *library.Library
+}
+
+// New returns a new library watcher
+func New(config *configuration.Config, library *library.Library) *LibraryWatcher {
+	return &LibraryWatcher{
+		Base:    subapp.NewBase(AppName),
+		config:  config,
+		library: library,
+	}
+}
+
+// Run starts the library watcher
+func (w *LibraryWatcher) Run(log *logrus.Entry) error {
+	// Init the app
+	w.InitStart(log)
+
+	log = log.WithField("app", AppName)
+
+	defer log.Debug("library watcher stopped")
+
+	ctx := polochon.FsNotifierCtx{
+		Event:      make(chan string),
+		Done:       w.Done,
+		Wg:         &w.Wg,
+		Recursive:  true,
+		AllChanges: true,
+	}
+
+	// Launch the FsNotifier on the library directories
+	for _, dir := range []string{w.config.Library.MovieDir, w.config.Library.ShowDir} {
+		if dir == "" {
+			continue
+		}
+
+		if err := w.config.Library.FsNotifier.Watch(dir, ctx, log); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	w.Wg.Add(1)
+	go func() {
+		defer func() {
+			w.Wg.Done()
+			if r := recover(); r != nil {
+				err = subapp.ErrPanicRecovered
+				w.Stop(log)
+			}
+		}()
+
+		w.handleEvents(ctx.Event, log)
+	}()
+
+	w.Wg.Wait()
+
+	return err
+}
+
+// handleEvents gathers the changed paths and refreshes the index once the
+// changes are done
+func (w *LibraryWatcher) handleEvents(events <-chan string, log *logrus.Entry) {
+	paths := map[string]struct{}{}
+
+	timer := time.NewTimer(delay)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case path := <-events:
+			log.WithField("event", path).Debug("got an event")
+			paths[path] = struct{}{}
+			timer.Reset(delay)
+		case <-timer.C:
+			w.refresh(slices.Collect(maps.Keys(paths)), log)
+			clear(paths)
+		case <-w.Done:
+			log.Debug("library watcher done handling events")
+			return
+		}
+	}
+}
+
+// refresh refreshes the index entries of the changed paths and publishes the
+// changes
+func (w *LibraryWatcher) refresh(paths []string, log *logrus.Entry) {
+	log.WithField("paths", len(paths)).Debug("refreshing the library index")
+
+	for _, e := range w.library.RefreshPaths(paths, log) {
+		log.WithFields(logrus.Fields{
+			"event":   e.Type,
+			"imdb_id": e.ImdbID,
+			"season":  e.Season,
+			"episode": e.Episode,
+		}).Info("library changed")
+
+		polochon.PublishEvent(w.config.Notifiers, e)
+	}
+}
@@ -0,0 +1,114 @@
+package metadatarefresher
+
+import (
+	"github.com/odwrtw/polochon/app/subapp"
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/configuration"
+	"github.com/odwrtw/polochon/lib/library"
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// AppName is the application name
+const AppName = "metadata_refresher"
+
+// MetadataRefresher refreshes the metadata of the library periodically
+type MetadataRefresher struct {
+	*subapp.Base
+
+	config  *configuration.Config
+	library *library.Library
+	event   chan struct{}
+}
+
+// New returns a new metadata refresher
+func New(config *configuration.Config, library *library.Library) *MetadataRefresher {
+	return &MetadataRefresher{
+		Base:    subapp.NewBase(AppName),
+		config:  config,
+		library: library,
+	}
+}
+
+// Run starts the metadata refresher
+func (m *MetadataRefresher) Run(log *logrus.Entry) error {
+	log = log.WithField("app", AppName)
+
+	// Init the app
+	m.InitStart(log)
+
+	log.Debug("metadata refresher started")
+	m.event = make(chan struct{}, 1)
+
+	// Start the scheduler
+	m.Wg.Go(func() {
+		m.scheduler(log)
+	})
+
+	// Start the refresher
+	var err error
+	m.Wg.Add(1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = subapp.ErrPanicRecovered
+				m.Stop(log)
+			}
+
+			m.Wg.Done()
+		}()
+		m.refresher(log)
+	}()
+
+	defer log.Debug("metadata refresher stopped")
+
+	m.Wg.Wait()
+
+	return err
+}
+
+func (m *MetadataRefresher) scheduler(log *logrus.Entry) {
+	c := cron.New()
+	c.Schedule(m.config.Library.MetadataRefresh.Schedule, cron.FuncJob(func() {
+		log.Debug("metadata refresher scheduler triggered")
+		// Skip this run if a refresh is already pending
+		select {
+		case m.event <- struct{}{}:
+		default:
+		}
+	}))
+	c.Start()
+
+	<-m.Done
+	log.Debug("metadata refresher scheduler stopped")
+	c.Stop()
+}
+
+func (m *MetadataRefresher) refresher(log *logrus.Entry) {
+	for {
+		select {
+		case <-m.event:
+			m.refresh(log)
+		case <-m.Done:
+			log.Debug("metadata refresher done handling events")
+			return
+		}
+	}
+}
+
+// refresh refreshes the metadata of the library and publishes the changes
+func (m *MetadataRefresher) refresh(log *logrus.Entry) {
+	log.Info("refreshing the library metadata")
+
+	events, err := m.library.RefreshMetadata(m.config.Library.MetadataRefresh.Fields, log)
+	if err != nil {
+		log.Errorf("failed to refresh the library metadata: %s", err)
+		return
+	}
+
+	for _, e := range events {
+		polochon.PublishEvent(m.config.Notifiers, e)
+	}
+
+	log.WithField("changes", len(events)).Info("library metadata refreshed")
+}
@@ -1,6 +1,7 @@
 package organizer
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 
@@ -8,6 +9,7 @@ import (
 	polochon "github.com/odwrtw/polochon/lib"
 	"github.com/odwrtw/polochon/lib/configuration"
 	"github.com/odwrtw/polochon/lib/library"
+	"github.com/odwrtw/polochon/lib/unmatched"
 	"github.com/sirupsen/logrus"
 )
 
@@ -107,14 +109,14 @@ func (o *Organizer) organize(filePath string, log *logrus.Entry) error {
 	if fileInfo.IsDir() {
 		err = o.organizeFolder(filePath, log)
 	} else {
-		err = o.organizeFile(filePath, log)
+		err = o.OrganizeFile(filePath, log)
 	}
 
 	return err
 }
 
 // OrganizeFile stores the videos in the video library
-func (o *Organizer) organizeFile(filePath string, log *logrus.Entry) error {
+func (o *Organizer) OrganizeFile(filePath string, log *logrus.Entry) error {
 	log = log.WithField("file_path", filePath)
 	log.Debug("organize file")
 
@@ -151,17 +153,32 @@ func (o *Organizer) organizeFile(filePath string, log *logrus.Entry) error {
 		return file.Ignore()
 	}
 
+	// Read the hint file forcing the video identification if any
+	hint, err := file.ReadHint()
+	if err != nil && !os.IsNotExist(err) {
+		log.Warnf("failed to read the hint file: %s", err)
+	}
+
 	// Guess the video inforamtion
 	video, err := file.Guess(o.config.Movie, o.config.Show, log)
-	if err != nil {
+	switch {
+	case err != nil && hint == nil:
 		if err != polochon.ErrGuessingVideo {
 			log.Error(err)
 		}
-		return file.Ignore()
-	}
-	if video == nil {
+		return o.unmatched(file, unmatched.ReasonGuessFailed, err, nil, log)
+	case err != nil:
+		log.WithField("hint", hint.String()).Info("using the hint file to identify the unguessed video")
+		video = hint.NewVideo(*file, o.config.Movie, o.config.Show)
+	case video == nil:
 		log.Error("invalid guess")
-		return file.Ignore()
+		return o.unmatched(file, unmatched.ReasonGuessFailed, errors.New("invalid guess"), nil, log)
+	case hint != nil && !hint.Matches(video):
+		log.WithField("hint", hint.String()).Info("using the hint file to identify the misguessed video")
+		video = hint.NewVideo(*file, o.config.Movie, o.config.Show)
+	case hint != nil:
+		log.WithField("hint", hint.String()).Info("using the hint file")
+		hint.Apply(video)
 	}
 
 	metadata, err := file.GuessMetadata(log)
@@ -175,7 +192,7 @@ func (o *Organizer) organizeFile(filePath string, log *logrus.Entry) error {
 		if err != polochon.ErrGettingDetails {
 			log.Error(err)
 		}
-		return file.Ignore()
+		return o.unmatched(file, unmatched.ReasonNoDetails, err, video, log)
 	}
 
 	// Get the video subtitles
@@ -186,18 +203,56 @@ func (o *Organizer) organizeFile(filePath string, log *logrus.Entry) error {
 		}
 	}
 
+	// Keep the quality of the video being replaced if any
+	previous, err := o.library.IndexedQuality(video)
+	replaced := err == nil
+
 	// Store the video
 	if err := o.library.Add(video, log); err != nil {
 		log.Error(err)
-		return file.Ignore()
+		return o.unmatched(file, unmatched.ReasonLibraryError, err, video, log)
+	}
+
+	// The hint is not needed anymore
+	if hint != nil {
+		if err := file.RemoveHint(); err != nil {
+			log.Warnf("failed to remove the hint file: %s", err)
+		}
+	}
+
+	// The file is not unmatched anymore
+	if o.config.Organizer.Unmatched != nil {
+		if err := o.config.Organizer.Unmatched.RemovePath(file.Path); err != nil {
+			log.Warnf("failed to remove the file from the unmatched queue: %s", err)
+		}
 	}
 
 	// Notify
 	o.Notify(video, log)
+	if replaced {
+		polochon.PublishVideoReplaced(o.config.Notifiers, video, previous, log)
+	}
 
 	return nil
 }
 
+// unmatched ignores a file that could not be organized and adds it to the
+// unmatched queue
+func (o *Organizer) unmatched(file *polochon.File, reason unmatched.Reason, cause error, video polochon.Video, log *logrus.Entry) error {
+	if o.config.Organizer.Unmatched != nil {
+		var guess *unmatched.Guess
+		if video != nil {
+			guess = unmatched.NewGuess(video)
+		}
+
+		if _, err := o.config.Organizer.Unmatched.Add(file.Path, reason, cause, guess); err != nil {
+			log.Warnf("failed to add the file to the unmatched queue: %s", err)
+		}
+	}
+
+	return file.Ignore()
+}
+
 // OrganizeFolder organize each file  in a folder
 func (o *Organizer) organizeFolder(folderPath string, log *logrus.Entry) error {
 	log.WithField("folder_path", folderPath).Debug("organize folder")
@@ -210,7 +265,7 @@ func (o *Organizer) organizeFolder(folderPath string, log *logrus.Entry) error {
 		}
 
 		// Organize the file
-		return o.organizeFile(filePath, log)
+		return o.OrganizeFile(filePath, log)
 	})
 
 	return err
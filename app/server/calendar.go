@@ -0,0 +1,290 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/library"
+	"github.com/sirupsen/logrus"
+)
+
+// Default calendar range
+const (
+	defaultCalendarPast   = 7 * 24 * time.Hour
+	defaultCalendarFuture = 30 * 24 * time.Hour
+)
+
+// wishedShows returns the wished shows indexed by id, an empty list is
+// returned if the wishlist can't be fetched
+func (s *Server) wishedShows(log *logrus.Entry) map[string]*polochon.WishedShow {
+	shows := map[string]*polochon.WishedShow{}
+
+	wl := polochon.NewWishlist(s.config.Wishlist, log)
+	if err := wl.Fetch(); err != nil {
+		log.Warnf("failed to fetch the wishlist: %s", err)
+		return shows
+	}
+
+	for _, ws := range wl.Shows {
+		shows[ws.ImdbID] = ws
+	}
+
+	return shows
+}
+
+// calendarShows returns the shows of the library and of the wishlist sorted
+// by id
+func (s *Server) calendarShows(log *logrus.Entry) []library.CalendarShow {
+	wished := s.wishedShows(log)
+
+	ids := slices.Collect(maps.Keys(wished))
+	for id := range s.library.ShowIDs() {
+		if _, ok := wished[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	shows := make([]library.CalendarShow, 0, len(ids))
+	for _, id := range ids {
+		shows = append(shows, library.CalendarShow{ImdbID: id, Wished: wished[id]})
+	}
+
+	return shows
+}
+
+// calendarDate parses a date of the calendar range, both RFC3339 dates and
+// days are accepted
+func calendarDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation(polochon.AiredDateLayout, value, time.Local)
+}
+
+// calendarRange reads the calendar range from the query string, the calendar
+// goes from a week ago to a month from now by default
+func calendarRange(r *http.Request) (time.Time, time.Time, error) {
+	now := time.Now()
+	from, to := now.Add(-defaultCalendarPast), now.Add(defaultCalendarFuture)
+
+	q := r.URL.Query()
+	for _, p := range []struct {
+		name  string
+		value *time.Time
+	}{
+		{name: "from", value: &from},
+		{name: "to", value: &to},
+	} {
+		v := q.Get(p.name)
+		if v == "" {
+			continue
+		}
+
+		t, err := calendarDate(v)
+		if err != nil {
+			return from, to, &Error{
+				Code:    http.StatusBadRequest,
+				Message: "invalid " + p.name + ", RFC3339 date or YYYY-MM-DD expected",
+			}
+		}
+		*p.value = t
+	}
+
+	if to.Before(from) {
+		return from, to, &Error{
+			Code:    http.StatusBadRequest,
+			Message: "invalid range, from is after to",
+		}
+	}
+
+	return from, to, nil
+}
+
+// renderCalendarError renders the errors returned while getting a calendar
+func (s *Server) renderCalendarError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, polochon.ErrCalendarModuleNotFound):
+		err = &Error{
+			Code:    http.StatusServiceUnavailable,
+			Message: "no calendar configured in your polochon",
+		}
+	case errors.Is(err, polochon.ErrCalendarNotFound),
+		errors.Is(err, polochon.ErrGettingDetails):
+		err = &Error{
+			Code:    http.StatusNotFound,
+			Message: "calendar not found",
+		}
+	}
+
+	s.renderError(w, r, err)
+}
+
+func (s *Server) calendarEpisodes(w http.ResponseWriter, r *http.Request) ([]*library.CalendarEpisode, bool) {
+	log := s.logEntry(r)
+
+	if s.config.Show.Calendar == nil {
+		s.renderCalendarError(w, r, polochon.ErrCalendarModuleNotFound)
+		return nil, false
+	}
+
+	from, to, err := calendarRange(r)
+	if err != nil {
+		s.renderError(w, r, err)
+		return nil, false
+	}
+
+	return s.library.Calendar(s.calendarShows(log), from, to, log), true
+}
+
+func (s *Server) getCalendar(w http.ResponseWriter, r *http.Request) {
+	s.logEntry(r).Infof("getting calendar")
+
+	episodes, ok := s.calendarEpisodes(w, r)
+	if !ok {
+		return
+	}
+
+	s.renderOK(w, episodes)
+}
+
+func (s *Server) getICalendar(w http.ResponseWriter, r *http.Request) {
+	s.logEntry(r).Infof("getting iCalendar")
+
+	episodes, ok := s.calendarEpisodes(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="polochon.ics"`)
+	if err := writeICalendar(w, episodes, time.Now()); err != nil {
+		s.logEntry(r).Errorf("failed to write the iCalendar: %s", err)
+	}
+}
+
+func (s *Server) getShowMissing(w http.ResponseWriter, r *http.Request) {
+	log := s.logEntry(r)
+	log.Infof("getting show missing episodes")
+
+	id := mux.Vars(r)["id"]
+	show := library.CalendarShow{ImdbID: id, Wished: s.wishedShows(log)[id]}
+
+	episodes, err := s.library.MissingEpisodes(show, log)
+	if err != nil {
+		s.renderCalendarError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, episodes)
+}
+
+func (s *Server) getMissing(w http.ResponseWriter, r *http.Request) {
+	log := s.logEntry(r)
+	log.Infof("getting missing episodes")
+
+	if s.config.Show.Calendar == nil {
+		s.renderCalendarError(w, r, polochon.ErrCalendarModuleNotFound)
+		return
+	}
+
+	episodes := []*library.CalendarEpisode{}
+	for _, show := range s.calendarShows(log) {
+		missing, err := s.library.MissingEpisodes(show, log)
+		if err != nil {
+			log.WithField("imdb_id", show.ImdbID).Warnf("failed to get missing episodes: %s", err)
+			continue
+		}
+		episodes = append(episodes, missing...)
+	}
+
+	s.renderOK(w, episodes)
+}
+
+// iCalendarEscaper escapes the text values of an iCalendar
+var iCalendarEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\n", `\n`,
+)
+
+// writeICalendarLine writes a content line of an iCalendar, the lines longer
+// than 75 octets are folded, the continuation lines start with a space and
+// hold 74 octets of the line
+func writeICalendarLine(w io.Writer, line string) error {
+	size := 75
+	for len(line) > size {
+		// Do not split an UTF-8 character
+		i := size
+		for i > 0 && line[i]&0xC0 == 0x80 {
+			i--
+		}
+
+		if _, err := io.WriteString(w, line[:i]+"\r\n "); err != nil {
+			return err
+		}
+		line = line[i:]
+		size = 74
+	}
+
+	_, err := io.WriteString(w, line+"\r\n")
+	return err
+}
+
+// writeICalendar writes the episodes as all day events of an iCalendar
+func writeICalendar(w io.Writer, episodes []*library.CalendarEpisode, now time.Time) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//polochon//calendar//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:polochon",
+	}
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, e := range episodes {
+		if e.AiredDate == nil {
+			continue
+		}
+
+		day := e.AiredDate.Format("20060102")
+		next := e.AiredDate.AddDate(0, 0, 1).Format("20060102")
+		summary := fmt.Sprintf("%s - S%02dE%02d", e.ShowTitle, e.Season, e.Episode)
+		description := "Not in the library"
+		if e.InLibrary {
+			description = "In the library"
+		}
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:%s-S%02dE%02d@polochon", e.ShowImdbID, e.Season, e.Episode),
+			"DTSTAMP:"+stamp,
+			"DTSTART;VALUE=DATE:"+day,
+			"DTEND;VALUE=DATE:"+next,
+			"SUMMARY:"+iCalendarEscaper.Replace(summary),
+			"DESCRIPTION:"+iCalendarEscaper.Replace(description),
+			"TRANSP:TRANSPARENT",
+			"END:VEVENT",
+		)
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if err := writeICalendarLine(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
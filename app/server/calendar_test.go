@@ -0,0 +1,103 @@
+package server
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/odwrtw/polochon/lib/library"
+)
+
+func TestWriteICalendar(t *testing.T) {
+	aired := time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC)
+	episodes := []*library.CalendarEpisode{
+		{
+			ShowImdbID: "tt0397306",
+			ShowTitle:  "American Dad, Stan; Roger",
+			Season:     1,
+			Episode:    2,
+			AiredDate:  &aired,
+		},
+		// Episodes without aired date are skipped
+		{ShowImdbID: "tt0397306", ShowTitle: "American Dad", Season: 1, Episode: 3},
+	}
+
+	var buf bytes.Buffer
+	now := time.Date(2026, time.October, 18, 10, 30, 0, 0, time.UTC)
+	if err := writeICalendar(&buf, episodes, now); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//polochon//calendar//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:polochon",
+		"BEGIN:VEVENT",
+		"UID:tt0397306-S01E02@polochon",
+		"DTSTAMP:20261018T103000Z",
+		"DTSTART;VALUE=DATE:20261017",
+		"DTEND;VALUE=DATE:20261018",
+		`SUMMARY:American Dad\, Stan\; Roger - S01E02`,
+		"DESCRIPTION:Not in the library",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	if got := buf.String(); got != expected {
+		t.Errorf("expected\n%q\ngot\n%q", expected, got)
+	}
+}
+
+func TestWriteICalendarLineFolding(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("é", 50)
+
+	var buf bytes.Buffer
+	if err := writeICalendarLine(&buf, line); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %q", lines)
+	}
+
+	for _, l := range lines {
+		if len(l) > 75 {
+			t.Errorf("line longer than 75 octets: %q", l)
+		}
+	}
+
+	if !strings.HasPrefix(lines[1], " ") {
+		t.Errorf("expected the folded line to start with a space, got %q", lines[1])
+	}
+
+	if got := lines[0] + lines[1][1:]; got != line {
+		t.Errorf("expected unfolded line %q, got %q", line, got)
+	}
+}
+
+func TestWriteICalendarLineContinuation(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("a", 200)
+
+	var buf bytes.Buffer
+	if err := writeICalendarLine(&buf, line); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	expected := []int{75, 75, 64}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %q", len(expected), lines)
+	}
+
+	for i, l := range lines {
+		if len(l) != expected[i] {
+			t.Errorf("expected line %d to be %d octets, got %d", i, expected[i], len(l))
+		}
+	}
+}
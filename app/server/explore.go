@@ -0,0 +1,153 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/sirupsen/logrus"
+)
+
+// movieResult represents a movie returned by a searcher or an explorer
+type movieResult struct {
+	*polochon.Movie
+	InLibrary bool `json:"in_library"`
+}
+
+// showResult represents a show returned by a searcher or an explorer
+type showResult struct {
+	*polochon.Show
+	InLibrary bool `json:"in_library"`
+}
+
+func (s *Server) movieResults(movies []*polochon.Movie, log *logrus.Entry) []*movieResult {
+	out := make([]*movieResult, 0, len(movies))
+	for _, m := range movies {
+		ok, err := s.library.HasMovie(m.ImdbID)
+		if err != nil {
+			log.WithField("imdb_id", m.ImdbID).Warn(err)
+		}
+
+		out = append(out, &movieResult{Movie: m, InLibrary: ok})
+	}
+
+	return out
+}
+
+func (s *Server) showResults(shows []*polochon.Show, log *logrus.Entry) []*showResult {
+	out := make([]*showResult, 0, len(shows))
+	for _, show := range shows {
+		ok, err := s.library.HasShow(show.ImdbID)
+		if err != nil {
+			log.WithField("imdb_id", show.ImdbID).Warn(err)
+		}
+
+		out = append(out, &showResult{Show: show, InLibrary: ok})
+	}
+
+	return out
+}
+
+// renderModuleError renders the errors returned when no module can handle the
+// request
+func (s *Server) renderModuleError(w http.ResponseWriter, r *http.Request, err error) {
+	switch err {
+	case polochon.ErrNoSearcher, polochon.ErrNoExplorer:
+		s.renderError(w, r, &Error{
+			Code:    http.StatusNotFound,
+			Message: err.Error(),
+		})
+	default:
+		s.renderError(w, r, err)
+	}
+}
+
+func searchQuery(r *http.Request) (string, error) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		return "", &Error{
+			Code:    http.StatusBadRequest,
+			Message: "missing search query",
+		}
+	}
+
+	return q, nil
+}
+
+func (s *Server) searchMovies(w http.ResponseWriter, r *http.Request) {
+	log := s.logEntry(r)
+
+	q, err := searchQuery(r)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	log.Infof("searching movies with %q", q)
+
+	movies, err := polochon.SearchMovies(s.config.Movie.Searchers, q, log)
+	if err != nil {
+		s.renderModuleError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, s.movieResults(movies, log))
+}
+
+func (s *Server) searchShows(w http.ResponseWriter, r *http.Request) {
+	log := s.logEntry(r)
+
+	q, err := searchQuery(r)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	log.Infof("searching shows with %q", q)
+
+	shows, err := polochon.SearchShows(s.config.Show.Searchers, q, log)
+	if err != nil {
+		s.renderModuleError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, s.showResults(shows, log))
+}
+
+func (s *Server) movieExploreOptions(w http.ResponseWriter, r *http.Request) {
+	s.logEntry(r).Infof("listing movie explore options")
+	s.renderOK(w, polochon.ExploreOptions(s.config.Movie.Explorers, polochon.TypeMovie))
+}
+
+func (s *Server) showExploreOptions(w http.ResponseWriter, r *http.Request) {
+	s.logEntry(r).Infof("listing show explore options")
+	s.renderOK(w, polochon.ExploreOptions(s.config.Show.Explorers, polochon.TypeEpisode))
+}
+
+func (s *Server) exploreMovies(w http.ResponseWriter, r *http.Request) {
+	log := s.logEntry(r)
+	option := mux.Vars(r)["option"]
+	log.Infof("exploring movies by %q", option)
+
+	movies, err := polochon.ExploreMovies(s.config.Movie.Explorers, option, log)
+	if err != nil {
+		s.renderModuleError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, s.movieResults(movies, log))
+}
+
+func (s *Server) exploreShows(w http.ResponseWriter, r *http.Request) {
+	log := s.logEntry(r)
+	option := mux.Vars(r)["option"]
+	log.Infof("exploring shows by %q", option)
+
+	shows, err := polochon.ExploreShows(s.config.Show.Explorers, option, log)
+	if err != nil {
+		s.renderModuleError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, s.showResults(shows, log))
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/history"
+	"github.com/sirupsen/logrus"
+)
+
+// historyStore returns the download history store if configured
+func (s *Server) historyStore() (*history.Store, error) {
+	if s.config.Downloader.History == nil {
+		return nil, &Error{
+			Code:    http.StatusServiceUnavailable,
+			Message: "download history not enabled in your polochon",
+		}
+	}
+
+	return s.config.Downloader.History, nil
+}
+
+// recordGrab records a torrent added through the API in the history
+func (s *Server) recordGrab(torrent *polochon.Torrent, log *logrus.Entry) {
+	entry := history.NewEntry(torrent, history.OutcomeGrabbed)
+	if err := s.config.Downloader.History.Record(entry); err != nil {
+		log.Errorf("failed to record the download history: %q", err)
+	}
+}
+
+// historyFilter reads the history filter from the query string
+func historyFilter(r *http.Request) (*history.Filter, error) {
+	q := r.URL.Query()
+	filter := &history.Filter{
+		ImdbID:  q.Get("imdb_id"),
+		Type:    polochon.VideoType(q.Get("type")),
+		Outcome: history.Outcome(q.Get("outcome")),
+	}
+
+	for _, f := range []struct {
+		name  string
+		value *int
+	}{
+		{name: "season", value: &filter.Season},
+		{name: "episode", value: &filter.Episode},
+		{name: "limit", value: &filter.Limit},
+	} {
+		v := q.Get(f.name)
+		if v == "" {
+			continue
+		}
+
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 0 {
+			return nil, &Error{
+				Code:    http.StatusBadRequest,
+				Message: "invalid " + f.name,
+			}
+		}
+		*f.value = i
+	}
+
+	if since := q.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return nil, &Error{
+				Code:    http.StatusBadRequest,
+				Message: "invalid since, RFC3339 date expected",
+			}
+		}
+		filter.Since = t
+	}
+
+	return filter, nil
+}
+
+func (s *Server) getHistory(w http.ResponseWriter, r *http.Request) {
+	s.logEntry(r).Infof("getting download history")
+
+	store, err := s.historyStore()
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	filter, err := historyFilter(r)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, store.Entries(filter))
+}
+
+func (s *Server) getBlocklist(w http.ResponseWriter, r *http.Request) {
+	s.logEntry(r).Infof("getting release blocklist")
+
+	store, err := s.historyStore()
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, store.Blocklist())
+}
+
+func (s *Server) blockRelease(w http.ResponseWriter, r *http.Request) {
+	s.logEntry(r).Infof("blocking release")
+
+	store, err := s.historyStore()
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	release := &history.BlockedRelease{}
+	if err := json.NewDecoder(r.Body).Decode(release); err != nil {
+		s.renderError(w, r, &Error{
+			Code:    http.StatusBadRequest,
+			Message: "Unable to read payload",
+		})
+		return
+	}
+
+	switch err := store.Block(release); err {
+	case nil:
+		s.renderOK(w, release)
+	case history.ErrMissingRelease:
+		s.renderError(w, r, &Error{Code: http.StatusBadRequest, Message: err.Error()})
+	case history.ErrAlreadyBlocked:
+		s.renderError(w, r, &Error{Code: http.StatusConflict, Message: err.Error()})
+	default:
+		s.renderError(w, r, err)
+	}
+}
+
+func (s *Server) unblockRelease(w http.ResponseWriter, r *http.Request) {
+	s.logEntry(r).Infof("unblocking release")
+
+	store, err := s.historyStore()
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		s.renderError(w, r, &Error{Code: http.StatusBadRequest, Message: "invalid id"})
+		return
+	}
+
+	switch err := store.Unblock(id); err {
+	case nil:
+		s.renderOK(w, nil)
+	case history.ErrNotFound:
+		s.renderError(w, r, &Error{Code: http.StatusNotFound, Message: err.Error()})
+	default:
+		s.renderError(w, r, err)
+	}
+}
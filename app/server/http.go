@@ -27,25 +27,25 @@ const AppName = "http_server"
 type Server struct {
 	*subapp.Base
 
-	config          *configuration.Config
-	library         *library.Library
-	authManager     *auth.Manager
-	gracefulServer  *http.Server
-	shutdownCancel  context.CancelFunc
-	hub    *sseHub
-	log    *logrus.Entry
-	render *render.Render
+	config         *configuration.Config
+	library        *library.Library
+	authManager    *auth.Manager
+	gracefulServer *http.Server
+	shutdownCancel context.CancelFunc
+	hub            *sseHub
+	log            *logrus.Entry
+	render         *render.Render
 }
 
 // New returns a new server
 func New(config *configuration.Config, vs *library.Library, auth *auth.Manager) *Server {
 	return &Server{
-		Base:          subapp.NewBase(AppName),
-		config:        config,
-		library:       vs,
-		authManager:   auth,
-		hub:    newSSEHub(),
-		render: render.New(),
+		Base:        subapp.NewBase(AppName),
+		config:      config,
+		library:     vs,
+		authManager: auth,
+		hub:         newSSEHub(),
+		render:      render.New(),
 	}
 }
 
@@ -88,20 +88,6 @@ func (s *Server) Hub() polochon.Notifier {
 	return s.hub
 }
 
-func (s *Server) wishlist(w http.ResponseWriter, r *http.Request) {
-	log := s.logEntry(r)
-	log.Infof("getting wishlist")
-
-	wl := polochon.NewWishlist(s.config.Wishlist, log)
-
-	if err := wl.Fetch(); err != nil {
-		s.renderError(w, r, err)
-		return
-	}
-
-	s.renderOK(w, wl)
-}
-
 func (s *Server) serveFile(w http.ResponseWriter, r *http.Request, file *polochon.File) {
 	if file == nil || file.Size == 0 {
 		s.renderError(w, r, index.ErrNotFound)
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/library"
+)
+
+// renderIdentifyError renders the errors returned while identifying a video
+func (s *Server) renderIdentifyError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, library.ErrMovieAlreadyExists),
+		errors.Is(err, library.ErrShowEpisodeAlreadyExists),
+		errors.Is(err, library.ErrReorganizeConflict):
+		err = &Error{
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+		}
+	case errors.Is(err, polochon.ErrGettingDetails):
+		err = &Error{
+			Code:    http.StatusNotFound,
+			Message: "unable to get the video details",
+		}
+	}
+
+	s.renderError(w, r, err)
+}
+
+func (s *Server) identifyMovie(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)["id"]
+	log := s.logEntry(req)
+
+	var payload struct {
+		ImdbID string `json:"imdb_id"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil || payload.ImdbID == "" {
+		s.renderError(w, req, &Error{
+			Code:    http.StatusBadRequest,
+			Message: "Unable to read payload",
+		})
+		return
+	}
+
+	log.WithField("new_imdb_id", payload.ImdbID).Infof("identifying movie")
+
+	m, events, err := s.library.IdentifyMovie(id, payload.ImdbID, log)
+	if err != nil {
+		s.renderIdentifyError(w, req, err)
+		return
+	}
+	s.publishEvents(events)
+
+	idxMovie, err := s.library.GetIndexedMovie(m.ImdbID)
+	if err != nil {
+		s.renderError(w, req, err)
+		return
+	}
+
+	s.renderOK(w, idxMovie)
+}
+
+func (s *Server) identifyShowEpisode(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	log := s.logEntry(req)
+
+	season, err := strconv.Atoi(vars["season"])
+	if err != nil {
+		s.renderError(w, req, &Error{Code: http.StatusBadRequest, Message: "invalid season"})
+		return
+	}
+
+	episode, err := strconv.Atoi(vars["episode"])
+	if err != nil {
+		s.renderError(w, req, &Error{Code: http.StatusBadRequest, Message: "invalid episode"})
+		return
+	}
+
+	identity := library.EpisodeIdentity{}
+	if err := json.NewDecoder(req.Body).Decode(&identity); err != nil ||
+		(identity.Season != nil && *identity.Season < 0) ||
+		(identity.Episode != nil && *identity.Episode < 0) {
+		s.renderError(w, req, &Error{
+			Code:    http.StatusBadRequest,
+			Message: "Unable to read payload",
+		})
+		return
+	}
+
+	log.Infof("identifying episode")
+
+	e, events, err := s.library.IdentifyShowEpisode(vars["id"], season, episode, identity, log)
+	if err != nil {
+		s.renderIdentifyError(w, req, err)
+		return
+	}
+	s.publishEvents(events)
+
+	idxEpisode, err := s.library.GetIndexedEpisode(e.ShowImdbID, e.Season, e.Episode)
+	if err != nil {
+		s.renderError(w, req, err)
+		return
+	}
+
+	s.renderOK(w, idxEpisode)
+}
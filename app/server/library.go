@@ -1,19 +1,100 @@
 package server
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+
+	polochon "github.com/odwrtw/polochon/lib"
 )
 
 func (s *Server) libraryRefresh(w http.ResponseWriter, req *http.Request) {
 	log := s.logEntry(req)
 	log.Infof("refreshing library")
 
-	if err := s.library.RebuildIndex(log); err != nil {
-		log.WithField("function", "rebuild_index").Error(err)
+	changes, err := s.library.RefreshIndex(log)
+	if err != nil {
+		log.WithField("function", "refresh_index").Error(err)
+		s.renderError(w, req, err)
+		return
+	}
+
+	s.hub.broadcast(polochon.EventLibraryRefreshed)
+	s.renderOK(w, changes)
+}
+
+func (s *Server) libraryReorganize(w http.ResponseWriter, req *http.Request) {
+	log := s.logEntry(req)
+
+	dryRun := false
+	if v := req.URL.Query().Get("dry_run"); v != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(v)
+		if err != nil {
+			s.renderError(w, req, &Error{
+				Code:    http.StatusBadRequest,
+				Message: "invalid dry_run",
+			})
+			return
+		}
+	}
+
+	log.WithField("dry_run", dryRun).Infof("reorganizing library")
+
+	result, err := s.library.Reorganize(dryRun, log)
+	if err != nil {
+		log.WithField("function", "reorganize").Error(err)
 		s.renderError(w, req, err)
 		return
 	}
 
-	s.hub.broadcast()
-	s.renderOK(w, nil)
+	if !dryRun {
+		s.hub.broadcast(polochon.EventLibraryRefreshed)
+	}
+
+	s.renderOK(w, result)
+}
+
+// metadataFields returns the detail fields asked in the query string, every
+// field is refreshed if none is given
+func metadataFields(r *http.Request) ([]string, error) {
+	q := r.URL.Query().Get("fields")
+	if q == "" {
+		return nil, nil
+	}
+
+	fields := []string{}
+	for f := range strings.SplitSeq(q, ",") {
+		f = strings.TrimSpace(f)
+		if !polochon.IsDetailField(f) {
+			return nil, &Error{
+				Code:    http.StatusBadRequest,
+				Message: "invalid field " + f,
+			}
+		}
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// publishEvents publishes the changes made to the library
+func (s *Server) publishEvents(events []*polochon.Event) {
+	for _, e := range events {
+		polochon.PublishEvent(s.config.Notifiers, e)
+	}
+}
+
+// renderMetadataError renders the errors returned while refreshing the
+// metadata
+func (s *Server) renderMetadataError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, polochon.ErrGettingDetails) {
+		err = &Error{
+			Code:    http.StatusNotFound,
+			Message: "unable to get the video details",
+		}
+	}
+
+	s.renderError(w, r, err)
 }
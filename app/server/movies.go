@@ -7,11 +7,27 @@ import (
 	"github.com/gorilla/mux"
 	polochon "github.com/odwrtw/polochon/lib"
 	index "github.com/odwrtw/polochon/lib/media_index"
+	"github.com/odwrtw/polochon/lib/progress"
 )
 
 func (s *Server) movieIndex(w http.ResponseWriter, req *http.Request) {
 	s.logEntry(req).Infof("listing movie index")
-	s.renderOK(w, s.library.MovieIndex())
+
+	type formatedMovie struct {
+		*index.Movie
+		Progress *progress.Progress `json:"progress,omitempty"`
+	}
+
+	userProgress := s.config.Library.Progress.User(progressUser(req))
+	ret := map[string]formatedMovie{}
+	for id, movie := range s.library.MovieIndex() {
+		ret[id] = formatedMovie{
+			Movie:    movie,
+			Progress: userProgress[polochon.VideoKey(id, 0, 0)],
+		}
+	}
+
+	s.renderOK(w, ret)
 }
 
 // TODO: handle this in a middleware
@@ -102,6 +118,34 @@ func (s *Server) deleteMovie(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	s.hub.broadcast()
+	s.hub.publish(polochon.NewVideoDeletedEvent(m))
 	s.renderOK(w, nil)
 }
+
+func (s *Server) refreshMovieMetadata(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)["id"]
+	log := s.logEntry(req)
+
+	fields, err := metadataFields(req)
+	if err != nil {
+		s.renderError(w, req, err)
+		return
+	}
+
+	log.WithField("fields", fields).Infof("refreshing movie metadata")
+
+	events, err := s.library.RefreshMovieMetadata(id, fields, log)
+	if err != nil {
+		s.renderMetadataError(w, req, err)
+		return
+	}
+	s.publishEvents(events)
+
+	idxMovie, err := s.library.GetIndexedMovie(id)
+	if err != nil {
+		s.renderError(w, req, err)
+		return
+	}
+
+	s.renderOK(w, idxMovie)
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/odwrtw/polochon/app/auth"
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/progress"
+)
+
+// progressUser returns the user of the progress, the requests made without
+// authentication share the default user
+func progressUser(r *http.Request) string {
+	tokenName, ok := r.Context().Value(auth.TokenName).(string)
+	if !ok || tokenName == "" {
+		return progress.DefaultUser
+	}
+
+	return tokenName
+}
+
+// progressStore returns the progress store if configured
+func (s *Server) progressStore() (*progress.Store, error) {
+	if s.config.Library.Progress == nil {
+		return nil, &Error{
+			Code:    http.StatusServiceUnavailable,
+			Message: "progress not enabled in your polochon",
+		}
+	}
+
+	return s.config.Library.Progress, nil
+}
+
+// renderProgress renders the progress of the user on a video
+func (s *Server) renderProgress(w http.ResponseWriter, req *http.Request, key string) {
+	store, err := s.progressStore()
+	if err != nil {
+		s.renderError(w, req, err)
+		return
+	}
+
+	p := store.Get(progressUser(req), key)
+	if p == nil {
+		p = &progress.Progress{}
+	}
+
+	s.renderOK(w, p)
+}
+
+// setProgress sets the progress of the user on a video from the payload
+func (s *Server) setProgress(w http.ResponseWriter, req *http.Request, key string) {
+	store, err := s.progressStore()
+	if err != nil {
+		s.renderError(w, req, err)
+		return
+	}
+
+	var payload progress.Progress
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		s.renderError(w, req, &Error{
+			Code:    http.StatusBadRequest,
+			Message: "Unable to read payload",
+		})
+		return
+	}
+
+	user := progressUser(req)
+	s.logEntry(req).WithField("key", key).Infof("setting progress")
+
+	p, err := store.Set(user, key, payload)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if payload.Validate() != nil {
+			code = http.StatusBadRequest
+		}
+
+		s.renderError(w, req, &Error{
+			Code:    code,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.renderOK(w, p)
+}
+
+func (s *Server) getMovieProgress(w http.ResponseWriter, req *http.Request) {
+	m := s.getMovie(w, req)
+	if m == nil {
+		return
+	}
+
+	s.renderProgress(w, req, polochon.VideoKey(m.ImdbID, 0, 0))
+}
+
+func (s *Server) setMovieProgress(w http.ResponseWriter, req *http.Request) {
+	m := s.getMovie(w, req)
+	if m == nil {
+		return
+	}
+
+	s.setProgress(w, req, polochon.VideoKey(m.ImdbID, 0, 0))
+}
+
+func (s *Server) getEpisodeProgress(w http.ResponseWriter, req *http.Request) {
+	e := s.getEpisode(w, req)
+	if e == nil {
+		return
+	}
+
+	s.renderProgress(w, req, polochon.VideoKey(e.ShowImdbID, e.Season, e.Episode))
+}
+
+func (s *Server) setEpisodeProgress(w http.ResponseWriter, req *http.Request) {
+	e := s.getEpisode(w, req)
+	if e == nil {
+		return
+	}
+
+	s.setProgress(w, req, polochon.VideoKey(e.ShowImdbID, e.Season, e.Episode))
+}
@@ -46,6 +46,36 @@ func (s *Server) httpServer(log *logrus.Entry) *http.Server {
 			methods: "DELETE",
 			handler: s.deleteMovie,
 		},
+		{
+			path:    "/movies/{id}/progress",
+			methods: "GET",
+			handler: s.getMovieProgress,
+		},
+		{
+			path:    "/movies/{id}/progress",
+			methods: "PUT",
+			handler: s.setMovieProgress,
+		},
+		{
+			path:    "/movies/{id}/identify",
+			methods: "POST",
+			handler: s.identifyMovie,
+		},
+		{
+			path:    "/movies/{id}/refresh",
+			methods: "POST",
+			handler: s.refreshMovieMetadata,
+		},
+		{
+			path:    "/movies/{id}/torrents",
+			methods: "GET",
+			handler: s.movieTorrentCandidates,
+		},
+		{
+			path:    "/movies/{id}/torrents",
+			methods: "POST",
+			handler: s.downloadMovieTorrent,
+		},
 		{
 			path:     "/movies/{id}/download",
 			methods:  "GET",
@@ -111,6 +141,16 @@ func (s *Server) httpServer(log *logrus.Entry) *http.Server {
 			methods: "DELETE",
 			handler: s.deleteShow,
 		},
+		{
+			path:    "/shows/{id}/refresh",
+			methods: "POST",
+			handler: s.refreshShowMetadata,
+		},
+		{
+			path:    "/shows/{id}/missing",
+			methods: "GET",
+			handler: s.getShowMissing,
+		},
 		{
 			path:    "/shows/{id}/files/{name}",
 			methods: "GET",
@@ -136,6 +176,31 @@ func (s *Server) httpServer(log *logrus.Entry) *http.Server {
 			methods: "DELETE",
 			handler: s.deleteEpisode,
 		},
+		{
+			path:    "/shows/{id}/seasons/{season:[0-9]+}/episodes/{episode:[0-9]+}/progress",
+			methods: "GET",
+			handler: s.getEpisodeProgress,
+		},
+		{
+			path:    "/shows/{id}/seasons/{season:[0-9]+}/episodes/{episode:[0-9]+}/progress",
+			methods: "PUT",
+			handler: s.setEpisodeProgress,
+		},
+		{
+			path:    "/shows/{id}/seasons/{season:[0-9]+}/episodes/{episode:[0-9]+}/identify",
+			methods: "POST",
+			handler: s.identifyShowEpisode,
+		},
+		{
+			path:    "/shows/{id}/seasons/{season:[0-9]+}/episodes/{episode:[0-9]+}/torrents",
+			methods: "GET",
+			handler: s.episodeTorrentCandidates,
+		},
+		{
+			path:    "/shows/{id}/seasons/{season:[0-9]+}/episodes/{episode:[0-9]+}/torrents",
+			methods: "POST",
+			handler: s.downloadEpisodeTorrent,
+		},
 		{
 			path:    "/shows/{id}/seasons/{season:[0-9]+}/episodes/{episode:[0-9]+}/subtitles/{lang}",
 			methods: "POST",
@@ -195,6 +260,56 @@ func (s *Server) httpServer(log *logrus.Entry) *http.Server {
 			methods: "GET",
 			handler: s.wishlist,
 		},
+		{
+			path:    "/wishlist/movies",
+			methods: "POST",
+			handler: s.addWishedMovie,
+		},
+		{
+			path:    "/wishlist/movies/{id}",
+			methods: "DELETE",
+			handler: s.deleteWishedMovie,
+		},
+		{
+			path:    "/wishlist/shows",
+			methods: "POST",
+			handler: s.addWishedShow,
+		},
+		{
+			path:    "/wishlist/shows/{id}",
+			methods: "DELETE",
+			handler: s.deleteWishedShow,
+		},
+		{
+			path:    "/search/movies",
+			methods: "GET",
+			handler: s.searchMovies,
+		},
+		{
+			path:    "/search/shows",
+			methods: "GET",
+			handler: s.searchShows,
+		},
+		{
+			path:    "/explore/movies",
+			methods: "GET",
+			handler: s.movieExploreOptions,
+		},
+		{
+			path:    "/explore/movies/{option}",
+			methods: "GET",
+			handler: s.exploreMovies,
+		},
+		{
+			path:    "/explore/shows",
+			methods: "GET",
+			handler: s.showExploreOptions,
+		},
+		{
+			path:    "/explore/shows/{option}",
+			methods: "GET",
+			handler: s.exploreShows,
+		},
 		{
 			path:    "/torrents",
 			methods: "POST",
@@ -210,11 +325,71 @@ func (s *Server) httpServer(log *logrus.Entry) *http.Server {
 			methods: "DELETE",
 			handler: s.removeTorrent,
 		},
+		{
+			path:    "/history",
+			methods: "GET",
+			handler: s.getHistory,
+		},
+		{
+			path:    "/history/blocklist",
+			methods: "GET",
+			handler: s.getBlocklist,
+		},
+		{
+			path:    "/history/blocklist",
+			methods: "POST",
+			handler: s.blockRelease,
+		},
+		{
+			path:    "/history/blocklist/{id:[0-9]+}",
+			methods: "DELETE",
+			handler: s.unblockRelease,
+		},
+		{
+			path:    "/calendar",
+			methods: "GET",
+			handler: s.getCalendar,
+		},
+		{
+			path:    "/calendar.ics",
+			methods: "GET",
+			handler: s.getICalendar,
+		},
+		{
+			path:    "/missing",
+			methods: "GET",
+			handler: s.getMissing,
+		},
+		{
+			path:    "/unmatched",
+			methods: "GET",
+			handler: s.getUnmatched,
+		},
+		{
+			path:    "/unmatched/{id:[0-9]+}",
+			methods: "DELETE",
+			handler: s.deleteUnmatched,
+		},
+		{
+			path:    "/unmatched/{id:[0-9]+}/retry",
+			methods: "POST",
+			handler: s.retryUnmatched,
+		},
+		{
+			path:    "/unmatched/{id:[0-9]+}/assign",
+			methods: "POST",
+			handler: s.assignUnmatched,
+		},
 		{
 			path:    "/library/refresh",
 			methods: "POST",
 			handler: s.libraryRefresh,
 		},
+		{
+			path:    "/library/reorganize",
+			methods: "POST",
+			handler: s.libraryReorganize,
+		},
 		{
 			path:    "/modules/status",
 			methods: "GET",
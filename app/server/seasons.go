@@ -98,6 +98,10 @@ func (s *Server) deleteSeason(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	s.hub.broadcast()
+	e := polochon.NewEvent(polochon.EventSeasonDeleted)
+	e.ImdbID = vars["id"]
+	e.Season = seasonNum
+	s.hub.publish(e)
+
 	s.renderOK(w, nil)
 }
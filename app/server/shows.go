@@ -9,21 +9,31 @@ import (
 	"github.com/gorilla/mux"
 	polochon "github.com/odwrtw/polochon/lib"
 	index "github.com/odwrtw/polochon/lib/media_index"
+	"github.com/odwrtw/polochon/lib/progress"
 )
 
+// formatedEpisode is an indexed episode along with the progress of the user
+type formatedEpisode struct {
+	*index.Episode
+	Progress *progress.Progress `json:"progress,omitempty"`
+}
+
 // Format seasons to get a pretty marshal
-func formatSeasons(show *index.Show) map[string]map[string]*index.Episode {
-	ret := map[string]map[string]*index.Episode{}
+func formatSeasons(id string, show *index.Show, userProgress map[string]*progress.Progress) map[string]map[string]formatedEpisode {
+	ret := map[string]map[string]formatedEpisode{}
 	for seasonNum, season := range show.Seasons {
 		s := fmt.Sprintf("%02d", seasonNum)
 		for episodeNb, episode := range season.Episodes {
 			e := fmt.Sprintf("%02d", episodeNb)
 
 			if _, ok := ret[s]; !ok {
-				ret[s] = map[string]*index.Episode{}
+				ret[s] = map[string]formatedEpisode{}
 			}
 
-			ret[s][e] = episode
+			ret[s][e] = formatedEpisode{
+				Episode:  episode,
+				Progress: userProgress[polochon.VideoKey(id, seasonNum, episodeNb)],
+			}
 		}
 	}
 	return ret
@@ -34,13 +44,15 @@ func (s *Server) showIds(w http.ResponseWriter, req *http.Request) {
 
 	type formatedShow struct {
 		*index.Show
-		Seasons map[string]map[string]*index.Episode `json:"seasons"`
+		Seasons map[string]map[string]formatedEpisode `json:"seasons"`
 	}
+
+	userProgress := s.config.Library.Progress.User(progressUser(req))
 	ret := map[string]formatedShow{}
 	for id, show := range s.library.ShowIDs() {
 		ret[id] = formatedShow{
 			Show:    show,
-			Seasons: formatSeasons(show),
+			Seasons: formatSeasons(id, show, userProgress),
 		}
 	}
 
@@ -86,10 +98,10 @@ func (s *Server) getShowDetails(w http.ResponseWriter, req *http.Request) {
 
 	out := struct {
 		*index.Show
-		Seasons map[string]map[string]*index.Episode `json:"seasons"`
+		Seasons map[string]map[string]formatedEpisode `json:"seasons"`
 	}{
 		indexedShow,
-		formatSeasons(indexedShow),
+		formatSeasons(vars["id"], indexedShow, s.config.Library.Progress.User(progressUser(req))),
 	}
 
 	s.renderOK(w, out)
@@ -105,7 +117,10 @@ func (s *Server) deleteShow(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	s.hub.broadcast()
+	e := polochon.NewEvent(polochon.EventShowDeleted)
+	e.ImdbID = vars["id"]
+	s.hub.publish(e)
+
 	s.renderOK(w, nil)
 }
 
@@ -153,7 +168,7 @@ func (s *Server) deleteEpisode(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	s.hub.broadcast()
+	s.hub.publish(polochon.NewVideoDeletedEvent(e))
 	s.renderOK(w, nil)
 }
 
@@ -165,3 +180,31 @@ func (s *Server) serveEpisode(w http.ResponseWriter, req *http.Request) {
 
 	s.serveFile(w, req, e.GetFile())
 }
+
+func (s *Server) refreshShowMetadata(w http.ResponseWriter, req *http.Request) {
+	id := mux.Vars(req)["id"]
+	log := s.logEntry(req)
+
+	fields, err := metadataFields(req)
+	if err != nil {
+		s.renderError(w, req, err)
+		return
+	}
+
+	log.WithField("fields", fields).Infof("refreshing show metadata")
+
+	events, err := s.library.RefreshShowMetadata(id, fields, log)
+	if err != nil {
+		s.renderMetadataError(w, req, err)
+		return
+	}
+	s.publishEvents(events)
+
+	show, err := s.library.GetIndexedShow(id)
+	if err != nil {
+		s.renderError(w, req, err)
+		return
+	}
+
+	s.renderOK(w, show)
+}
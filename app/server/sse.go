@@ -1,8 +1,11 @@
 package server
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -10,19 +13,36 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-const sseModuleName = "sse"
+const (
+	sseModuleName = "sse"
+	// sseHistorySize is the number of events kept to be replayed to the
+	// clients reconnecting
+	sseHistorySize = 512
+	// sseClientBufferSize is the number of events buffered for each client,
+	// slow clients are disconnected when their buffer is full
+	sseClientBufferSize = 64
+)
 
-// Compile-time assertion.
-var _ polochon.Notifier = (*sseHub)(nil)
+// Compile-time assertions.
+var (
+	_ polochon.Notifier      = (*sseHub)(nil)
+	_ polochon.EventListener = (*sseHub)(nil)
+)
 
 type sseHub struct {
 	mu      sync.Mutex
-	clients map[chan struct{}]struct{}
+	lastID  uint64
+	history []*polochon.Event
+	clients map[chan *polochon.Event]struct{}
 }
 
 func newSSEHub() *sseHub {
 	return &sseHub{
-		clients: make(map[chan struct{}]struct{}),
+		// Start the IDs from the current time so that the IDs of a restarted
+		// server are greater than the ones known by the clients
+		lastID:  uint64(time.Now().UnixMilli()),
+		history: make([]*polochon.Event, 0, sseHistorySize),
+		clients: make(map[chan *polochon.Event]struct{}),
 	}
 }
 
@@ -31,37 +51,114 @@ func (h *sseHub) Init(_ []byte) error                    { return nil }
 func (h *sseHub) Name() string                           { return sseModuleName }
 func (h *sseHub) Status() (polochon.ModuleStatus, error) { return polochon.StatusOK, nil }
 
-// Notifier interface.
-func (h *sseHub) Notify(_ any, _ *logrus.Entry) error {
-	h.broadcast()
+// Notifier interface, the notifiers are called with the videos added to the
+// library.
+func (h *sseHub) Notify(i any, _ *logrus.Entry) error {
+	switch v := i.(type) {
+	case *polochon.Event:
+		h.publish(v)
+	case polochon.Video:
+		h.publish(polochon.NewVideoAddedEvent(v))
+	}
 	return nil
 }
 
-func (h *sseHub) subscribe() chan struct{} {
-	ch := make(chan struct{}, 1)
+// OnEvent implements the EventListener interface
+func (h *sseHub) OnEvent(e *polochon.Event) {
+	h.publish(e)
+}
+
+// subscribe registers a new client, the events published after lastID are
+// returned to be replayed. If some of the events are not in the history
+// anymore, the missed return value is true.
+func (h *sseHub) subscribe(lastID uint64) (ch chan *polochon.Event, replay []*polochon.Event, missed bool) {
+	ch = make(chan *polochon.Event, sseClientBufferSize)
+
 	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	h.clients[ch] = struct{}{}
-	h.mu.Unlock()
-	return ch
+
+	switch {
+	case lastID == 0 || lastID == h.lastID:
+		return ch, nil, false
+	case lastID > h.lastID:
+		// Unknown event ID
+		return ch, nil, true
+	}
+
+	for _, e := range h.history {
+		if e.ID > lastID {
+			replay = append(replay, e)
+		}
+	}
+
+	missed = len(replay) == 0 || replay[0].ID != lastID+1
+	return ch, replay, missed
 }
 
-func (h *sseHub) unsubscribe(ch chan struct{}) {
+func (h *sseHub) unsubscribe(ch chan *polochon.Event) {
 	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// The channel may have already been closed if the client was too slow
+	if _, ok := h.clients[ch]; !ok {
+		return
+	}
+
 	delete(h.clients, ch)
-	h.mu.Unlock()
 	close(ch)
 }
 
-func (h *sseHub) broadcast() {
+// publish assigns an ID to the event, stores it in the history and sends it
+// to all the clients
+func (h *sseHub) publish(e *polochon.Event) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+
+	h.lastID++
+	e.ID = h.lastID
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	if len(h.history) == sseHistorySize {
+		copy(h.history, h.history[1:])
+		h.history = h.history[:sseHistorySize-1]
+	}
+	h.history = append(h.history, e)
+
 	for ch := range h.clients {
-		// Non-blocking send to coalesce duplicate events.
 		select {
-		case ch <- struct{}{}:
+		case ch <- e:
 		default:
+			// The client is too slow, disconnect it so that it reconnects
+			// and gets the missed events replayed
+			delete(h.clients, ch)
+			close(ch)
+		}
+	}
+}
+
+// broadcast publishes a new event of type t
+func (h *sseHub) broadcast(t polochon.EventType) {
+	h.publish(polochon.NewEvent(t))
+}
+
+func writeEvent(w io.Writer, e *polochon.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if e.ID != 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", e.ID); err != nil {
+			return err
 		}
 	}
+
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+	return err
 }
 
 func (s *Server) events(w http.ResponseWriter, r *http.Request) {
@@ -71,12 +168,27 @@ func (s *Server) events(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ch := s.hub.subscribe()
+	var lastID uint64
+	if v := r.Header.Get("Last-Event-ID"); v != "" {
+		lastID, _ = strconv.ParseUint(v, 10, 64)
+	}
+
+	ch, replay, missed := s.hub.subscribe(lastID)
 	defer s.hub.unsubscribe(ch)
 
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
+
+	// Some events are not in the history anymore, tell the client to
+	// refresh everything
+	if missed {
+		_ = writeEvent(w, polochon.NewEvent(polochon.EventLibraryRefreshed))
+	}
+
+	for _, e := range replay {
+		_ = writeEvent(w, e)
+	}
 	flusher.Flush()
 
 	const keepalive = 30 * time.Second
@@ -85,12 +197,12 @@ func (s *Server) events(w http.ResponseWriter, r *http.Request) {
 
 	for {
 		select {
-		case <-ch:
-			_, _ = io.WriteString(w, "data:\n\n")
-			flusher.Flush()
-			if !timer.Stop() {
-				<-timer.C
+		case e, ok := <-ch:
+			if !ok {
+				return
 			}
+			_ = writeEvent(w, e)
+			flusher.Flush()
 			timer.Reset(keepalive)
 		case <-timer.C:
 			_, _ = io.WriteString(w, ": keepalive\n\n")
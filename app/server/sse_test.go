@@ -2,33 +2,38 @@ package server
 
 import (
 	"bufio"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
 )
 
-func TestSSEHubSubscribeBroadcast(t *testing.T) {
+func TestSSEHubSubscribePublish(t *testing.T) {
 	hub := newSSEHub()
 
-	ch1 := hub.subscribe()
-	ch2 := hub.subscribe()
+	ch1, _, _ := hub.subscribe(0)
+	ch2, _, _ := hub.subscribe(0)
 
-	hub.broadcast()
+	hub.broadcast(polochon.EventLibraryRefreshed)
 
-	select {
-	case <-ch1:
-	case <-time.After(time.Second):
-		t.Fatal("ch1 did not receive broadcast")
-	}
-	select {
-	case <-ch2:
-	case <-time.After(time.Second):
-		t.Fatal("ch2 did not receive broadcast")
+	for name, ch := range map[string]chan *polochon.Event{"ch1": ch1, "ch2": ch2} {
+		select {
+		case e := <-ch:
+			if e.Type != polochon.EventLibraryRefreshed {
+				t.Fatalf("%s: expected event %q, got %q", name, polochon.EventLibraryRefreshed, e.Type)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("%s did not receive the event", name)
+		}
 	}
 
 	hub.unsubscribe(ch1)
-	hub.broadcast()
+	hub.broadcast(polochon.EventLibraryRefreshed)
 
 	// ch1 should be closed
 	select {
@@ -44,36 +49,113 @@ func TestSSEHubSubscribeBroadcast(t *testing.T) {
 	select {
 	case <-ch2:
 	case <-time.After(time.Second):
-		t.Fatal("ch2 did not receive broadcast after ch1 unsubscribed")
+		t.Fatal("ch2 did not receive the event after ch1 unsubscribed")
 	}
 
 	hub.unsubscribe(ch2)
 }
 
-func TestSSEHubCoalescing(t *testing.T) {
+func TestSSEHubEventIDs(t *testing.T) {
 	hub := newSSEHub()
-	ch := hub.subscribe()
+	ch, _, _ := hub.subscribe(0)
 	defer hub.unsubscribe(ch)
 
-	// Multiple broadcasts before consuming should coalesce into one.
-	hub.broadcast()
-	hub.broadcast()
-	hub.broadcast()
+	hub.broadcast(polochon.EventMovieAdded)
+	hub.broadcast(polochon.EventMovieDeleted)
 
-	select {
-	case <-ch:
-	case <-time.After(time.Second):
-		t.Fatal("did not receive coalesced broadcast")
+	first, second := <-ch, <-ch
+	if second.ID != first.ID+1 {
+		t.Fatalf("expected monotonically increasing IDs, got %d and %d", first.ID, second.ID)
 	}
+}
 
-	// Channel should be empty now.
-	select {
-	case <-ch:
-		t.Fatal("should not receive a second event (coalescing)")
-	default:
+func TestSSEHubReplay(t *testing.T) {
+	hub := newSSEHub()
+
+	for range 3 {
+		hub.broadcast(polochon.EventMovieAdded)
+	}
+	lastID := hub.history[0].ID
+
+	ch, replay, missed := hub.subscribe(lastID)
+	defer hub.unsubscribe(ch)
+
+	if missed {
+		t.Fatal("no event should have been missed")
+	}
+
+	if len(replay) != 2 || replay[0].ID != lastID+1 || replay[1].ID != lastID+2 {
+		t.Fatalf("unexpected replayed events: %+v", replay)
 	}
 }
 
+func TestSSEHubReplayOverflow(t *testing.T) {
+	hub := newSSEHub()
+	firstID := hub.lastID + 1
+
+	for range sseHistorySize + 10 {
+		hub.broadcast(polochon.EventMovieAdded)
+	}
+
+	if len(hub.history) != sseHistorySize {
+		t.Fatalf("expected %d events in the history, got %d", sseHistorySize, len(hub.history))
+	}
+
+	ch, replay, missed := hub.subscribe(firstID)
+	defer hub.unsubscribe(ch)
+
+	if !missed {
+		t.Fatal("some events should have been missed")
+	}
+
+	if len(replay) != sseHistorySize {
+		t.Fatalf("expected %d replayed events, got %d", sseHistorySize, len(replay))
+	}
+}
+
+func TestSSEHubSlowClient(t *testing.T) {
+	hub := newSSEHub()
+	ch, _, _ := hub.subscribe(0)
+
+	for range sseClientBufferSize + 1 {
+		hub.broadcast(polochon.EventMovieAdded)
+	}
+
+	// Drain the channel, it should be closed
+	for range sseClientBufferSize {
+		<-ch
+	}
+
+	if _, ok := <-ch; ok {
+		t.Fatal("the slow client should have been disconnected")
+	}
+
+	// Unsubscribing a disconnected client should not panic
+	hub.unsubscribe(ch)
+}
+
+func readEvent(t *testing.T, scanner *bufio.Scanner) (string, *polochon.Event) {
+	t.Helper()
+
+	var id string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			e := &polochon.Event{}
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), e); err != nil {
+				t.Fatalf("invalid event data: %v", err)
+			}
+			return id, e
+		}
+	}
+
+	t.Fatal("did not receive SSE data line")
+	return "", nil
+}
+
 func TestSSEHandler(t *testing.T) {
 	hub := newSSEHub()
 	s := &Server{hub: hub}
@@ -91,13 +173,49 @@ func TestSSEHandler(t *testing.T) {
 		t.Fatalf("expected Content-Type text/event-stream, got %q", ct)
 	}
 
-	hub.broadcast()
+	m := &polochon.Movie{ImdbID: "tt001"}
+	m.Path = "/movies/movie.mkv"
+	hub.publish(polochon.NewVideoAddedEvent(m))
 
-	scanner := bufio.NewScanner(resp.Body)
-	for scanner.Scan() {
-		if scanner.Text() == "data:" {
-			return
-		}
+	id, e := readEvent(t, bufio.NewScanner(resp.Body))
+	if id != strconv.FormatUint(e.ID, 10) {
+		t.Fatalf("expected id %d, got %s", e.ID, id)
+	}
+
+	if e.Type != polochon.EventMovieAdded || e.ImdbID != "tt001" {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+
+	if len(e.Files) != 1 || e.Files[0] != "movie.mkv" {
+		t.Fatalf("unexpected event files: %+v", e.Files)
+	}
+}
+
+func TestSSEHandlerLastEventID(t *testing.T) {
+	hub := newSSEHub()
+	s := &Server{hub: hub}
+
+	hub.broadcast(polochon.EventMovieAdded)
+	lastID := hub.lastID
+	hub.broadcast(polochon.EventMovieDeleted)
+
+	ts := httptest.NewServer(http.HandlerFunc(s.events))
+	defer ts.Close()
+
+	req, err := http.NewRequest("GET", ts.URL, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Last-Event-ID", strconv.FormatUint(lastID, 10))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	_, e := readEvent(t, bufio.NewScanner(resp.Body))
+	if e.ID != lastID+1 || e.Type != polochon.EventMovieDeleted {
+		t.Fatalf("unexpected replayed event: %+v", e)
 	}
-	t.Fatal("did not receive SSE data line")
 }
@@ -73,7 +73,7 @@ func (s *Server) downloadSubtitleByEntry(v polochon.Video, w http.ResponseWriter
 		return
 	}
 
-	s.hub.broadcast()
+	s.hub.publish(polochon.NewSubtitleEvent(sub))
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -202,7 +202,7 @@ func (s *Server) updateSubtitle(v polochon.Video, w http.ResponseWriter, r *http
 		return
 	}
 
-	s.hub.broadcast()
+	s.hub.publish(polochon.NewSubtitleEvent(sub))
 	s.renderOK(w, sub)
 }
 
@@ -236,7 +236,7 @@ func (s *Server) uploadSubtitle(v polochon.Video, w http.ResponseWriter, r *http
 		return
 	}
 
-	s.hub.broadcast()
+	s.hub.publish(polochon.NewSubtitleEvent(sub))
 	s.renderOK(w, sub)
 }
 
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/sirupsen/logrus"
+)
+
+// candidatesQualities returns the qualities asked in the query string or the
+// default ones
+func candidatesQualities(r *http.Request, defaults []polochon.Quality) ([]polochon.Quality, error) {
+	q := r.URL.Query().Get("qualities")
+	if q == "" {
+		return defaults, nil
+	}
+
+	qualities := []polochon.Quality{}
+	for s := range strings.SplitSeq(q, ",") {
+		quality := polochon.Quality(strings.TrimSpace(s))
+		if !quality.IsAllowed() {
+			return nil, &Error{
+				Code:    http.StatusBadRequest,
+				Message: "invalid quality " + string(quality),
+			}
+		}
+		qualities = append(qualities, quality)
+	}
+
+	return qualities, nil
+}
+
+// renderCandidatesError renders the errors returned while fetching the
+// torrent candidates
+func (s *Server) renderCandidatesError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, polochon.ErrGettingDetails) {
+		err = &Error{
+			Code:    http.StatusNotFound,
+			Message: "unable to get the video details",
+		}
+	}
+
+	s.renderError(w, r, err)
+}
+
+// movieCandidates returns the torrents of a movie
+func (s *Server) movieCandidates(r *http.Request, log *logrus.Entry) ([]*polochon.Torrent, error) {
+	m := polochon.NewMovie(s.config.Movie)
+	m.ImdbID = mux.Vars(r)["id"]
+
+	if err := polochon.GetDetails(m, log); err != nil {
+		return nil, err
+	}
+
+	if err := polochon.GetTorrents(m, log); err != nil && err != polochon.ErrTorrentNotFound {
+		return nil, err
+	}
+
+	for _, t := range m.Torrents {
+		t.Type = polochon.TypeMovie
+		t.ImdbID = m.ImdbID
+	}
+
+	return m.Torrents, nil
+}
+
+// episodeCandidates returns the torrents of an episode
+func (s *Server) episodeCandidates(r *http.Request, log *logrus.Entry) ([]*polochon.Torrent, error) {
+	vars := mux.Vars(r)
+
+	season, err := strconv.Atoi(vars["season"])
+	if err != nil {
+		return nil, &Error{Code: http.StatusBadRequest, Message: "invalid season"}
+	}
+
+	episode, err := strconv.Atoi(vars["episode"])
+	if err != nil {
+		return nil, &Error{Code: http.StatusBadRequest, Message: "invalid episode"}
+	}
+
+	show := polochon.NewShow(s.config.Show)
+	show.ImdbID = vars["id"]
+	if err := polochon.GetDetails(show, log); err != nil {
+		return nil, err
+	}
+
+	e := polochon.NewShowEpisode(s.config.Show)
+	e.ShowImdbID = show.ImdbID
+	e.ShowTitle = show.Title
+	e.Season = season
+	e.Episode = episode
+
+	if err := polochon.GetTorrents(e, log); err != nil && err != polochon.ErrTorrentNotFound {
+		return nil, err
+	}
+
+	for _, t := range e.Torrents {
+		t.Type = polochon.TypeEpisode
+		t.ImdbID = e.ShowImdbID
+		t.Season = e.Season
+		t.Episode = e.Episode
+	}
+
+	return e.Torrents, nil
+}
+
+func (s *Server) rankCandidates(w http.ResponseWriter, r *http.Request, defaults []polochon.Quality, candidates func(*http.Request, *logrus.Entry) ([]*polochon.Torrent, error)) {
+	log := s.logEntry(r)
+	log.Infof("ranking torrent candidates")
+
+	qualities, err := candidatesQualities(r, defaults)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	torrents, err := candidates(r, log)
+	if err != nil {
+		s.renderCandidatesError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, s.config.Downloader.TorrentPolicy.Rank(torrents, qualities))
+}
+
+func (s *Server) downloadBestCandidate(w http.ResponseWriter, r *http.Request, defaults []polochon.Quality, candidates func(*http.Request, *logrus.Entry) ([]*polochon.Torrent, error)) {
+	log := s.logEntry(r)
+	log.Infof("downloading the best torrent candidate")
+
+	if !s.config.Downloader.Enabled {
+		s.renderError(w, r, &Error{
+			Code:    http.StatusServiceUnavailable,
+			Message: "downloader not enabled in your polochon",
+		})
+		return
+	}
+
+	qualities, err := candidatesQualities(r, defaults)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	torrents, err := candidates(r, log)
+	if err != nil {
+		s.renderCandidatesError(w, r, err)
+		return
+	}
+
+	torrent := s.config.Downloader.TorrentPolicy.Choose(torrents, qualities)
+	if torrent == nil {
+		s.renderError(w, r, &Error{
+			Code:    http.StatusNotFound,
+			Message: "no torrent matching the policy",
+		})
+		return
+	}
+
+	if err := s.config.Downloader.Client.Download(torrent); err != nil {
+		if err == polochon.ErrDuplicateTorrent {
+			s.renderError(w, r, &Error{
+				Code:    http.StatusConflict,
+				Message: "Torrent already added",
+			})
+			return
+		}
+		s.renderError(w, r, &Error{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.recordGrab(torrent, log)
+	s.hub.publish(polochon.NewTorrentEvent(polochon.EventTorrentAdded, torrent))
+	s.renderOK(w, torrent)
+}
+
+func (s *Server) movieTorrentCandidates(w http.ResponseWriter, r *http.Request) {
+	s.rankCandidates(w, r, s.config.Wishlist.MovieDefaultQualities, s.movieCandidates)
+}
+
+func (s *Server) downloadMovieTorrent(w http.ResponseWriter, r *http.Request) {
+	s.downloadBestCandidate(w, r, s.config.Wishlist.MovieDefaultQualities, s.movieCandidates)
+}
+
+func (s *Server) episodeTorrentCandidates(w http.ResponseWriter, r *http.Request) {
+	s.rankCandidates(w, r, s.config.Wishlist.ShowDefaultQualities, s.episodeCandidates)
+}
+
+func (s *Server) downloadEpisodeTorrent(w http.ResponseWriter, r *http.Request) {
+	s.downloadBestCandidate(w, r, s.config.Wishlist.ShowDefaultQualities, s.episodeCandidates)
+}
@@ -49,6 +49,9 @@ func (s *Server) addTorrent(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
+
+	s.recordGrab(torrent, s.logEntry(r))
+	s.hub.publish(polochon.NewTorrentEvent(polochon.EventTorrentAdded, torrent))
 	s.renderOK(w, nil)
 }
 
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/odwrtw/polochon/app/organizer"
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/unmatched"
+	"github.com/sirupsen/logrus"
+)
+
+// unmatchedResult represents the result of an attempt to organize an
+// unmatched file
+type unmatchedResult struct {
+	Matched bool             `json:"matched"`
+	Entry   *unmatched.Entry `json:"entry,omitempty"`
+}
+
+// unmatchedEntry returns the unmatched queue entry requested
+func (s *Server) unmatchedEntry(r *http.Request) (*unmatched.Store, *unmatched.Entry, error) {
+	store := s.config.Organizer.Unmatched
+	if store == nil {
+		return nil, nil, &Error{
+			Code:    http.StatusServiceUnavailable,
+			Message: "unmatched queue not enabled in your polochon",
+		}
+	}
+
+	id, err := strconv.ParseUint(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		return nil, nil, &Error{Code: http.StatusBadRequest, Message: "invalid id"}
+	}
+
+	entry, err := store.Get(id)
+	if errors.Is(err, unmatched.ErrNotFound) {
+		return nil, nil, &Error{Code: http.StatusNotFound, Message: err.Error()}
+	}
+
+	return store, entry, err
+}
+
+// organizeUnmatched organizes an unmatched file again and renders the result
+func (s *Server) organizeUnmatched(w http.ResponseWriter, r *http.Request, store *unmatched.Store, entry *unmatched.Entry, log *logrus.Entry) {
+	file := polochon.NewFileWithConfig(entry.Path, s.config.File)
+	if !file.Exists() {
+		s.renderError(w, r, &Error{
+			Code:    http.StatusGone,
+			Message: "the file does not exist anymore",
+		})
+		return
+	}
+
+	if err := file.Unignore(); err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	if err := organizer.New(s.config, s.library).OrganizeFile(entry.Path, log); err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	if !store.Has(entry.Path) {
+		s.renderOK(w, unmatchedResult{Matched: true})
+		return
+	}
+
+	// The entry has been updated by the organizer
+	entry, err := store.Get(entry.ID)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, unmatchedResult{Entry: entry})
+}
+
+func (s *Server) getUnmatched(w http.ResponseWriter, r *http.Request) {
+	s.logEntry(r).Infof("getting unmatched files")
+
+	store := s.config.Organizer.Unmatched
+	if store == nil {
+		s.renderError(w, r, &Error{
+			Code:    http.StatusServiceUnavailable,
+			Message: "unmatched queue not enabled in your polochon",
+		})
+		return
+	}
+
+	s.renderOK(w, store.Entries())
+}
+
+func (s *Server) retryUnmatched(w http.ResponseWriter, r *http.Request) {
+	store, entry, err := s.unmatchedEntry(r)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	log := s.logEntry(r).WithField("file_path", entry.Path)
+	log.Infof("retrying unmatched file")
+
+	s.organizeUnmatched(w, r, store, entry, log)
+}
+
+func (s *Server) assignUnmatched(w http.ResponseWriter, r *http.Request) {
+	store, entry, err := s.unmatchedEntry(r)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	hint := &polochon.Hint{}
+	if err := json.NewDecoder(r.Body).Decode(hint); err != nil {
+		s.renderError(w, r, &Error{
+			Code:    http.StatusBadRequest,
+			Message: "Unable to read payload",
+		})
+		return
+	}
+
+	if err := hint.Validate(); err != nil {
+		s.renderError(w, r, &Error{Code: http.StatusBadRequest, Message: err.Error()})
+		return
+	}
+
+	log := s.logEntry(r).WithFields(logrus.Fields{
+		"file_path": entry.Path,
+		"hint":      hint.String(),
+	})
+	log.Infof("assigning unmatched file")
+
+	file := polochon.NewFileWithConfig(entry.Path, s.config.File)
+	if err := file.WriteHint(hint); err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	s.organizeUnmatched(w, r, store, entry, log)
+}
+
+func (s *Server) deleteUnmatched(w http.ResponseWriter, r *http.Request) {
+	store, entry, err := s.unmatchedEntry(r)
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	s.logEntry(r).WithField("file_path", entry.Path).Infof("deleting unmatched file")
+
+	file := polochon.NewFileWithConfig(entry.Path, s.config.File)
+	for _, path := range []string{file.Path, file.IgnorePath(), file.HintPath()} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			s.renderError(w, r, err)
+			return
+		}
+	}
+
+	if err := store.Remove(entry.ID); err != nil && !errors.Is(err, unmatched.ErrNotFound) {
+		s.renderError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, nil)
+}
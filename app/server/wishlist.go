@@ -0,0 +1,196 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+func (s *Server) wishlist(w http.ResponseWriter, r *http.Request) {
+	log := s.logEntry(r)
+	log.Infof("getting wishlist")
+
+	wl := polochon.NewWishlist(s.config.Wishlist, log)
+
+	if err := wl.Fetch(); err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, wl)
+}
+
+// wishlistEditor returns the first configured wishlister that can be edited
+func (s *Server) wishlistEditor() (polochon.WishlistEditor, error) {
+	for _, wl := range s.config.Wishlist.Wishlisters {
+		if editor, ok := wl.(polochon.WishlistEditor); ok {
+			return editor, nil
+		}
+	}
+
+	return nil, &Error{
+		Code:    http.StatusServiceUnavailable,
+		Message: "no editable wishlister configured in your polochon",
+	}
+}
+
+// checkWishedQualities makes sure all the wished qualities are valid
+func checkWishedQualities(qualities []polochon.Quality) error {
+	for _, q := range qualities {
+		if !q.IsAllowed() {
+			return &Error{
+				Code:    http.StatusBadRequest,
+				Message: "invalid quality " + string(q),
+			}
+		}
+	}
+
+	return nil
+}
+
+// renderWishlistError renders the errors returned by the wishlist editors
+func (s *Server) renderWishlistError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, polochon.ErrNotInWishlist) {
+		err = &Error{
+			Code:    http.StatusNotFound,
+			Message: err.Error(),
+		}
+	}
+
+	s.renderError(w, r, err)
+}
+
+func (s *Server) addWishedMovie(w http.ResponseWriter, r *http.Request) {
+	log := s.logEntry(r)
+	log.Infof("adding movie to the wishlist")
+
+	editor, err := s.wishlistEditor()
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	movie := &polochon.WishedMovie{}
+	if err := json.NewDecoder(r.Body).Decode(movie); err != nil {
+		s.renderError(w, r, &Error{
+			Code:    http.StatusBadRequest,
+			Message: "Unable to read payload",
+		})
+		return
+	}
+
+	if movie.ImdbID == "" {
+		s.renderError(w, r, &Error{
+			Code:    http.StatusBadRequest,
+			Message: "Missing imdb_id",
+		})
+		return
+	}
+
+	if err := checkWishedQualities(movie.Qualities); err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	if err := editor.AddMovie(movie, log); err != nil {
+		s.renderWishlistError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, movie)
+}
+
+func (s *Server) addWishedShow(w http.ResponseWriter, r *http.Request) {
+	log := s.logEntry(r)
+	log.Infof("adding show to the wishlist")
+
+	editor, err := s.wishlistEditor()
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	show := &polochon.WishedShow{}
+	if err := json.NewDecoder(r.Body).Decode(show); err != nil {
+		s.renderError(w, r, &Error{
+			Code:    http.StatusBadRequest,
+			Message: "Unable to read payload",
+		})
+		return
+	}
+
+	if show.ImdbID == "" {
+		s.renderError(w, r, &Error{
+			Code:    http.StatusBadRequest,
+			Message: "Missing imdb_id",
+		})
+		return
+	}
+
+	if show.Season < 0 || show.Episode < 0 {
+		s.renderError(w, r, &Error{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid season_from or episode_from",
+		})
+		return
+	}
+
+	if show.DailyDays < 0 {
+		s.renderError(w, r, &Error{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid daily_days",
+		})
+		return
+	}
+
+	if err := checkWishedQualities(show.Qualities); err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	if err := editor.AddShow(show, log); err != nil {
+		s.renderWishlistError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, show)
+}
+
+func (s *Server) deleteWishedMovie(w http.ResponseWriter, r *http.Request) {
+	log := s.logEntry(r)
+	log.Infof("removing movie from the wishlist")
+
+	editor, err := s.wishlistEditor()
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	if err := editor.RemoveMovie(mux.Vars(r)["id"], log); err != nil {
+		s.renderWishlistError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, nil)
+}
+
+func (s *Server) deleteWishedShow(w http.ResponseWriter, r *http.Request) {
+	log := s.logEntry(r)
+	log.Infof("removing show from the wishlist")
+
+	editor, err := s.wishlistEditor()
+	if err != nil {
+		s.renderError(w, r, err)
+		return
+	}
+
+	if err := editor.RemoveShow(mux.Vars(r)["id"], log); err != nil {
+		s.renderWishlistError(w, r, err)
+		return
+	}
+
+	s.renderOK(w, nil)
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	log "github.com/sirupsen/logrus"
+)
+
+// diskChunkSize represents the size of the chunks kept in the disk cache.
+var diskChunkSize int64 = 1_000_000 // 1MB
+
+// diskCacheEntry represents a chunk stored in the disk cache.
+type diskCacheEntry struct {
+	name string
+	size int64
+}
+
+// diskCache is a persistent cache of file chunks with a size cap, the least
+// recently used chunks are evicted first.
+type diskCache struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	size    int64
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+// newDiskCache creates the cache directory if needed and loads the chunks
+// already in it, the oldest modification times are evicted first.
+func newDiskCache(dir string, maxSize int64) (*diskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	c := &diskCache{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+	}
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type chunk struct {
+		diskCacheEntry
+		modTime time.Time
+	}
+
+	var chunks []chunk
+	for _, e := range dirEntries {
+		path := filepath.Join(dir, e.Name())
+		if e.IsDir() {
+			continue
+		}
+
+		// Remove the temporary files of interrupted writes
+		if strings.HasPrefix(e.Name(), ".") {
+			_ = os.Remove(path)
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+
+		chunks = append(chunks, chunk{
+			diskCacheEntry: diskCacheEntry{name: e.Name(), size: info.Size()},
+			modTime:        info.ModTime(),
+		})
+	}
+
+	slices.SortFunc(chunks, func(a, b chunk) int {
+		return a.modTime.Compare(b.modTime)
+	})
+
+	for _, chunk := range chunks {
+		c.entries[chunk.name] = c.lru.PushFront(&chunk.diskCacheEntry)
+		c.size += chunk.size
+	}
+
+	c.mu.Lock()
+	c.evict()
+	c.mu.Unlock()
+
+	log.WithFields(log.Fields{
+		"dir":      dir,
+		"chunks":   c.lru.Len(),
+		"size":     humanize.SI(float64(c.size), "B"),
+		"max_size": humanize.SI(float64(maxSize), "B"),
+	}).Info("Disk cache loaded")
+
+	return c, nil
+}
+
+// diskCacheKey returns the cache key of a file, the key changes whenever the
+// file is replaced on the server.
+func diskCacheKey(url string, size int64, modTime time.Time) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s|%d|%d", url, size, modTime.UnixNano()))
+	return hex.EncodeToString(sum[:16])
+}
+
+// chunkName returns the file name of a chunk.
+func chunkName(key string, index int64) string {
+	return key + "-" + strconv.FormatInt(index, 10)
+}
+
+// get returns the content of a chunk, false is returned if the chunk is not
+// in the cache.
+func (c *diskCache) get(key string, index int64) ([]byte, bool) {
+	name := chunkName(key, index)
+
+	c.mu.Lock()
+	elem, ok := c.entries[name]
+	if ok {
+		c.lru.MoveToFront(elem)
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	path := filepath.Join(c.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err,
+			"chunk": name,
+		}).Warn("Failed to read chunk from disk cache")
+		c.remove(name)
+		return nil, false
+	}
+
+	// Keep the order of the chunks between restarts
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+
+	return data, true
+}
+
+// put stores a chunk in the cache.
+func (c *diskCache) put(key string, index int64, data []byte) error {
+	name := chunkName(key, index)
+
+	c.mu.Lock()
+	_, ok := c.entries[name]
+	c.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	// Write the chunk in a temporary file and move it to its final
+	// destination so that the chunks are never partially written
+	tmp, err := os.CreateTemp(c.dir, "."+name+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir, name)); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[name]; ok {
+		return nil
+	}
+
+	size := int64(len(data))
+	c.entries[name] = c.lru.PushFront(&diskCacheEntry{name: name, size: size})
+	c.size += size
+	c.evict()
+
+	return nil
+}
+
+// remove removes a chunk from the cache.
+func (c *diskCache) remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[name]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// evict removes the least recently used chunks until the cache size is below
+// the limit, the caller must hold the lock.
+func (c *diskCache) evict() {
+	for c.size > c.maxSize {
+		elem := c.lru.Back()
+		if elem == nil {
+			return
+		}
+
+		log.WithField("chunk", elem.Value.(*diskCacheEntry).name).Trace("Evicting chunk from disk cache")
+		c.removeElement(elem)
+	}
+}
+
+// removeElement removes a chunk from the cache and from the disk, the caller
+// must hold the lock.
+func (c *diskCache) removeElement(elem *list.Element) {
+	entry := elem.Value.(*diskCacheEntry)
+	c.lru.Remove(elem)
+	delete(c.entries, entry.name)
+	c.size -= entry.size
+
+	err := os.Remove(filepath.Join(c.dir, entry.name))
+	if err != nil && !os.IsNotExist(err) {
+		log.WithFields(log.Fields{
+			"error": err,
+			"chunk": entry.name,
+		}).Warn("Failed to remove chunk from disk cache")
+	}
+}
+
+// readAt reads a file from the cache, false is returned unless the whole range
+// is in the cache.
+func (c *diskCache) readAt(key string, size int64, dest []byte, offset int64) (int, bool) {
+	end := min(offset+int64(len(dest)), size)
+	if offset >= end {
+		return 0, false
+	}
+
+	n := 0
+	for pos := offset; pos < end; {
+		index := pos / diskChunkSize
+		data, ok := c.get(key, index)
+		if !ok {
+			return 0, false
+		}
+
+		start := pos - index*diskChunkSize
+		if start >= int64(len(data)) {
+			return 0, false
+		}
+
+		read := copy(dest[n:end-offset], data[start:])
+		n += read
+		pos += int64(read)
+	}
+
+	return n, true
+}
+
+// cachingReader is a reader that stores the chunks it reads in the disk
+// cache, the bytes before the first chunk boundary are not cached.
+type cachingReader struct {
+	source io.ReadCloser
+	cache  *diskCache
+	key    string
+	// pos is the position of the next byte to read in the file.
+	pos, size int64
+	chunk     []byte
+}
+
+func newCachingReader(source io.ReadCloser, cache *diskCache, key string, offset, size int64) *cachingReader {
+	return &cachingReader{
+		source: source,
+		cache:  cache,
+		key:    key,
+		pos:    offset,
+		size:   size,
+	}
+}
+
+// Read implements the io.Reader interface.
+func (r *cachingReader) Read(p []byte) (int, error) {
+	n, err := r.source.Read(p)
+	r.write(p[:n])
+	return n, err
+}
+
+// write adds the bytes read to the current chunk and stores it once complete.
+func (r *cachingReader) write(p []byte) {
+	for len(p) > 0 {
+		index := r.pos / diskChunkSize
+		chunkStart := index * diskChunkSize
+		chunkEnd := min(chunkStart+diskChunkSize, r.size)
+		n := min(int64(len(p)), chunkEnd-r.pos)
+
+		// Only the chunks read from their start can be cached
+		if r.chunk != nil || r.pos == chunkStart {
+			r.chunk = append(r.chunk, p[:n]...)
+		}
+
+		r.pos += n
+		p = p[n:]
+
+		if r.pos != chunkEnd {
+			continue
+		}
+
+		if r.chunk != nil {
+			if err := r.cache.put(r.key, index, r.chunk); err != nil {
+				log.WithFields(log.Fields{
+					"error": err,
+					"chunk": chunkName(r.key, index),
+				}).Warn("Failed to write chunk in disk cache")
+			}
+		}
+		r.chunk = nil
+	}
+}
+
+// Close implements the io.Closer interface.
+func (r *cachingReader) Close() error {
+	return r.source.Close()
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func withChunkSize(t *testing.T, size int64) {
+	previous := diskChunkSize
+	diskChunkSize = size
+	t.Cleanup(func() { diskChunkSize = previous })
+}
+
+func TestDiskCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := newDiskCache(dir, 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	for i := range int64(3) {
+		if err := c.put("key", i, []byte("1234")); err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+
+		// Use the first chunk to make it the most recently used
+		if _, ok := c.get("key", 0); !ok {
+			t.Fatal("expected the first chunk to be in the cache")
+		}
+	}
+
+	if _, ok := c.get("key", 1); ok {
+		t.Error("expected the least recently used chunk to be evicted")
+	}
+
+	if c.size != 8 {
+		t.Errorf("expected a cache size of 8, got %d", c.size)
+	}
+
+	// The chunks are kept between restarts
+	c, err = newDiskCache(dir, 10)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	for _, i := range []int64{0, 2} {
+		data, ok := c.get("key", i)
+		if !ok {
+			t.Fatalf("expected chunk %d to be in the cache", i)
+		}
+
+		if string(data) != "1234" {
+			t.Errorf("expected chunk %d to be %q, got %q", i, "1234", data)
+		}
+	}
+}
+
+func TestDiskCacheKey(t *testing.T) {
+	now := time.Now()
+	key := diskCacheKey("http://polochon/movies/tt001/download", 10, now)
+
+	for _, other := range []string{
+		diskCacheKey("http://polochon/movies/tt002/download", 10, now),
+		diskCacheKey("http://polochon/movies/tt001/download", 11, now),
+		diskCacheKey("http://polochon/movies/tt001/download", 10, now.Add(time.Second)),
+	} {
+		if other == key {
+			t.Errorf("expected different keys, got %q", key)
+		}
+	}
+}
+
+func TestCachingReader(t *testing.T) {
+	withChunkSize(t, 4)
+
+	c, err := newDiskCache(t.TempDir(), 100)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	content := []byte("0123456789")
+	size := int64(len(content))
+
+	// Start reading in the middle of the first chunk
+	source := io.NopCloser(bytes.NewReader(content[2:]))
+	r := newCachingReader(source, c, "key", 2, size)
+
+	// Read with a buffer that does not match the chunks
+	buf := make([]byte, 3)
+	for {
+		if _, err := r.Read(buf); err != nil {
+			break
+		}
+	}
+
+	if _, ok := c.get("key", 0); ok {
+		t.Error("expected the first chunk not to be cached")
+	}
+
+	dest := make([]byte, 5)
+	n, ok := c.readAt("key", size, dest, 4)
+	if !ok {
+		t.Fatal("expected the range to be in the cache")
+	}
+
+	if got := string(dest[:n]); got != "45678" {
+		t.Errorf("expected %q, got %q", "45678", got)
+	}
+
+	// The last chunk is shorter than the others
+	n, ok = c.readAt("key", size, dest, 8)
+	if !ok {
+		t.Fatal("expected the end of the file to be in the cache")
+	}
+
+	if got := string(dest[:n]); got != "89" {
+		t.Errorf("expected %q, got %q", "89", got)
+	}
+
+	if _, ok := c.readAt("key", size, dest, 0); ok {
+		t.Error("expected a range starting in the first chunk to be missed")
+	}
+}
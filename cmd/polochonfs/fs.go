@@ -12,6 +12,7 @@ import (
 
 	"github.com/hanwen/go-fuse/v2/fs"
 	"github.com/hanwen/go-fuse/v2/fuse"
+	polochon "github.com/odwrtw/polochon/lib"
 	"github.com/odwrtw/polochon/lib/papi"
 	log "github.com/sirupsen/logrus"
 )
@@ -28,10 +29,9 @@ type polochonfs struct {
 }
 
 func newPolochonFs(ctx context.Context) (*polochonfs, error) {
-	return &polochonfs{
-		ctx:  ctx,
-		root: newRootNode(),
-	}, nil
+	pfs := &polochonfs{ctx: ctx}
+	pfs.root = newRootNode(pfs)
+	return pfs, nil
 }
 
 func (pfs *polochonfs) init() error {
@@ -81,7 +81,7 @@ func (pfs *polochonfs) handleUpdates() {
 
 	for {
 		select {
-		case _, ok := <-events:
+		case e, ok := <-events:
 			if !ok {
 				// SSE channel closed (server doesn't support /events),
 				// degrade to ticker-only mode.
@@ -89,10 +89,8 @@ func (pfs *polochonfs) handleUpdates() {
 				events = nil
 				continue
 			}
-			log.Info("Updating library from SSE event")
 			ticker.Reset(libraryRefresh)
-			pfs.updateMovies()
-			pfs.updateShows()
+			pfs.handleEvent(e)
 		case s := <-sigs:
 			switch s {
 			case syscall.SIGUSR1:
@@ -115,6 +113,57 @@ func (pfs *polochonfs) handleUpdates() {
 	}
 }
 
+func (pfs *polochonfs) handleEvent(e *polochon.Event) {
+	log := log.WithFields(log.Fields{"event_id": e.ID, "event_type": e.Type})
+
+	switch e.Type {
+	case polochon.EventMovieAdded, polochon.EventMovieUpgraded,
+		polochon.EventMovieDeleted, polochon.EventMovieUpdated:
+		log.Info("Updating movie from SSE event")
+		pfs.updateMovieFromEvent(e)
+	case polochon.EventShowDeleted, polochon.EventShowUpdated, polochon.EventSeasonDeleted,
+		polochon.EventEpisodeAdded, polochon.EventEpisodeUpgraded,
+		polochon.EventEpisodeDeleted, polochon.EventEpisodeUpdated:
+		log.Info("Updating show from SSE event")
+		pfs.updateShowFromEvent(e)
+	case polochon.EventSubtitleUpdated:
+		log.Info("Updating subtitles from SSE event")
+		if e.Season == 0 && e.Episode == 0 {
+			pfs.updateMovieFromEvent(e)
+		} else {
+			pfs.updateShowFromEvent(e)
+		}
+	case polochon.EventLibraryRefreshed:
+		log.Info("Updating library from SSE event")
+		pfs.updateMovies()
+		pfs.updateShows()
+	default:
+		log.Debug("Ignoring SSE event")
+	}
+}
+
+// updateMovieFromEvent updates the movie of an event, all the movies are
+// updated if the event is not about a single movie.
+func (pfs *polochonfs) updateMovieFromEvent(e *polochon.Event) {
+	if e.ImdbID == "" {
+		pfs.updateMovies()
+		return
+	}
+
+	pfs.updateMovie(e.ImdbID)
+}
+
+// updateShowFromEvent updates the show of an event, all the shows are updated
+// if the event is not about a single show.
+func (pfs *polochonfs) updateShowFromEvent(e *polochon.Event) {
+	if e.ImdbID == "" {
+		pfs.updateShows()
+		return
+	}
+
+	pfs.updateShow(e.ImdbID)
+}
+
 func (pfs *polochonfs) mount() (*fuse.Server, error) {
 	customLogger := logger.New(os.Stdout,
 		"\033[33mFUSE\033[0m ", // Yellow prefix
@@ -129,8 +178,8 @@ func (pfs *polochonfs) mount() (*fuse.Server, error) {
 			// Enforce sequential read, one read at a time. This is useful to
 			// read from the http body directly.
 			SyncRead: true,
-			// Mount in read only mode
-			Options: []string{"ro"},
+			// Mount in read only mode unless the writes are allowed
+			Options: mountOptions(),
 			Name:    "polochonfs",
 			FsName:  "polochonfs",
 			Debug:   pfs.fuseDebug,
@@ -142,6 +191,14 @@ func (pfs *polochonfs) mount() (*fuse.Server, error) {
 	})
 }
 
+func mountOptions() []string {
+	if allowWrite {
+		return nil
+	}
+
+	return []string{"ro"}
+}
+
 func (pfs *polochonfs) unmount(server *fuse.Server) {
 	var err error
 
@@ -21,15 +21,26 @@ var (
 	defaultCacheSize = "10MB"
 	logLevel         = "info"
 	cacheSize        = 10_000_000
+	diskCacheDir     = ""
+	diskCacheMaxSize = "10GB"
 	defaultTimeout   = 3 * time.Second
 	libraryRefresh   = 1 * time.Hour
 	umountLogTimeout = 1 * time.Minute
 	globalCtx        context.Context
+	allowWrite       = false
+	viewList         = ""
+	recentDays       = 30
 
 	// Polochon URL / Token configs.
 	polochonURL   string
 	polochonToken string
 
+	// Enabled virtual views.
+	views []string
+
+	// Persistent chunk cache, disabled if no directory is set.
+	fileCache *diskCache
+
 	// User and group IDs.
 	uid, gid uint32
 )
@@ -72,11 +83,16 @@ func run() error {
 	flag.StringVar(&showDirName, "showDirName", showDirName, "show directory name")
 	flag.StringVar(&movieDirName, "movieDirName", movieDirName, "movie directory name")
 	flag.StringVar(&defaultCacheSize, "cache", defaultCacheSize, "cache size (e.g. 10MB)")
+	flag.StringVar(&diskCacheDir, "diskCache", diskCacheDir, "directory of the persistent chunk cache, disabled if empty")
+	flag.StringVar(&diskCacheMaxSize, "diskCacheSize", diskCacheMaxSize, "persistent chunk cache size (e.g. 10GB)")
 	flag.StringVar(&logLevel, "logLevel", logLevel, "log level (warn, error, info, debug, trace)")
 	flag.DurationVar(&defaultTimeout, "timeout", defaultTimeout, "HTTP requests timeout")
 	flag.DurationVar(&libraryRefresh, "libraryRefresh", libraryRefresh, "library refresh timer")
 	flag.Uint64Var(&uid64, "uid", uid64, "UID of the mounted files")
 	flag.Uint64Var(&gid64, "gid", uid64, "GID of the mounted files")
+	flag.BoolVar(&allowWrite, "allowWrite", allowWrite, "allow to delete videos and to upload subtitles through the mount")
+	flag.StringVar(&viewList, "views", viewList, "comma separated list of virtual views to show (genre, year, recent, quality)")
+	flag.IntVar(&recentDays, "recentDays", recentDays, "number of days of the recently added view")
 	flag.BoolVar(&pfs.fuseDebug, "fuseDebug", false, "debug fuse events")
 	flag.Parse()
 
@@ -102,6 +118,23 @@ func run() error {
 	log.SetOutput(os.Stdout)
 	log.SetLevel(lvl)
 
+	views, err = parseViews(viewList)
+	if err != nil {
+		return err
+	}
+
+	if diskCacheDir != "" {
+		maxSize, err := humanize.ParseBytes(diskCacheMaxSize)
+		if err != nil {
+			return err
+		}
+
+		fileCache, err = newDiskCache(diskCacheDir, int64(maxSize))
+		if err != nil {
+			return err
+		}
+	}
+
 	err = pfs.init()
 	if err != nil {
 		flag.PrintDefaults()
@@ -1,8 +1,8 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/odwrtw/polochon/lib/papi"
 	log "github.com/sirupsen/logrus"
@@ -14,9 +14,8 @@ func movieDirTitle(m *papi.Movie) string {
 		dirTitle += fmt.Sprintf(" (%d)", m.Year)
 	}
 
-	// Replace the "/" in the names with a "-".
 	// e.g. 50/50 -> 50-50
-	return strings.ReplaceAll(dirTitle, "/", "-")
+	return sanitizeName(dirTitle)
 }
 
 func (pfs *polochonfs) updateMovies() {
@@ -33,22 +32,56 @@ func (pfs *polochonfs) updateMovies() {
 	movieRootDir.valid = true
 
 	for _, m := range movies.List() {
-		movieDirNode := pfs.createDirNode(movieRootDir, movieDirTitle(m), m.DateAdded)
-
-		err = pfs.createFileNode(movieDirNode, m, m.Path, m.Size, m.DateAdded)
-		if err != nil {
-			log.WithFields(log.Fields{
-				"error": err,
-				"title": m.Title,
-			}).Error("Failed to create movie node")
-			continue
-		}
-
-		pfs.createSubtitlesNodes(movieDirNode, m.Path, m.Subtitles, m.DateAdded)
-		pfs.createFilesNodes(movieDirNode, []*papi.File{m.Fanart, m.Thumb, m.NFO}, m.DateAdded)
+		pfs.createMovieNodes(movieRootDir, m)
 	}
 
 	movieRootDir.clear()
 
+	pfs.updateViews()
+
 	log.Debug("Movies updated")
 }
+
+// updateMovie only updates the nodes of a movie, the movie nodes are removed
+// if it's not in the library anymore.
+func (pfs *polochonfs) updateMovie(id string) {
+	log := log.WithField("imdb_id", id)
+
+	log.Debug("Fecthing movie")
+	movie, err := pfs.client.GetMovie(id)
+	if err != nil && !errors.Is(err, papi.ErrResourceNotFound) {
+		log.WithField("error", err).Error("Failed to get movie")
+		return
+	}
+
+	movieRootDir := pfs.createDirNode(pfs.root, movieDirName, pfs.root.times)
+	movieRootDir.invalidateResource(id)
+
+	if movie != nil {
+		pfs.createMovieNodes(movieRootDir, movie)
+	}
+
+	movieRootDir.clear()
+
+	pfs.updateViews()
+
+	log.Debug("Movie updated")
+}
+
+func (pfs *polochonfs) createMovieNodes(movieRootDir *node, m *papi.Movie) {
+	movieDirNode := pfs.createDirNode(movieRootDir, movieDirTitle(m), m.DateAdded)
+	movieDirNode.resource = m
+
+	movieNode, err := pfs.createFileNode(movieDirNode, m, m.Path, m.Size, m.DateAdded)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err,
+			"title": m.Title,
+		}).Error("Failed to create movie node")
+		return
+	}
+	movieNode.resource = m
+
+	pfs.createSubtitlesNodes(movieDirNode, m.Path, m.Subtitles, m.DateAdded)
+	pfs.createFilesNodes(movieDirNode, []*papi.File{m.Fanart, m.Thumb, m.NFO}, m.DateAdded)
+}
@@ -12,12 +12,14 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/hanwen/go-fuse/v2/fs"
 	"github.com/hanwen/go-fuse/v2/fuse"
+	"github.com/odwrtw/polochon/lib/papi"
 	log "github.com/sirupsen/logrus"
 )
 
 var (
 	_ = (fs.NodeGetattrer)((*node)(nil))
 	_ = (fs.NodeOpener)((*node)(nil))
+	_ = (fs.NodeReadlinker)((*node)(nil))
 
 	_ = (fs.FileReader)((*fileHandle)(nil))
 	_ = (fs.FileFlusher)((*fileHandle)(nil))
@@ -31,10 +33,18 @@ type node struct {
 	size  uint64
 	url   string
 	isDir bool
+	// target is the path pointed by a symlink node, relative to its parent.
+	target string
 
 	mu       sync.Mutex
 	children map[string]*node
 	valid    bool
+
+	// pfs is the filesystem of the node, it's used to modify the library.
+	pfs *polochonfs
+	// resource is the library resource represented by the node, it's used
+	// to delete the movies, shows, seasons and episodes.
+	resource papi.Resource
 }
 
 func newNode(name string, isDir bool) *node {
@@ -53,8 +63,9 @@ func newNodeDir(name string, times time.Time) *node {
 	return node
 }
 
-func newRootNode() *node {
+func newRootNode(pfs *polochonfs) *node {
 	return &node{
+		pfs:      pfs,
 		name:     "root",
 		times:    time.Now(),
 		children: map[string]*node{},
@@ -65,8 +76,46 @@ func newNodeFile(name string) *node {
 	return newNode(name, false)
 }
 
-func (n *node) setURL(url string) {
-	n.url = url
+func newNodeLink(name, target string) *node {
+	node := newNode(name, false)
+	node.target = target
+	return node
+}
+
+func (n *node) isLink() bool {
+	return n.target != ""
+}
+
+// setSize sets the size of a file node, the size of the subtitles being
+// written changes while the node attributes are read.
+func (n *node) setSize(size uint64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.size = size
+}
+
+func (n *node) getSize() uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.size
+}
+
+func (n *node) getURL() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.url
+}
+
+func (n *node) getTimes() time.Time {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.times
+}
+
+func (n *node) isValid() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.valid
 }
 
 func (n *node) invalidate() {
@@ -86,7 +135,7 @@ func (n *node) clear() {
 
 	for name, child := range n.children {
 		child.clear()
-		if child.valid {
+		if child.isValid() {
 			continue
 		}
 
@@ -126,13 +175,71 @@ func (n *node) addChildNode(c *node) {
 
 func (n *node) addChild(child *node) {
 	attr := fs.StableAttr{Mode: syscall.S_IFREG}
-	if child.isDir {
+	switch {
+	case child.isDir:
 		attr.Mode = syscall.S_IFDIR
+	case child.isLink():
+		attr.Mode = syscall.S_IFLNK
 	}
 
+	child.pfs = n.pfs
 	inode := n.NewInode(context.Background(), child, attr)
 	n.addChildNode(child)
 	n.AddChild(child.name, inode, true)
+
+	// Drop the negative entry the kernel may have cached for this name and
+	// the cached listing of the directory, the errors are expected if the
+	// kernel never looked them up
+	_ = n.NotifyEntry(child.name)
+	_ = n.NotifyContent(0, 0)
+}
+
+// update updates the attributes of a file node, the kernel caches of the file
+// are invalidated if it changed.
+func (n *node) update(size uint64, times time.Time, url string) {
+	n.mu.Lock()
+	changed := n.size != size || !n.times.Equal(times) || n.url != url
+	n.size = size
+	n.times = times
+	n.url = url
+	n.valid = true
+	n.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	log.WithField("file", n.name).Debug("File changed")
+
+	// Invalidate the attributes and the pages of the file, the open file
+	// handles keep reading the previous URL
+	_ = n.NotifyContent(0, 0)
+}
+
+// invalidateResource invalidates the children representing the resource
+// with the given id.
+func (n *node) invalidateResource(id string) {
+	for _, child := range n.childNodes() {
+		if resourceID(child.resource) == id {
+			child.invalidate()
+		}
+	}
+}
+
+// resourceID returns the imdb id of a resource.
+func resourceID(r papi.Resource) string {
+	switch r := r.(type) {
+	case *papi.Movie:
+		return r.ImdbID
+	case *papi.Show:
+		return r.ImdbID
+	case *papi.Season:
+		return r.ShowImdbID
+	case *papi.Episode:
+		return r.ShowImdbID
+	default:
+		return ""
+	}
 }
 
 func (n *node) getChild(name string) *node {
@@ -141,6 +248,18 @@ func (n *node) getChild(name string) *node {
 	return n.children[name]
 }
 
+// childNodes returns the children of the node.
+func (n *node) childNodes() []*node {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	children := make([]*node, 0, len(n.children))
+	for _, child := range n.children {
+		children = append(children, child)
+	}
+	return children
+}
+
 func (n *node) childCount() uint32 {
 	n.mu.Lock()
 	defer n.mu.Unlock()
@@ -148,13 +267,17 @@ func (n *node) childCount() uint32 {
 }
 
 func (n *node) updateAttr(out *fuse.Attr) {
-	out.SetTimes(&n.times, &n.times, &n.times)
+	times := n.getTimes()
+	out.SetTimes(&times, &times, &times)
 
-	if n.IsDir() {
+	switch {
+	case n.IsDir():
 		out.Size = 4096
 		out.Nlink = n.childCount()
-	} else {
-		out.Size = n.size
+	case n.isLink():
+		out.Size = uint64(len(n.target))
+	default:
+		out.Size = n.getSize()
 		out.Blksize = 4096 * 4
 	}
 }
@@ -166,12 +289,26 @@ func (n *node) Getattr(_ context.Context, _ fs.FileHandle, out *fuse.AttrOut) sy
 	return 0
 }
 
+func (n *node) Readlink(_ context.Context) ([]byte, syscall.Errno) {
+	if !n.isLink() {
+		return nil, syscall.EINVAL
+	}
+
+	return []byte(n.target), 0
+}
+
 func (n *node) Open(_ context.Context, flags uint32) (fh fs.FileHandle, fuseFlags uint32, errno syscall.Errno) {
 	log.WithFields(log.Fields{
 		"node":  n.name,
 		"flags": flags,
 	}).Debug("Open called on node")
-	return newFileHandle(n.name, n.url, int64(n.size)), 0, 0
+
+	if flags&syscall.O_ACCMODE != syscall.O_RDONLY {
+		fh, errno := n.openSubtitle(flags)
+		return fh, 0, errno
+	}
+
+	return newFileHandle(n.name, n.getURL(), int64(n.getSize()), n.getTimes()), 0, 0
 }
 
 type fileHandle struct {
@@ -179,13 +316,17 @@ type fileHandle struct {
 	size, lastOffset int64
 	cancel           context.CancelFunc
 	buffer           io.ReadCloser
+
+	// cacheKey is the key of the file in the disk cache.
+	cacheKey string
 }
 
-func newFileHandle(name, url string, size int64) *fileHandle {
+func newFileHandle(name, url string, size int64, times time.Time) *fileHandle {
 	return &fileHandle{
-		name: name,
-		url:  url,
-		size: size,
+		name:     name,
+		url:      url,
+		size:     size,
+		cacheKey: diskCacheKey(url, size, times),
 	}
 }
 
@@ -247,8 +388,13 @@ func (fh *fileHandle) setup(_ context.Context, offset int64) error {
 		log.WithField("name", fh.name).Trace("Done closing old buffer")
 	}
 
+	var source io.ReadCloser = resp.Body
+	if fileCache != nil {
+		source = newCachingReader(source, fileCache, fh.cacheKey, offset, fh.size)
+	}
+
 	fh.lastOffset = offset
-	fh.buffer = newAsyncReader(cancelCtx, fh.name, resp.Body, offset, fh.size)
+	fh.buffer = newAsyncReader(cancelCtx, fh.name, source, offset, fh.size)
 	return nil
 }
 
@@ -283,6 +429,15 @@ func (fh *fileHandle) Read(ctx context.Context, dest []byte, offset int64) (fuse
 
 	defaultErr := syscall.ENETUNREACH // Network unreachable
 	if fh.buffer == nil || offset != fh.lastOffset {
+		// Serve the reads from the disk cache instead of starting a new
+		// request when possible
+		if fileCache != nil {
+			if n, ok := fileCache.readAt(fh.cacheKey, fh.size, dest, offset); ok {
+				l.Trace("Read from disk cache")
+				return fuse.ReadResultData(dest[:n]), 0
+			}
+		}
+
 		if err := fh.setup(ctx, offset); err != nil {
 			l.WithField("error", err).Error("Failed to setup file handle")
 			return fuse.ReadResultData(dest), defaultErr
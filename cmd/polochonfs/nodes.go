@@ -19,30 +19,33 @@ func (pfs *polochonfs) createDirNode(parent *node, name string, times time.Time)
 }
 
 func (pfs *polochonfs) createFileNode(parent *node, res papi.Downloadable, name string,
-	size int64, times time.Time) error {
+	size int64, times time.Time) (*node, error) {
 	if res == nil {
-		return nil
+		return nil, nil
 	}
 
 	url, err := pfs.client.DownloadURL(res)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return pfs.addOrUpdateFileNode(parent, name, uint64(size), times, url)
+	return pfs.addOrUpdateFileNode(parent, name, uint64(size), times, url), nil
 }
 
-func (pfs *polochonfs) addOrUpdateFileNode(parent *node, name string, size uint64, times time.Time, url string) error {
+func (pfs *polochonfs) addOrUpdateFileNode(parent *node, name string, size uint64, times time.Time, url string) *node {
 	fileNode := parent.getChild(name)
 	if fileNode == nil {
 		fileNode = newNodeFile(name)
-		fileNode.setURL(url)
+		fileNode.size = size
+		fileNode.times = times
+		fileNode.url = url
+		fileNode.valid = true
 		parent.addChild(fileNode)
+		return fileNode
 	}
-	fileNode.size = size
-	fileNode.times = times
-	fileNode.valid = true
-	return nil
+
+	fileNode.update(size, times, url)
+	return fileNode
 }
 
 func (pfs *polochonfs) createFilesNodes(parent *node, files []*papi.File, times time.Time) {
@@ -51,7 +54,7 @@ func (pfs *polochonfs) createFilesNodes(parent *node, files []*papi.File, times
 			continue
 		}
 
-		err := pfs.createFileNode(parent, file, file.Name, file.Size, times)
+		_, err := pfs.createFileNode(parent, file, file.Name, file.Size, times)
 		if err != nil {
 			log.WithFields(log.Fields{
 				"error": err,
@@ -69,7 +72,7 @@ func (pfs *polochonfs) createSubtitlesNodes(parent *node, videoPath string, subs
 		}
 
 		path := polochon.NewFile(videoPath).SubtitlePath(sub.Lang)
-		err := pfs.createFileNode(parent, sub, path, sub.Size, times)
+		_, err := pfs.createFileNode(parent, sub, path, sub.Size, times)
 		if err != nil {
 			log.WithFields(log.Fields{
 				"error": err,
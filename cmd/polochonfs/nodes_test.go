@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/papi"
+)
+
+func TestInvalidateResource(t *testing.T) {
+	root := newNodeDir(movieDirName, time.Time{})
+
+	movies := map[string]*node{}
+	for _, id := range []string{"tt001", "tt002"} {
+		dir := newNodeDir("Movie "+id, time.Time{})
+		dir.resource = &papi.Movie{Movie: &polochon.Movie{ImdbID: id}}
+		dir.valid = true
+
+		file := newNodeFile(id + ".mkv")
+		file.valid = true
+		dir.addChildNode(file)
+
+		root.addChildNode(dir)
+		movies[id] = dir
+	}
+
+	root.invalidateResource("tt001")
+
+	if movies["tt001"].valid || movies["tt001"].getChild("tt001.mkv").valid {
+		t.Error("expected the movie nodes to be invalidated")
+	}
+
+	if !movies["tt002"].valid || !movies["tt002"].getChild("tt002.mkv").valid {
+		t.Error("expected the other movie nodes to stay valid")
+	}
+}
+
+func TestResourceID(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		resource papi.Resource
+		expected string
+	}{
+		{
+			name:     "movie",
+			resource: &papi.Movie{Movie: &polochon.Movie{ImdbID: "tt001"}},
+			expected: "tt001",
+		},
+		{
+			name:     "show",
+			resource: &papi.Show{Show: &polochon.Show{ImdbID: "tt002"}},
+			expected: "tt002",
+		},
+		{
+			name:     "season",
+			resource: &papi.Season{ShowImdbID: "tt002", Season: 1},
+			expected: "tt002",
+		},
+		{
+			name:     "episode",
+			resource: &papi.Episode{ShowEpisode: &polochon.ShowEpisode{ShowImdbID: "tt002"}},
+			expected: "tt002",
+		},
+		{
+			name:     "no resource",
+			expected: "",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := resourceID(test.resource); got != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, got)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/odwrtw/polochon/lib/papi"
@@ -21,35 +22,70 @@ func (pfs *polochonfs) updateShows() {
 	showRootDir.valid = true
 
 	for _, s := range shows.List() {
-		showDirNode := pfs.createDirNode(showRootDir, s.Title, pfs.root.times)
-
-		files := []*papi.File{s.Fanart, s.Banner, s.Poster, s.NFO}
-		pfs.createFilesNodes(showDirNode, files, showDirNode.times)
-
-		for _, season := range s.Seasons {
-			name := fmt.Sprintf("Season %d", season.Season)
-			seasonDir := pfs.createDirNode(showDirNode, name, showDirNode.times)
-
-			for _, episode := range season.Episodes {
-				err = pfs.createFileNode(seasonDir, episode, episode.Path, episode.Size, episode.DateAdded)
-				if err != nil {
-					log.WithFields(log.Fields{
-						"error":   err,
-						"show":    s.Title,
-						"season":  episode.Season,
-						"episode": episode.Episode,
-					}).Error("Failed to create episode node")
-					continue
-				}
-
-				files := []*papi.File{episode.NFO}
-				pfs.createFilesNodes(seasonDir, files, episode.DateAdded)
-				pfs.createSubtitlesNodes(seasonDir, episode.Path, episode.Subtitles, episode.DateAdded)
-			}
-		}
+		pfs.createShowNodes(showRootDir, s)
 	}
 
 	showRootDir.clear()
 
+	pfs.updateViews()
+
 	log.Debug("Shows updated")
 }
+
+// updateShow only updates the nodes of a show, the show nodes are removed if
+// it's not in the library anymore.
+func (pfs *polochonfs) updateShow(id string) {
+	log := log.WithField("imdb_id", id)
+
+	log.Debug("Fecthing show")
+	show, err := pfs.client.GetShow(id)
+	if err != nil && !errors.Is(err, papi.ErrResourceNotFound) {
+		log.WithField("error", err).Error("Failed to get show")
+		return
+	}
+
+	showRootDir := pfs.createDirNode(pfs.root, showDirName, pfs.root.times)
+	showRootDir.invalidateResource(id)
+
+	if show != nil {
+		pfs.createShowNodes(showRootDir, show)
+	}
+
+	showRootDir.clear()
+
+	pfs.updateViews()
+
+	log.Debug("Show updated")
+}
+
+func (pfs *polochonfs) createShowNodes(showRootDir *node, s *papi.Show) {
+	showDirNode := pfs.createDirNode(showRootDir, s.Title, pfs.root.times)
+	showDirNode.resource = s
+
+	files := []*papi.File{s.Fanart, s.Banner, s.Poster, s.NFO}
+	pfs.createFilesNodes(showDirNode, files, showDirNode.times)
+
+	for _, season := range s.Seasons {
+		name := fmt.Sprintf("Season %d", season.Season)
+		seasonDir := pfs.createDirNode(showDirNode, name, showDirNode.times)
+		seasonDir.resource = season
+
+		for _, episode := range season.Episodes {
+			episodeNode, err := pfs.createFileNode(seasonDir, episode, episode.Path, episode.Size, episode.DateAdded)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"error":   err,
+					"show":    s.Title,
+					"season":  episode.Season,
+					"episode": episode.Episode,
+				}).Error("Failed to create episode node")
+				continue
+			}
+			episodeNode.resource = episode
+
+			files := []*papi.File{episode.NFO}
+			pfs.createFilesNodes(seasonDir, files, episode.DateAdded)
+			pfs.createSubtitlesNodes(seasonDir, episode.Path, episode.Subtitles, episode.DateAdded)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/odwrtw/polochon/lib/papi"
+	log "github.com/sirupsen/logrus"
+)
+
+// Available virtual views.
+const (
+	viewGenre   = "genre"
+	viewYear    = "year"
+	viewRecent  = "recent"
+	viewQuality = "quality"
+)
+
+// viewDirNames holds the directory name of each view.
+var viewDirNames = map[string]string{
+	viewGenre:   "movies-by-genre",
+	viewYear:    "movies-by-year",
+	viewRecent:  "recently-added",
+	viewQuality: "by-quality",
+}
+
+// viewLink represents a symlink of a virtual view.
+type viewLink struct {
+	// dir is the sub directory of the link in the view, empty for the root
+	// of the view.
+	dir  string
+	name string
+	// target is the path of the target from the root of the mount.
+	target string
+	times  time.Time
+}
+
+// parseViews parses a comma separated list of views.
+func parseViews(value string) ([]string, error) {
+	views := []string{}
+	for view := range strings.SplitSeq(value, ",") {
+		view = strings.TrimSpace(view)
+		if view == "" {
+			continue
+		}
+
+		if _, ok := viewDirNames[view]; !ok {
+			return nil, fmt.Errorf("invalid view %q", view)
+		}
+
+		if !slices.Contains(views, view) {
+			views = append(views, view)
+		}
+	}
+
+	return views, nil
+}
+
+// sanitizeName replaces the "/" in the names with a "-".
+func sanitizeName(name string) string {
+	return strings.ReplaceAll(name, "/", "-")
+}
+
+// viewLinks returns the links of the views built from the movies and shows
+// trees, the recent view holds the videos added after the since date.
+func viewLinks(movies, shows *node, since time.Time) map[string][]viewLink {
+	links := map[string][]viewLink{}
+
+	if movies != nil {
+		for _, dir := range movies.childNodes() {
+			m, ok := dir.resource.(*papi.Movie)
+			if !ok {
+				continue
+			}
+
+			link := viewLink{
+				name:   dir.name,
+				target: path.Join(movieDirName, dir.name),
+				times:  dir.times,
+			}
+
+			for _, genre := range m.Genres {
+				link.dir = sanitizeName(genre)
+				links[viewGenre] = append(links[viewGenre], link)
+			}
+
+			if m.Year != 0 {
+				link.dir = strconv.Itoa(m.Year)
+				links[viewYear] = append(links[viewYear], link)
+			}
+
+			if m.DateAdded.After(since) {
+				link.dir = ""
+				links[viewRecent] = append(links[viewRecent], link)
+			}
+
+			if m.Quality != "" {
+				link.dir = sanitizeName(string(m.Quality))
+				links[viewQuality] = append(links[viewQuality], link)
+			}
+		}
+	}
+
+	if shows != nil {
+		for _, show := range shows.childNodes() {
+			for _, season := range show.childNodes() {
+				for _, file := range season.childNodes() {
+					e, ok := file.resource.(*papi.Episode)
+					if !ok {
+						continue
+					}
+
+					link := viewLink{
+						name:   file.name,
+						target: path.Join(showDirName, show.name, season.name, file.name),
+						times:  file.times,
+					}
+
+					if e.DateAdded.After(since) {
+						links[viewRecent] = append(links[viewRecent], link)
+					}
+
+					if e.Quality != "" {
+						link.dir = sanitizeName(string(e.Quality))
+						links[viewQuality] = append(links[viewQuality], link)
+					}
+				}
+			}
+		}
+	}
+
+	return links
+}
+
+// updateViews updates the virtual views from the movies and shows trees.
+func (pfs *polochonfs) updateViews() {
+	if len(views) == 0 {
+		return
+	}
+
+	since := time.Now().Add(-time.Duration(recentDays) * 24 * time.Hour)
+	links := viewLinks(pfs.root.getChild(movieDirName), pfs.root.getChild(showDirName), since)
+
+	for _, view := range views {
+		pfs.updateView(viewDirNames[view], links[view])
+	}
+
+	log.Debug("Views updated")
+}
+
+// updateView updates the links of a view, the links no longer in the view
+// are removed.
+func (pfs *polochonfs) updateView(name string, links []viewLink) {
+	viewDir := pfs.createDirNode(pfs.root, name, pfs.root.times)
+	viewDir.invalidate()
+	viewDir.valid = true
+
+	for _, link := range links {
+		parent, depth := viewDir, 1
+		if link.dir != "" {
+			parent = pfs.createDirNode(viewDir, link.dir, pfs.root.times)
+			depth = 2
+		}
+
+		target := strings.Repeat("../", depth) + link.target
+		pfs.addOrUpdateLinkNode(parent, link.name, target, link.times)
+	}
+
+	viewDir.clear()
+}
+
+func (pfs *polochonfs) addOrUpdateLinkNode(parent *node, name, target string, times time.Time) {
+	linkNode := parent.getChild(name)
+	if linkNode == nil {
+		linkNode = newNodeLink(name, target)
+		linkNode.times = times
+		linkNode.valid = true
+		parent.addChild(linkNode)
+		return
+	}
+
+	linkNode.times = times
+	linkNode.valid = true
+	if linkNode.target == target {
+		return
+	}
+
+	linkNode.target = target
+	_ = linkNode.NotifyContent(0, 0)
+}
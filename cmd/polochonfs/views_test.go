@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/papi"
+)
+
+func TestParseViews(t *testing.T) {
+	got, err := parseViews(" genre,recent,, genre ")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expected := []string{viewGenre, viewRecent}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if _, err := parseViews("genre,actor"); err == nil {
+		t.Error("expected an error for an invalid view")
+	}
+}
+
+func TestViewLinks(t *testing.T) {
+	now := time.Now()
+	old := now.AddDate(-1, 0, 0)
+
+	movies := newNodeDir(movieDirName, old)
+	movie := newNodeDir("Movie (2000)", now)
+	movie.resource = &papi.Movie{Movie: &polochon.Movie{
+		BaseVideo: polochon.BaseVideo{VideoMetadata: polochon.VideoMetadata{
+			DateAdded: now,
+			Quality:   polochon.Quality1080p,
+		}},
+		Year:   2000,
+		Genres: []string{"Action", "Sci/Fi"},
+	}}
+	movies.addChildNode(movie)
+
+	shows := newNodeDir(showDirName, old)
+	show := newNodeDir("Show", old)
+	season := newNodeDir("Season 1", old)
+	episode := newEpisodeNode("show.s01e01.mkv", 1)
+	episode.times = old
+	episode.resource.(*papi.Episode).DateAdded = old
+	episode.resource.(*papi.Episode).Quality = polochon.Quality720p
+	season.addChildNode(episode)
+	season.addChildNode(newNodeFile("show.s01e01.nfo"))
+	show.addChildNode(season)
+	shows.addChildNode(show)
+
+	got := viewLinks(movies, shows, now.AddDate(0, 0, -30))
+
+	movieLink := func(dir string) viewLink {
+		return viewLink{dir: dir, name: "Movie (2000)", target: "movies/Movie (2000)", times: now}
+	}
+
+	expected := map[string][]viewLink{
+		viewGenre:  {movieLink("Action"), movieLink("Sci-Fi")},
+		viewYear:   {movieLink("2000")},
+		viewRecent: {movieLink("")},
+		viewQuality: {
+			movieLink("1080p"),
+			{
+				dir:    "720p",
+				name:   "show.s01e01.mkv",
+				target: "tvshows/Show/Season 1/show.s01e01.mkv",
+				times:  old,
+			},
+		},
+	}
+
+	for _, links := range got {
+		slices.SortFunc(links, func(a, b viewLink) int {
+			return strings.Compare(a.dir, b.dir)
+		})
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
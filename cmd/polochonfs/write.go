@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hanwen/go-fuse/v2/fs"
+	"github.com/hanwen/go-fuse/v2/fuse"
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/papi"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	_ = (fs.NodeUnlinker)((*node)(nil))
+	_ = (fs.NodeRmdirer)((*node)(nil))
+	_ = (fs.NodeCreater)((*node)(nil))
+	_ = (fs.NodeSetattrer)((*node)(nil))
+
+	_ = (fs.FileWriter)((*subtitleHandle)(nil))
+	_ = (fs.FileFlusher)((*subtitleHandle)(nil))
+)
+
+// subtitleExt is the extension of the files uploaded as subtitles.
+const subtitleExt = ".srt"
+
+// maxSubtitleSize is the largest subtitle accepted, the subtitles being
+// written are kept in memory.
+const maxSubtitleSize = 5 * 1024 * 1024
+
+func (n *node) Unlink(_ context.Context, name string) syscall.Errno {
+	return n.remove(name, false)
+}
+
+func (n *node) Rmdir(_ context.Context, name string) syscall.Errno {
+	return n.remove(name, true)
+}
+
+// remove deletes the resource of a child from the library, only the movies,
+// shows, seasons and episodes can be deleted.
+func (n *node) remove(name string, isDir bool) syscall.Errno {
+	if !allowWrite {
+		return syscall.EROFS
+	}
+
+	child := n.getChild(name)
+	switch {
+	case child == nil:
+		return syscall.ENOENT
+	case child.isDir && !isDir:
+		return syscall.EISDIR
+	case !child.isDir && isDir:
+		return syscall.ENOTDIR
+	case child.resource == nil:
+		return syscall.EPERM
+	}
+
+	l := log.WithFields(log.Fields{
+		"parent": n.name,
+		"name":   name,
+	})
+	l.Info("Deleting from the library")
+
+	if err := n.pfs.client.Delete(child.resource); err != nil {
+		l.WithField("error", err).Error("Failed to delete from the library")
+		if errors.Is(err, papi.ErrResourceNotFound) {
+			return syscall.ENOENT
+		}
+		return syscall.EIO
+	}
+
+	// The inode is removed by go-fuse once the call succeeds
+	n.mu.Lock()
+	delete(n.children, name)
+	n.mu.Unlock()
+
+	return 0
+}
+
+// subtitleVideo returns the video and the language of a subtitle file created
+// in the directory, the video is the one whose subtitle path matches the file
+// name, or the only video of the directory.
+func (n *node) subtitleVideo(name string) (polochon.Video, polochon.Language, syscall.Errno) {
+	if !strings.HasSuffix(name, subtitleExt) {
+		return nil, "", syscall.EPERM
+	}
+
+	lang, err := polochon.LanguageFromFilename(name)
+	if err != nil {
+		return nil, "", syscall.EINVAL
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	var videos []polochon.Video
+	for _, child := range n.children {
+		video, ok := child.resource.(polochon.Video)
+		if !ok || child.isDir {
+			continue
+		}
+
+		if polochon.NewFile(child.name).SubtitlePath(lang) == name {
+			return video, lang, 0
+		}
+
+		videos = append(videos, video)
+	}
+
+	if len(videos) != 1 {
+		return nil, "", syscall.EINVAL
+	}
+
+	return videos[0], lang, 0
+}
+
+func (n *node) Create(ctx context.Context, name string, _ uint32, _ uint32, out *fuse.EntryOut) (*fs.Inode, fs.FileHandle, uint32, syscall.Errno) {
+	if !allowWrite {
+		return nil, nil, 0, syscall.EROFS
+	}
+
+	video, lang, errno := n.subtitleVideo(name)
+	if errno != 0 {
+		return nil, nil, 0, errno
+	}
+
+	child := n.getChild(name)
+	if child == nil {
+		child = newNodeFile(name)
+		child.times = time.Now()
+		child.pfs = n.pfs
+		n.NewInode(ctx, child, fs.StableAttr{Mode: syscall.S_IFREG})
+		// The inode is added to the tree by go-fuse once the call succeeds
+		n.addChildNode(child)
+	}
+
+	child.updateAttr(&out.Attr)
+	return &child.Inode, newSubtitleHandle(child, video, lang), 0, 0
+}
+
+// openSubtitle opens an existing subtitle in write mode, the subtitle is
+// replaced by the content written.
+func (n *node) openSubtitle(flags uint32) (fs.FileHandle, syscall.Errno) {
+	if !allowWrite {
+		return nil, syscall.EROFS
+	}
+
+	if flags&syscall.O_TRUNC == 0 {
+		return nil, syscall.ENOTSUP
+	}
+
+	_, parentInode := n.Parent()
+	if parentInode == nil {
+		return nil, syscall.ENOENT
+	}
+
+	parent, ok := parentInode.Operations().(*node)
+	if !ok {
+		return nil, syscall.EIO
+	}
+
+	video, lang, errno := parent.subtitleVideo(n.name)
+	if errno != 0 {
+		return nil, errno
+	}
+
+	return newSubtitleHandle(n, video, lang), 0
+}
+
+func (n *node) Setattr(_ context.Context, f fs.FileHandle, in *fuse.SetAttrIn, out *fuse.AttrOut) syscall.Errno {
+	if size, ok := in.GetSize(); ok {
+		h, ok := f.(*subtitleHandle)
+		if !ok {
+			return syscall.EPERM
+		}
+		if errno := h.truncate(size); errno != 0 {
+			return errno
+		}
+	}
+
+	// The other attributes are not stored
+	n.updateAttr(&out.Attr)
+	return 0
+}
+
+// subtitleHandle is the handle of a subtitle being written, the subtitle is
+// uploaded when the file is flushed after being written.
+type subtitleHandle struct {
+	node  *node
+	video polochon.Video
+	lang  polochon.Language
+
+	mu    sync.Mutex
+	data  []byte
+	dirty bool
+}
+
+func newSubtitleHandle(n *node, video polochon.Video, lang polochon.Language) *subtitleHandle {
+	return &subtitleHandle{
+		node:  n,
+		video: video,
+		lang:  lang,
+	}
+}
+
+func (h *subtitleHandle) truncate(size uint64) syscall.Errno {
+	if size > maxSubtitleSize {
+		return syscall.EFBIG
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if int(size) <= len(h.data) {
+		h.data = h.data[:size]
+	} else {
+		h.data = append(h.data, make([]byte, int(size)-len(h.data))...)
+	}
+	h.node.setSize(size)
+	h.dirty = true
+	return 0
+}
+
+func (h *subtitleHandle) Write(_ context.Context, data []byte, offset int64) (uint32, syscall.Errno) {
+	if offset < 0 || offset+int64(len(data)) > maxSubtitleSize {
+		return 0, syscall.EFBIG
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	end := int(offset) + len(data)
+	if end > len(h.data) {
+		h.data = append(h.data, make([]byte, end-len(h.data))...)
+	}
+	copy(h.data[offset:], data)
+
+	h.node.setSize(uint64(len(h.data)))
+	h.dirty = true
+	return uint32(len(data)), 0
+}
+
+func (h *subtitleHandle) Flush(_ context.Context) syscall.Errno {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.dirty {
+		return 0
+	}
+
+	l := log.WithFields(log.Fields{
+		"name": h.node.name,
+		"lang": h.lang,
+		"size": len(h.data),
+	})
+
+	// An empty subtitle would replace the one of the library
+	if len(h.data) == 0 {
+		l.Warn("Refusing to upload an empty subtitle")
+		return syscall.EINVAL
+	}
+
+	l.Info("Uploading subtitle")
+
+	sub, err := h.node.pfs.client.UploadSubtitle(h.video, h.lang, bytes.NewReader(h.data))
+	if err != nil {
+		l.WithField("error", err).Error("Failed to upload subtitle")
+		return syscall.EIO
+	}
+
+	url, err := h.node.pfs.client.DownloadURL(sub)
+	if err != nil {
+		l.WithField("error", err).Error("Failed to get subtitle URL")
+		return syscall.EIO
+	}
+
+	h.node.update(uint64(len(h.data)), time.Now(), url)
+	h.dirty = false
+	return 0
+}
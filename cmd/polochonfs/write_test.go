@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/papi"
+)
+
+func newEpisodeNode(name string, episode int) *node {
+	n := newNodeFile(name)
+	n.resource = &papi.Episode{ShowEpisode: &polochon.ShowEpisode{
+		ShowImdbID: "tt001",
+		Season:     1,
+		Episode:    episode,
+	}}
+	return n
+}
+
+func TestSubtitleVideo(t *testing.T) {
+	season := newNodeDir("Season 1", time.Time{})
+	for i, name := range []string{"show.s01e01.mkv", "show.s01e02.mp4"} {
+		season.addChildNode(newEpisodeNode(name, i+1))
+	}
+	season.addChildNode(newNodeFile("show.s01e01.nfo"))
+
+	movie := newNodeDir("Movie (2000)", time.Time{})
+	movie.addChildNode(newEpisodeNode("movie.mkv", 1))
+
+	for _, test := range []struct {
+		name            string
+		dir             *node
+		file            string
+		expectedEpisode int
+		expectedLang    polochon.Language
+		expectedErrno   syscall.Errno
+	}{
+		{
+			name:            "matching video",
+			dir:             season,
+			file:            "show.s01e02.fr.srt",
+			expectedEpisode: 2,
+			expectedLang:    polochon.FR,
+		},
+		{
+			name:            "single video",
+			dir:             movie,
+			file:            "subs.english.srt",
+			expectedEpisode: 1,
+			expectedLang:    polochon.EN,
+		},
+		{
+			name:          "ambiguous video",
+			dir:           season,
+			file:          "subs.en.srt",
+			expectedErrno: syscall.EINVAL,
+		},
+		{
+			name:          "missing language",
+			dir:           movie,
+			file:          "movie.srt",
+			expectedErrno: syscall.EINVAL,
+		},
+		{
+			name:          "not a subtitle",
+			dir:           movie,
+			file:          "movie.en.txt",
+			expectedErrno: syscall.EPERM,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			video, lang, errno := test.dir.subtitleVideo(test.file)
+			if errno != test.expectedErrno {
+				t.Fatalf("expected errno %v, got %v", test.expectedErrno, errno)
+			}
+
+			if errno != 0 {
+				return
+			}
+
+			if lang != test.expectedLang {
+				t.Errorf("expected lang %q, got %q", test.expectedLang, lang)
+			}
+
+			episode := video.(*papi.Episode)
+			if episode.Episode != test.expectedEpisode {
+				t.Errorf("expected episode %d, got %d", test.expectedEpisode, episode.Episode)
+			}
+		})
+	}
+}
+
+func TestSubtitleHandleEmptyFlush(t *testing.T) {
+	n := newNodeFile("movie.fr.srt")
+	video := newEpisodeNode("movie.mkv", 1).resource.(polochon.Video)
+
+	// Nothing written, nothing to upload
+	h := newSubtitleHandle(n, video, polochon.FR)
+	if errno := h.Flush(context.Background()); errno != 0 {
+		t.Fatalf("expected no error, got %v", errno)
+	}
+
+	// An emptied subtitle is not uploaded
+	h.truncate(0)
+	if errno := h.Flush(context.Background()); errno != syscall.EINVAL {
+		t.Fatalf("expected %v, got %v", syscall.EINVAL, errno)
+	}
+}
+
+func TestSubtitleHandleTooLarge(t *testing.T) {
+	n := newNodeFile("movie.fr.srt")
+	video := newEpisodeNode("movie.mkv", 1).resource.(polochon.Video)
+	h := newSubtitleHandle(n, video, polochon.FR)
+
+	if _, errno := h.Write(context.Background(), []byte("data"), maxSubtitleSize); errno != syscall.EFBIG {
+		t.Fatalf("expected %v, got %v", syscall.EFBIG, errno)
+	}
+
+	if errno := h.truncate(maxSubtitleSize + 1); errno != syscall.EFBIG {
+		t.Fatalf("expected %v, got %v", syscall.EFBIG, errno)
+	}
+
+	if n.getSize() != 0 {
+		t.Errorf("expected an empty file, got %d bytes", n.getSize())
+	}
+
+	if _, errno := h.Write(context.Background(), []byte("data"), 2); errno != 0 {
+		t.Fatalf("expected no error, got %v", errno)
+	}
+
+	if n.getSize() != 6 {
+		t.Errorf("expected 6 bytes, got %d", n.getSize())
+	}
+}
@@ -35,9 +35,15 @@ func NewShowCalendar(imdbID string) *ShowCalendar {
 
 // ShowCalendarEpisode holds the episode calendar infos
 type ShowCalendarEpisode struct {
-	Season    int
-	Episode   int
-	AiredDate *time.Time
+	Season         int
+	Episode        int
+	AbsoluteNumber int
+	AiredDate      *time.Time
+}
+
+// IsSpecial returns true if the episode is a special
+func (sc *ShowCalendarEpisode) IsSpecial() bool {
+	return sc.Season == 0
 }
 
 // IsAvailable tells if the episode is currently available
@@ -50,8 +56,23 @@ func (sc *ShowCalendarEpisode) IsAvailable() bool {
 	return sc.AiredDate.Before(time.Now())
 }
 
-// IsOlder returns true if the given show is older than the calendar episode
+// IsRecent tells if the episode aired during the last days, the episodes
+// without aired date are never recent
+func (sc *ShowCalendarEpisode) IsRecent(days int) bool {
+	if sc.AiredDate == nil || sc.AiredDate.IsZero() || !sc.IsAvailable() {
+		return false
+	}
+
+	return time.Since(*sc.AiredDate) < time.Duration(days)*24*time.Hour
+}
+
+// IsOlder returns true if the given show is older than the calendar episode,
+// the specials are never older as they're not ordered with the seasons
 func (sc *ShowCalendarEpisode) IsOlder(ws *WishedShow) bool {
+	if sc.IsSpecial() {
+		return false
+	}
+
 	if sc.Season < ws.Season {
 		return true
 	}
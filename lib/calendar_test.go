@@ -75,6 +75,12 @@ func TestShowCalendarIsOlder(t *testing.T) {
 			wishedShow: &WishedShow{Season: 2, Episode: 10},
 			expected:   true,
 		},
+		{
+			name:       "specials are never older",
+			episode:    &ShowCalendarEpisode{Season: 0, Episode: 1},
+			wishedShow: &WishedShow{Season: 2, Episode: 10},
+			expected:   false,
+		},
 	}
 
 	for _, tc := range tt {
@@ -85,3 +91,45 @@ func TestShowCalendarIsOlder(t *testing.T) {
 		})
 	}
 }
+
+func TestShowCalendarEpisodeIsRecent(t *testing.T) {
+	now := time.Now()
+	yesterday := now.Add(-24 * time.Hour)
+	lastMonth := now.Add(-30 * 24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+
+	tt := []struct {
+		episode  *ShowCalendarEpisode
+		expected bool
+		name     string
+	}{
+		{
+			name:     "episode aired yesterday",
+			episode:  &ShowCalendarEpisode{AiredDate: &yesterday},
+			expected: true,
+		},
+		{
+			name:     "episode aired last month",
+			episode:  &ShowCalendarEpisode{AiredDate: &lastMonth},
+			expected: false,
+		},
+		{
+			name:     "episode in the future",
+			episode:  &ShowCalendarEpisode{AiredDate: &tomorrow},
+			expected: false,
+		},
+		{
+			name:     "episode with no aired date",
+			episode:  &ShowCalendarEpisode{AiredDate: nil},
+			expected: false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.episode.IsRecent(7) != tc.expected {
+				t.Error("incorrect result")
+			}
+		})
+	}
+}
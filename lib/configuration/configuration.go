@@ -6,6 +6,9 @@ import (
 	"time"
 
 	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/history"
+	"github.com/odwrtw/polochon/lib/progress"
+	"github.com/odwrtw/polochon/lib/unmatched"
 	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
@@ -51,8 +54,21 @@ func (c *Config) UnmarshalYAML(unmarshal func(any) error) error {
 
 // LibraryConfig represents configuration for the library
 type LibraryConfig struct {
-	MovieDir string
-	ShowDir  string
+	MovieDir        string
+	ShowDir         string
+	IndexPath       string
+	FsNotifier      polochon.FsNotifier
+	Naming          NamingConfig
+	MetadataRefresh MetadataRefreshConfig
+	Progress        *progress.Store
+}
+
+// MetadataRefreshConfig represents the configuration of the scheduled
+// metadata refresh of the library
+type MetadataRefreshConfig struct {
+	Enabled  bool
+	Schedule cron.Schedule
+	Fields   []string
 }
 
 // WatcherConfig represents the configuration for the detailers
@@ -67,19 +83,66 @@ type DownloaderConfig struct {
 	LaunchAtStartup bool
 	Schedule        cron.Schedule
 	Client          polochon.Downloader
+	TorrentPolicy   polochon.TorrentPolicy
+	History         *history.Store
+	DeleteWatched   DeleteWatchedConfig
+}
+
+// DeleteWatchedConfig represents the configuration of the deletion of the
+// watched episodes
+type DeleteWatchedConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Retention is the delay to wait after an episode has been watched
+	Retention time.Duration `yaml:"retention"`
 }
 
 // DownloadManagerConfig represents the configuration for the download manager
 type DownloadManagerConfig struct {
-	Enabled bool          `yaml:"enabled"`
-	Dir     string        `yaml:"dir"`
-	Timer   time.Duration `yaml:"timer"`
-	Ratio   float32       `yaml:"ratio"`
+	Enabled    bool          `yaml:"enabled"`
+	Dir        string        `yaml:"dir"`
+	Timer      time.Duration `yaml:"timer"`
+	Ratio      float32       `yaml:"ratio"`
+	ImportMode ImportMode    `yaml:"import_mode"`
+}
+
+// ImportMode represents the way the downloaded files are imported into the
+// library
+type ImportMode string
+
+// Available import modes
+const (
+	// ImportMove moves the file into the library, a symlink is left in its
+	// place when the downloader is enabled
+	ImportMove ImportMode = "move"
+	// ImportCopy copies the file into the library
+	ImportCopy ImportMode = "copy"
+	// ImportHardlink creates a hard link of the file in the library, the
+	// library and the downloads must be on the same filesystem
+	ImportHardlink ImportMode = "hardlink"
+	// ImportReflink creates a copy on write clone of the file in the library
+	// and falls back to a copy if the filesystem does not support it
+	ImportReflink ImportMode = "reflink"
+)
+
+// IsValid returns true if the import mode is known
+func (m ImportMode) IsValid() bool {
+	switch m {
+	case ImportMove, ImportCopy, ImportHardlink, ImportReflink:
+		return true
+	default:
+		return false
+	}
+}
+
+// KeepsSource returns true if the import mode leaves the source file in place
+func (m ImportMode) KeepsSource() bool {
+	return m != ImportMove
 }
 
 // OrganizerConfig represents the configuration for the organizer
 type OrganizerConfig struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled   bool
+	Unmatched *unmatched.Store
 }
 
 // HTTPServer represents the configuration for the HTTP Server
@@ -3,7 +3,9 @@ package configuration
 import (
 	"bytes"
 	"reflect"
+	"strings"
 	"testing"
+	"text/template"
 	"time"
 
 	polochon "github.com/odwrtw/polochon/lib"
@@ -22,11 +24,20 @@ downloader:
   launch_at_startup: true
   schedule: "@every 4h"
   client: mock
+  torrent_policy:
+    min_seeders: 5
+    size_limits:
+      1080p:
+        min: 1000
+        max: 8000
+    banned_keywords:
+    - CAM
 download_manager:
   enabled: true
   timer: 30s
   ratio: 0
   dir: /downloads
+  import_mode: hardlink
 http_server:
   enable: true
   port: 8080
@@ -74,6 +85,15 @@ show:
     - mock
   subtitlers:
     - mock
+library:
+  naming:
+    movie_file: "{{.Title}}.{{.Quality}}"
+  metadata_refresh:
+    enabled: true
+    schedule: "@every 168h"
+    fields:
+    - plot
+    - rating
 movie:
   dir: /tmp
   torrenters:
@@ -103,6 +123,35 @@ func TestReadConfig(t *testing.T) {
 	}
 	got.Logger = nil
 
+	// The unmatched queue is always opened
+	if got.Organizer.Unmatched == nil {
+		t.Fatalf("got a nil unmatched queue")
+	}
+	got.Organizer.Unmatched = nil
+
+	// The templates can't be compared, check their output instead
+	naming := map[string]*template.Template{
+		"Title (2000)": got.Library.Naming.MovieDir,
+		"Title.1080p":  got.Library.Naming.MovieFile,
+		"Show":         got.Library.Naming.ShowDir,
+		"Season 1":     got.Library.Naming.SeasonDir,
+	}
+	for expected, tmpl := range naming {
+		var out strings.Builder
+		if err := tmpl.Execute(&out, sampleNamingData); err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+
+		if out.String() != expected {
+			t.Errorf("invalid naming template output, expected %q got %q", expected, out.String())
+		}
+	}
+
+	if got.Library.Naming.EpisodeFile != nil {
+		t.Errorf("expected no episode file template")
+	}
+	got.Library.Naming = NamingConfig{}
+
 	expected := &Config{
 		Watcher: WatcherConfig{
 			Dir:        "/downloads/todo",
@@ -115,12 +164,20 @@ func TestReadConfig(t *testing.T) {
 				Delay: 4 * time.Hour,
 			},
 			Client: mock,
+			TorrentPolicy: polochon.TorrentPolicy{
+				MinSeeders: 5,
+				SizeLimits: map[polochon.Quality]polochon.SizeLimit{
+					polochon.Quality1080p: {Min: 1000, Max: 8000},
+				},
+				BannedKeywords: []string{"CAM"},
+			},
 		},
 		DownloadManager: DownloadManagerConfig{
-			Enabled: true,
-			Dir:     "/downloads",
-			Timer:   30 * time.Second,
-			Ratio:   0,
+			Enabled:    true,
+			Dir:        "/downloads",
+			Timer:      30 * time.Second,
+			Ratio:      0,
+			ImportMode: ImportHardlink,
 		},
 		HTTPServer: HTTPServer{
 			Enable:            true,
@@ -156,6 +213,13 @@ func TestReadConfig(t *testing.T) {
 		Library: LibraryConfig{
 			MovieDir: "/tmp",
 			ShowDir:  "/tmp",
+			MetadataRefresh: MetadataRefreshConfig{
+				Enabled: true,
+				Schedule: cron.ConstantDelaySchedule{
+					Delay: 168 * time.Hour,
+				},
+				Fields: []string{"plot", "rating"},
+			},
 		},
 		Notifiers:         []polochon.Notifier{mock},
 		SubtitleLanguages: []polochon.Language{"fr_FR", "en_US"},
@@ -165,3 +229,23 @@ func TestReadConfig(t *testing.T) {
 		t.Fatalf("invalid configuration\ngot:\n%+v\nexpected:\n%+v", got, expected)
 	}
 }
+
+func TestNamingTemplates(t *testing.T) {
+	for _, c := range []struct {
+		name      string
+		templates namingTemplates
+		valid     bool
+	}{
+		{name: "defaults", valid: true},
+		{name: "valid", templates: namingTemplates{EpisodeFile: `{{.ShowTitle}} - S{{printf "%02d" .Season}}E{{printf "%02d" .Episode}}`}, valid: true},
+		{name: "invalid syntax", templates: namingTemplates{MovieDir: "{{.Title"}},
+		{name: "unknown field", templates: namingTemplates{ShowDir: "{{.Name}}"}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := c.templates.parse()
+			if c.valid != (err == nil) {
+				t.Fatalf("expected valid to be %t, got error %v", c.valid, err)
+			}
+		})
+	}
+}
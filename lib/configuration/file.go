@@ -2,16 +2,23 @@ package configuration
 
 import (
 	"errors"
+	"fmt"
 
 	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/history"
+	"github.com/odwrtw/polochon/lib/progress"
+	"github.com/odwrtw/polochon/lib/unmatched"
 	"github.com/robfig/cron/v3"
 )
 
 type configFile struct {
 	modulesParams *ModulesParams
 
-	Logs      Logger          `yaml:"logs"`
-	Organizer OrganizerConfig `yaml:"organizer"`
+	Logs      Logger `yaml:"logs"`
+	Organizer struct {
+		Enabled   bool             `yaml:"enabled"`
+		Unmatched unmatched.Config `yaml:"unmatched"`
+	} `yaml:"organizer"`
 
 	Watcher struct {
 		ModuleLoader `yaml:",inline"`
@@ -20,9 +27,12 @@ type configFile struct {
 
 	Downloader struct {
 		ModuleLoader    `yaml:",inline"`
-		LaunchAtStartup bool   `yaml:"launch_at_startup"`
-		Enabled         bool   `yaml:"enabled"`
-		Schedule        string `yaml:"schedule"`
+		LaunchAtStartup bool                   `yaml:"launch_at_startup"`
+		Enabled         bool                   `yaml:"enabled"`
+		Schedule        string                 `yaml:"schedule"`
+		TorrentPolicy   polochon.TorrentPolicy `yaml:"torrent_policy"`
+		History         history.Config         `yaml:"history"`
+		DeleteWatched   DeleteWatchedConfig    `yaml:"delete_watched_episodes"`
 	} `yaml:"downloader"`
 
 	DownloadManager DownloadManagerConfig `yaml:"download_manager"`
@@ -47,6 +57,19 @@ type configFile struct {
 		Dir          string `yaml:"dir"`
 	} `yaml:"movie"`
 
+	Library struct {
+		ModuleLoader `yaml:",inline"`
+		IndexPath    string          `yaml:"index_path"`
+		Naming       namingTemplates `yaml:"naming"`
+		Progress     progress.Config `yaml:"progress"`
+
+		MetadataRefresh struct {
+			Enabled  bool     `yaml:"enabled"`
+			Schedule string   `yaml:"schedule"`
+			Fields   []string `yaml:"fields"`
+		} `yaml:"metadata_refresh"`
+	} `yaml:"library"`
+
 	Wishlist struct {
 		ModuleLoader          `yaml:",inline"`
 		ShowDefaultQualities  []polochon.Quality `yaml:"show_default_qualities"`
@@ -58,6 +81,7 @@ func loadConfig(cf *configFile, conf *Config) error {
 	// Load the configs in the module loaders
 	for _, ml := range []*ModuleLoader{
 		&cf.Downloader.ModuleLoader,
+		&cf.Library.ModuleLoader,
 		&cf.Movie.ModuleLoader,
 		&cf.Show.ModuleLoader,
 		&cf.Video.ModuleLoader,
@@ -79,7 +103,9 @@ func loadConfig(cf *configFile, conf *Config) error {
 		}
 	}
 
-	conf.Organizer = cf.Organizer
+	conf.Organizer = OrganizerConfig{
+		Enabled: cf.Organizer.Enabled,
+	}
 	conf.Logger = cf.Logs.logger
 	conf.Watcher = WatcherConfig{
 		Dir:        cf.Watcher.Dir,
@@ -90,6 +116,8 @@ func loadConfig(cf *configFile, conf *Config) error {
 		LaunchAtStartup: cf.Downloader.LaunchAtStartup,
 		Schedule:        schedule,
 		Client:          cf.Downloader.downloader,
+		TorrentPolicy:   cf.Downloader.TorrentPolicy,
+		DeleteWatched:   cf.Downloader.DeleteWatched,
 	}
 	conf.DownloadManager = cf.DownloadManager
 	conf.HTTPServer = cf.HTTPServer
@@ -119,7 +147,10 @@ func loadConfig(cf *configFile, conf *Config) error {
 		AllowedExtensionsToDelete: cf.Video.AllowedExtensionsToDelete,
 		Guessers:                  cf.Video.guessers,
 	}
-	conf.Library = LibraryConfig{}
+	conf.Library = LibraryConfig{
+		IndexPath:  cf.Library.IndexPath,
+		FsNotifier: cf.Library.fsNotifier,
+	}
 	conf.Notifiers = cf.Video.notifiers
 	conf.SubtitleLanguages = cf.Video.SubtitleLanguages
 
@@ -132,6 +163,83 @@ func loadConfig(cf *configFile, conf *Config) error {
 		return err
 	}
 
+	// Check the qualities of the torrent size limits
+	for quality := range conf.Downloader.TorrentPolicy.SizeLimits {
+		if err := checkQuality([]polochon.Quality{quality}); err != nil {
+			return err
+		}
+	}
+
+	// Move the downloaded files by default
+	if conf.DownloadManager.ImportMode == "" {
+		conf.DownloadManager.ImportMode = ImportMove
+	}
+
+	if !conf.DownloadManager.ImportMode.IsValid() {
+		return fmt.Errorf("configuration: invalid import mode %q", conf.DownloadManager.ImportMode)
+	}
+
+	// Check the scheduled metadata refresh
+	if mr := cf.Library.MetadataRefresh; mr.Enabled {
+		schedule, err := cron.ParseStandard(mr.Schedule)
+		if err != nil {
+			return errors.New("configuration: " + err.Error())
+		}
+
+		if err := polochon.ValidateDetailFields(mr.Fields); err != nil {
+			return fmt.Errorf("configuration: invalid metadata refresh fields: %w", err)
+		}
+
+		conf.Library.MetadataRefresh = MetadataRefreshConfig{
+			Enabled:  true,
+			Schedule: schedule,
+			Fields:   mr.Fields,
+		}
+	}
+
+	// Parse the naming templates
+	naming, err := cf.Library.Naming.parse()
+	if err != nil {
+		return err
+	}
+	conf.Library.Naming = naming
+
+	// Open the download history
+	if cf.Downloader.History.Path != "" {
+		store, err := history.Open(cf.Downloader.History)
+		if err != nil {
+			return err
+		}
+
+		conf.Downloader.History = store
+		conf.Downloader.TorrentPolicy.Blocklist = store
+	}
+
+	// Open the viewing progress store
+	if cf.Library.Progress.Path != "" {
+		store, err := progress.Open(cf.Library.Progress)
+		if err != nil {
+			return err
+		}
+
+		conf.Library.Progress = store
+	}
+
+	if conf.Downloader.DeleteWatched.Enabled && conf.Library.Progress == nil {
+		return errors.New("configuration: the watched episodes deletion requires the progress path")
+	}
+
+	if conf.Downloader.DeleteWatched.Retention < 0 {
+		return errors.New("configuration: invalid watched episodes retention")
+	}
+
+	// Open the unmatched files queue
+	queue, err := unmatched.Open(cf.Organizer.Unmatched)
+	if err != nil {
+		return err
+	}
+	conf.Organizer.Unmatched = queue
+
 	if err := evalSymlink(&conf.Library.MovieDir, cf.Movie.Dir); err != nil {
 		return err
 	}
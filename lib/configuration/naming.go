@@ -0,0 +1,137 @@
+package configuration
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+)
+
+// Default naming templates
+const (
+	DefaultMovieDirTemplate  = `{{.Title}}{{if .Year}} ({{.Year}}){{end}}`
+	DefaultShowDirTemplate   = `{{.ShowTitle}}`
+	DefaultSeasonDirTemplate = `Season {{.Season}}`
+)
+
+// NamingConfig holds the templates used to name the directories and files of
+// the library, the file templates don't include the extension and the
+// original file names are kept when they're nil
+type NamingConfig struct {
+	MovieDir    *template.Template
+	MovieFile   *template.Template
+	ShowDir     *template.Template
+	SeasonDir   *template.Template
+	EpisodeFile *template.Template
+}
+
+// NamingData holds the values available in the naming templates
+type NamingData struct {
+	// Title is the title of the movie or the episode
+	Title string
+	// Year is the year of the movie or the show
+	Year int
+	// ImdbID is the ID of the movie or the show
+	ImdbID    string
+	TmdbID    int
+	TvdbID    int
+	ShowTitle string
+	Season    int
+	Episode   int
+	// LastEpisode is the last episode of a multi-episode file, it's 0 for
+	// the other files
+	LastEpisode    int
+	AbsoluteNumber int
+	EpisodeImdbID  string
+	Quality        string
+	ReleaseGroup   string
+	VideoCodec     string
+	AudioCodec     string
+}
+
+// namingTemplates represents the naming templates in the configuration file
+type namingTemplates struct {
+	MovieDir    string `yaml:"movie_dir"`
+	MovieFile   string `yaml:"movie_file"`
+	ShowDir     string `yaml:"show_dir"`
+	SeasonDir   string `yaml:"season_dir"`
+	EpisodeFile string `yaml:"episode_file"`
+}
+
+// sampleNamingData is used to validate the templates
+var sampleNamingData = NamingData{
+	Title:          "Title",
+	Year:           2000,
+	ImdbID:         "tt0000000",
+	TmdbID:         1,
+	TvdbID:         1,
+	ShowTitle:      "Show",
+	Season:         1,
+	Episode:        1,
+	LastEpisode:    2,
+	AbsoluteNumber: 1,
+	EpisodeImdbID:  "tt0000001",
+	Quality:        "1080p",
+	ReleaseGroup:   "GROUP",
+	VideoCodec:     "x264",
+	AudioCodec:     "AAC",
+}
+
+func (n namingTemplates) parse() (NamingConfig, error) {
+	c := NamingConfig{}
+	for _, t := range []struct {
+		name  string
+		text  string
+		value string
+		tmpl  **template.Template
+	}{
+		{name: "movie_dir", text: n.MovieDir, value: DefaultMovieDirTemplate, tmpl: &c.MovieDir},
+		{name: "movie_file", text: n.MovieFile, tmpl: &c.MovieFile},
+		{name: "show_dir", text: n.ShowDir, value: DefaultShowDirTemplate, tmpl: &c.ShowDir},
+		{name: "season_dir", text: n.SeasonDir, value: DefaultSeasonDirTemplate, tmpl: &c.SeasonDir},
+		{name: "episode_file", text: n.EpisodeFile, tmpl: &c.EpisodeFile},
+	} {
+		text := t.text
+		if text == "" {
+			text = t.value
+		}
+
+		if text == "" {
+			continue
+		}
+
+		tmpl, err := template.New(t.name).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return c, fmt.Errorf("configuration: invalid %s naming template: %w", t.name, err)
+		}
+
+		if err := tmpl.Execute(io.Discard, sampleNamingData); err != nil {
+			return c, fmt.Errorf("configuration: invalid %s naming template: %w", t.name, err)
+		}
+
+		*t.tmpl = tmpl
+	}
+
+	return c, nil
+}
+
+// WithDefaults returns the naming configuration with the default templates
+// set in place of the missing ones
+func (c NamingConfig) WithDefaults() NamingConfig {
+	defaults, _ := namingTemplates{}.parse()
+	for _, t := range []struct {
+		tmpl *template.Template
+		def  **template.Template
+	}{
+		{tmpl: c.MovieDir, def: &defaults.MovieDir},
+		{tmpl: c.MovieFile, def: &defaults.MovieFile},
+		{tmpl: c.ShowDir, def: &defaults.ShowDir},
+		{tmpl: c.SeasonDir, def: &defaults.SeasonDir},
+		{tmpl: c.EpisodeFile, def: &defaults.EpisodeFile},
+	} {
+		if t.tmpl != nil {
+			*t.def = t.tmpl
+		}
+	}
+
+	return defaults
+}
@@ -2,12 +2,22 @@ package polochon
 
 import (
 	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
 
 	"github.com/sirupsen/logrus"
 )
 
-// ErrGettingDetails is returned if polochon failed to get details of the video
-var ErrGettingDetails = errors.New("polochon: failed to get details")
+// Custom errors
+var (
+	// ErrGettingDetails is returned if polochon failed to get details of the
+	// video
+	ErrGettingDetails = errors.New("polochon: failed to get details")
+	// ErrUnknownDetailField is returned when a detail field does not exist
+	ErrUnknownDetailField = errors.New("polochon: unknown detail field")
+)
 
 // Detailer is the interface to get details on a video or a show
 type Detailer interface {
@@ -47,3 +57,87 @@ func GetDetails(v Detailable, log *logrus.Entry) error {
 
 	return nil
 }
+
+// identityFields are the fields identifying a video, they're never updated
+var identityFields = map[string]struct{}{
+	"imdb_id":      {},
+	"show_imdb_id": {},
+	"season":       {},
+	"episode":      {},
+	"last_episode": {},
+}
+
+// detailFields returns the updatable fields of a detailable struct indexed by
+// their JSON name
+func detailFields(v reflect.Value) map[string]reflect.Value {
+	fields := map[string]reflect.Value{}
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if f.Anonymous || !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		if _, ok := identityFields[name]; ok {
+			continue
+		}
+
+		fields[name] = v.Field(i)
+	}
+
+	return fields
+}
+
+// IsDetailField returns true if the name is the JSON name of an updatable
+// field of a movie, a show or an episode
+func IsDetailField(name string) bool {
+	for _, v := range []any{Movie{}, Show{}, ShowEpisode{}} {
+		if _, ok := detailFields(reflect.ValueOf(v))[name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateDetailFields returns an error if one of the fields is not an
+// updatable field
+func ValidateDetailFields(fields []string) error {
+	for _, name := range fields {
+		if !IsDetailField(name) {
+			return fmt.Errorf("%w: %q", ErrUnknownDetailField, name)
+		}
+	}
+
+	return nil
+}
+
+// UpdateDetails copies the details of src into dst, both must be pointers to
+// the same type. Only the fields named in fields are copied if it's not empty,
+// the ids of the video are never copied.
+func UpdateDetails(dst, src any, fields []string) error {
+	d, s := reflect.ValueOf(dst), reflect.ValueOf(src)
+	if d.Kind() != reflect.Pointer || d.Type() != s.Type() || d.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("polochon: cannot update a %T from a %T", dst, src)
+	}
+
+	if err := ValidateDetailFields(fields); err != nil {
+		return err
+	}
+
+	srcFields := detailFields(s.Elem())
+	for name, f := range detailFields(d.Elem()) {
+		if len(fields) > 0 && !slices.Contains(fields, name) {
+			continue
+		}
+
+		f.Set(srcFields[name])
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package polochon
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateDetails(t *testing.T) {
+	current := func() *Movie {
+		return &Movie{
+			ImdbID: "tt001",
+			Title:  "Hand edited title",
+			Plot:   "Old plot",
+			Rating: 2,
+			Genres: []string{"Drama"},
+		}
+	}
+
+	fresh := &Movie{
+		ImdbID: "tt002",
+		Title:  "Title",
+		Plot:   "New plot",
+		Rating: 4,
+		Year:   2000,
+		Genres: []string{"Action"},
+	}
+
+	tt := []struct {
+		name     string
+		fields   []string
+		expected *Movie
+		err      error
+	}{
+		{
+			name:   "all fields",
+			fields: nil,
+			expected: &Movie{
+				ImdbID: "tt001",
+				Title:  "Title",
+				Plot:   "New plot",
+				Rating: 4,
+				Year:   2000,
+				Genres: []string{"Action"},
+			},
+		},
+		{
+			name:   "some fields",
+			fields: []string{"plot", "rating"},
+			expected: &Movie{
+				ImdbID: "tt001",
+				Title:  "Hand edited title",
+				Plot:   "New plot",
+				Rating: 4,
+				Genres: []string{"Drama"},
+			},
+		},
+		{
+			name:     "show field",
+			fields:   []string{"first_aired"},
+			expected: current(),
+		},
+		{
+			name:     "unknown field",
+			fields:   []string{"plot", "yo"},
+			expected: current(),
+			err:      ErrUnknownDetailField,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			m := current()
+			err := UpdateDetails(m, fresh, tc.fields)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+
+			if !reflect.DeepEqual(m, tc.expected) {
+				t.Errorf("expected %+v, got %+v", tc.expected, m)
+			}
+		})
+	}
+}
+
+func TestUpdateDetailsInvalidType(t *testing.T) {
+	if err := UpdateDetails(&Movie{}, &Show{}, nil); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,111 @@
+package polochon
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var (
+	// multiEpisodeRegexp matches the multi-episode numbering e.g. S01E01E02,
+	// S01E01-E02 or S01E01-02
+	multiEpisodeRegexp = regexp.MustCompile(`(?i)S([0-9]{1,3})E([0-9]{1,4})((?:-?E[0-9]{1,4})+|-[0-9]{1,4}(?:[^0-9a-z]|$))`)
+	// episodeNumberRegexp matches the numbers of the episodes in a
+	// multi-episode numbering
+	episodeNumberRegexp = regexp.MustCompile(`[0-9]+`)
+	// seasonEpisodeRegexp matches the regular numbering e.g. S01E01 or 1x01
+	seasonEpisodeRegexp = regexp.MustCompile(`(?i)(?:S[0-9]{1,3}E[0-9]{1,4}|[^0-9][0-9]{1,2}x[0-9]{2,3}(?:[^0-9]|$))`)
+	// absoluteEpisodeRegexp matches the absolute numbering used by anime
+	// releases e.g. "Show - 123" or "Show E123"
+	absoluteEpisodeRegexp = regexp.MustCompile(`(?i)(?:\s-\s|[\s._]EP?)([0-9]{2,4})(?:v[0-9])?(?:[\s._\[(]|$)`)
+	// airDateRegexp matches the air date used by the daily shows releases
+	// e.g. "Show.2026.10.17" or "Show 2026-10-17"
+	airDateRegexp = regexp.MustCompile(`(?:^|[^0-9])((?:19|20)[0-9]{2})[\s._-]([01][0-9])[\s._-]([0-3][0-9])(?:[^0-9]|$)`)
+)
+
+// EpisodeNumbering represents the numbering of the episodes found in a file
+// name
+type EpisodeNumbering struct {
+	Season         int
+	Episode        int
+	LastEpisode    int
+	AbsoluteNumber int
+	// AirDate is the air date of an episode of a daily show, its layout is
+	// AiredDateLayout
+	AirDate string
+}
+
+// ParseEpisodeNumbering parses the multi-episode, the absolute or the air date
+// numbering of a file name, nil is returned if the file name has none of them
+func ParseEpisodeNumbering(name string) *EpisodeNumbering {
+	if m := multiEpisodeRegexp.FindStringSubmatch(name); m != nil {
+		n := &EpisodeNumbering{}
+		n.Season, _ = strconv.Atoi(m[1])
+		n.Episode, _ = strconv.Atoi(m[2])
+		for _, s := range episodeNumberRegexp.FindAllString(m[3], -1) {
+			if e, _ := strconv.Atoi(s); e > n.LastEpisode {
+				n.LastEpisode = e
+			}
+		}
+
+		if n.LastEpisode <= n.Episode {
+			return nil
+		}
+
+		return n
+	}
+
+	// The absolute and the air date numbering are only used without season
+	if seasonEpisodeRegexp.MatchString(name) {
+		return nil
+	}
+
+	if m := airDateRegexp.FindStringSubmatch(name); m != nil {
+		date := fmt.Sprintf("%s-%s-%s", m[1], m[2], m[3])
+		if _, err := time.Parse(AiredDateLayout, date); err == nil {
+			return &EpisodeNumbering{AirDate: date}
+		}
+	}
+
+	m := absoluteEpisodeRegexp.FindStringSubmatch(name)
+	if m == nil {
+		return nil
+	}
+
+	// Years are not episode numbers
+	n, _ := strconv.Atoi(m[1])
+	if len(m[1]) == 4 && n >= 1900 && n < 2100 {
+		return nil
+	}
+
+	return &EpisodeNumbering{AbsoluteNumber: n}
+}
+
+// Apply sets the numbering of an episode guessed from the same file name
+func (n *EpisodeNumbering) Apply(e *ShowEpisode) {
+	if n.AirDate != "" {
+		// The season and the episode are found from the air date when the
+		// details are fetched
+		e.Season = 0
+		e.Episode = 0
+		e.Aired = n.AirDate
+		if e.Show != nil {
+			e.Show.Daily = true
+		}
+		return
+	}
+
+	if n.AbsoluteNumber != 0 {
+		// The season and the episode are found from the absolute number
+		// when the details are fetched
+		e.Season = 0
+		e.Episode = 0
+		e.AbsoluteNumber = n.AbsoluteNumber
+		return
+	}
+
+	if e.Season == n.Season && e.Episode == n.Episode {
+		e.LastEpisode = n.LastEpisode
+	}
+}
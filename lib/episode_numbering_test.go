@@ -0,0 +1,105 @@
+package polochon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEpisodeNumbering(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		expected *EpisodeNumbering
+	}{
+		{name: "Show.S01E02.720p.mkv"},
+		{name: "Show.1x02.720p.mkv"},
+		{name: "Show.S01E02-1080p.mkv"},
+		{name: "Show (2019).mkv"},
+		{name: "Show - 2019.mkv"},
+		{
+			name:     "Show.S01E01E02.720p.mkv",
+			expected: &EpisodeNumbering{Season: 1, Episode: 1, LastEpisode: 2},
+		},
+		{
+			name:     "Show.S01E01-E03.720p.mkv",
+			expected: &EpisodeNumbering{Season: 1, Episode: 1, LastEpisode: 3},
+		},
+		{
+			name:     "Show S02E10-11.mkv",
+			expected: &EpisodeNumbering{Season: 2, Episode: 10, LastEpisode: 11},
+		},
+		{
+			name:     "[Group] Show - 1071 (1080p) [ABCD1234].mkv",
+			expected: &EpisodeNumbering{AbsoluteNumber: 1071},
+		},
+		{
+			name:     "Show.E123.mkv",
+			expected: &EpisodeNumbering{AbsoluteNumber: 123},
+		},
+		{
+			name:     "[Group] Show - 05v2 [720p].mkv",
+			expected: &EpisodeNumbering{AbsoluteNumber: 5},
+		},
+		{
+			name:     "Show.2026.10.17.720p.HDTV.mkv",
+			expected: &EpisodeNumbering{AirDate: "2026-10-17"},
+		},
+		{
+			name:     "Show 2026-10-17.mkv",
+			expected: &EpisodeNumbering{AirDate: "2026-10-17"},
+		},
+		{name: "Show.2026.13.45.mkv"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ParseEpisodeNumbering(tc.name)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %+v, got %+v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestEpisodeNumbers(t *testing.T) {
+	e := &ShowEpisode{Season: 1, Episode: 1}
+	if e.IsMultiEpisode() {
+		t.Error("the episode should not be a multi-episode")
+	}
+
+	n := &EpisodeNumbering{Season: 1, Episode: 1, LastEpisode: 3}
+	n.Apply(e)
+
+	expected := []int{1, 2, 3}
+	if got := e.EpisodeNumbers(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	// The absolute number replaces the guessed numbering
+	n = &EpisodeNumbering{AbsoluteNumber: 123}
+	e = &ShowEpisode{Episode: 123}
+	n.Apply(e)
+	if e.Season != 0 || e.Episode != 0 || e.AbsoluteNumber != 123 {
+		t.Errorf("unexpected numbering S%02dE%02d #%d", e.Season, e.Episode, e.AbsoluteNumber)
+	}
+}
+
+func TestDailyEpisodeNumbering(t *testing.T) {
+	e := &ShowEpisode{Season: 20, Episode: 26, Show: &Show{}}
+	n := ParseEpisodeNumbering("Show.2026.10.17.mkv")
+	n.Apply(e)
+
+	if e.Season != 0 || e.Episode != 0 || e.Aired != "2026-10-17" {
+		t.Errorf("unexpected numbering S%02dE%02d aired %q", e.Season, e.Episode, e.Aired)
+	}
+
+	if !e.IsDaily() {
+		t.Error("the episode should be daily")
+	}
+
+	aired, err := e.AiredDate()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if aired.Year() != 2026 || aired.Month() != 10 || aired.Day() != 17 {
+		t.Errorf("unexpected aired date %s", aired)
+	}
+}
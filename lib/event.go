@@ -0,0 +1,185 @@
+package polochon
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EventType represents the type of a library event
+type EventType string
+
+// Available event types
+const (
+	EventMovieAdded       EventType = "movie.added"
+	EventMovieDeleted     EventType = "movie.deleted"
+	EventShowDeleted      EventType = "show.deleted"
+	EventSeasonDeleted    EventType = "season.deleted"
+	EventMovieUpgraded    EventType = "movie.upgraded"
+	EventMovieUpdated     EventType = "movie.updated"
+	EventShowUpdated      EventType = "show.updated"
+	EventEpisodeAdded     EventType = "episode.added"
+	EventEpisodeUpgraded  EventType = "episode.upgraded"
+	EventEpisodeDeleted   EventType = "episode.deleted"
+	EventEpisodeUpdated   EventType = "episode.updated"
+	EventSubtitleUpdated  EventType = "subtitle.updated"
+	EventTorrentAdded     EventType = "torrent.added"
+	EventTorrentFinished  EventType = "torrent.finished"
+	EventLibraryRefreshed EventType = "library.refreshed"
+)
+
+// Event represents a change in polochon
+type Event struct {
+	// ID is set by the event publisher, it is monotonically increasing
+	ID      uint64    `json:"id"`
+	Type    EventType `json:"type"`
+	Time    time.Time `json:"time"`
+	ImdbID  string    `json:"imdb_id,omitempty"`
+	Season  int       `json:"season,omitempty"`
+	Episode int       `json:"episode,omitempty"`
+	Lang    Language  `json:"lang,omitempty"`
+	Files   []string  `json:"files,omitempty"`
+
+	// Quality and PreviousQuality are set when a video is upgraded
+	Quality         Quality `json:"quality,omitempty"`
+	PreviousQuality Quality `json:"previous_quality,omitempty"`
+}
+
+// EventListener is implemented by the notifiers interested in events
+type EventListener interface {
+	OnEvent(*Event)
+}
+
+// NewEvent returns a new event
+func NewEvent(t EventType) *Event {
+	return &Event{
+		Type: t,
+		Time: time.Now(),
+	}
+}
+
+// NewVideoEvent returns a new event of type t about a video
+func NewVideoEvent(t EventType, v Video) *Event {
+	e := NewEvent(t)
+	if v == nil {
+		return e
+	}
+
+	switch video := v.(type) {
+	case *Movie:
+		e.ImdbID = video.ImdbID
+	case *ShowEpisode:
+		e.ImdbID = video.ShowImdbID
+		e.Season = video.Season
+		e.Episode = video.Episode
+	}
+
+	if f := v.GetFile(); f != nil && f.Path != "" {
+		e.Files = []string{filepath.Base(f.Path)}
+	}
+
+	return e
+}
+
+// NewVideoAddedEvent returns the event to send when a video is added
+func NewVideoAddedEvent(v Video) *Event {
+	t := EventMovieAdded
+	if _, ok := v.(*ShowEpisode); ok {
+		t = EventEpisodeAdded
+	}
+
+	return NewVideoEvent(t, v)
+}
+
+// NewVideoDeletedEvent returns the event to send when a video is deleted
+func NewVideoDeletedEvent(v Video) *Event {
+	t := EventMovieDeleted
+	if _, ok := v.(*ShowEpisode); ok {
+		t = EventEpisodeDeleted
+	}
+
+	return NewVideoEvent(t, v)
+}
+
+// NewVideoUpgradedEvent returns the event to send when a video replaced
+// another one with a different quality
+func NewVideoUpgradedEvent(v Video, quality, previous Quality) *Event {
+	t := EventMovieUpgraded
+	if _, ok := v.(*ShowEpisode); ok {
+		t = EventEpisodeUpgraded
+	}
+
+	e := NewVideoEvent(t, v)
+	e.Quality = quality
+	e.PreviousQuality = previous
+	return e
+}
+
+// NewVideoUpdatedEvent returns the event to send when a video is updated
+func NewVideoUpdatedEvent(v Video) *Event {
+	t := EventMovieUpdated
+	if _, ok := v.(*ShowEpisode); ok {
+		t = EventEpisodeUpdated
+	}
+
+	return NewVideoEvent(t, v)
+}
+
+// NewVideoReplacedEvent returns the event to send when a video replaced
+// another one, it's an upgrade only if the quality is better than the
+// previous one
+func NewVideoReplacedEvent(v Video, previous Quality) *Event {
+	quality := v.GetMetadata().Quality
+	if !IsUpgrade(previous, quality) {
+		return NewVideoUpdatedEvent(v)
+	}
+
+	return NewVideoUpgradedEvent(v, quality, previous)
+}
+
+// PublishVideoReplaced publishes the event of a video replacing another one
+func PublishVideoReplaced(notifiers []Notifier, v Video, previous Quality, log *logrus.Entry) {
+	e := NewVideoReplacedEvent(v, previous)
+	log.WithFields(logrus.Fields{
+		"quality":          v.GetMetadata().Quality,
+		"previous_quality": previous,
+		"event":            e.Type,
+	}).Info("video replaced")
+
+	PublishEvent(notifiers, e)
+}
+
+// NewSubtitleEvent returns the event to send when a subtitle is updated
+func NewSubtitleEvent(s *Subtitle) *Event {
+	e := NewVideoEvent(EventSubtitleUpdated, s.Video)
+	e.Lang = s.Lang
+	if s.Path != "" {
+		e.Files = []string{filepath.Base(s.Path)}
+	}
+
+	return e
+}
+
+// NewTorrentEvent returns the event to send when a torrent changes
+func NewTorrentEvent(t EventType, torrent *Torrent) *Event {
+	e := NewEvent(t)
+	e.ImdbID = torrent.ImdbID
+	e.Season = torrent.Season
+	e.Episode = torrent.Episode
+
+	if torrent.Status != nil {
+		e.Files = torrent.Status.FilePaths
+	}
+
+	return e
+}
+
+// PublishEvent sends the event to the notifiers listening to events
+func PublishEvent(notifiers []Notifier, e *Event) {
+	for _, n := range notifiers {
+		if l, ok := n.(EventListener); ok {
+			l.OnEvent(e)
+		}
+	}
+}
@@ -1,9 +1,17 @@
 package polochon
 
 import (
+	"errors"
+	"slices"
+
 	"github.com/sirupsen/logrus"
 )
 
+// Explorer errors
+var (
+	ErrNoExplorer = errors.New("polochon: no explorer available for this option")
+)
+
 // Explorer is the interface explore new videos from different sources
 type Explorer interface {
 	Module
@@ -12,3 +20,54 @@ type Explorer interface {
 	AvailableShowOptions() []string
 	GetShowList(option string, log *logrus.Entry) ([]*Show, error)
 }
+
+// ExploreMovies returns the movies of all the explorers supporting the option,
+// the results are merged and deduplicated by imdb id
+func ExploreMovies(explorers []Explorer, option string, log *logrus.Entry) ([]*Movie, error) {
+	explorers = slices.DeleteFunc(slices.Clone(explorers), func(e Explorer) bool {
+		return !slices.Contains(e.AvailableMovieOptions(), option)
+	})
+	if len(explorers) == 0 {
+		return nil, ErrNoExplorer
+	}
+
+	results := fanOut(len(explorers), func(i int) ([]*Movie, error) {
+		e := explorers[i]
+		return e.GetMovieList(option, log.WithField("explorer", e.Name()))
+	}, log)
+
+	return uniqueByImdbID(results, func(m *Movie) string { return m.ImdbID }), nil
+}
+
+// ExploreShows returns the shows of all the explorers supporting the option,
+// the results are merged and deduplicated by imdb id
+func ExploreShows(explorers []Explorer, option string, log *logrus.Entry) ([]*Show, error) {
+	explorers = slices.DeleteFunc(slices.Clone(explorers), func(e Explorer) bool {
+		return !slices.Contains(e.AvailableShowOptions(), option)
+	})
+	if len(explorers) == 0 {
+		return nil, ErrNoExplorer
+	}
+
+	results := fanOut(len(explorers), func(i int) ([]*Show, error) {
+		e := explorers[i]
+		return e.GetShowList(option, log.WithField("explorer", e.Name()))
+	}, log)
+
+	return uniqueByImdbID(results, func(s *Show) string { return s.ImdbID }), nil
+}
+
+// ExploreOptions returns the options available for each explorer
+func ExploreOptions(explorers []Explorer, videoType VideoType) map[string][]string {
+	options := make(map[string][]string, len(explorers))
+	for _, e := range explorers {
+		switch videoType {
+		case TypeMovie:
+			options[e.Name()] = e.AvailableMovieOptions()
+		default:
+			options[e.Name()] = e.AvailableShowOptions()
+		}
+	}
+
+	return options
+}
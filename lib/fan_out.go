@@ -0,0 +1,59 @@
+package polochon
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fanOut calls f concurrently for each index in [0, n) and returns the
+// results in the index order. Errors are logged and the failing calls are
+// skipped, the modules not implementing the call are only logged at debug
+// level.
+func fanOut[T any](n int, f func(i int) ([]T, error), log *logrus.Entry) [][]T {
+	results := make([][]T, n)
+
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Go(func() {
+			r, err := f(i)
+			if err != nil {
+				if errors.Is(err, ErrNotAvailable) {
+					log.Debug(err)
+				} else {
+					log.Warn(err)
+				}
+				return
+			}
+			results[i] = r
+		})
+	}
+	wg.Wait()
+
+	return results
+}
+
+// uniqueByImdbID flattens the results keeping only the first occurrence of
+// each imdb id, results without imdb id are dropped
+func uniqueByImdbID[T any](results [][]T, imdbID func(T) string) []T {
+	seen := map[string]struct{}{}
+	out := []T{}
+	for _, r := range results {
+		for _, item := range r {
+			id := imdbID(item)
+			if id == "" {
+				continue
+			}
+
+			if _, ok := seen[id]; ok {
+				continue
+			}
+
+			seen[id] = struct{}{}
+			out = append(out, item)
+		}
+	}
+
+	return out
+}
@@ -2,6 +2,7 @@ package polochon
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path"
@@ -120,6 +121,15 @@ func (f *File) Ignore() error {
 	return nil
 }
 
+// Unignore removes the ".ignore" file next to the file
+func (f *File) Unignore() error {
+	err := os.Remove(f.IgnorePath())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
 // Guess video information from file
 func (f *File) Guess(movieConf MovieConfig, showConf ShowConfig, log *logrus.Entry) (Video, error) {
 	for _, guesser := range f.Guessers {
@@ -174,6 +184,11 @@ func (f *File) IgnorePath() string {
 	return f.Path + ".ignore"
 }
 
+// HintPath is an helper to get the hint file path
+func (f *File) HintPath() string {
+	return f.Path + ".imdb"
+}
+
 // Filename returns the file name
 func (f *File) Filename() string {
 	return filepath.Base(f.Path)
@@ -259,3 +274,26 @@ func exists(path string) bool {
 	}
 	return false
 }
+
+// WriteJSONFile writes the value as JSON in a temporary file and moves it to
+// the path so that the file is never partially written
+func WriteJSONFile(path string, v any) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	encoder := json.NewEncoder(tmp)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -77,6 +77,15 @@ func TestIgnoreFile(t *testing.T) {
 	if !file.IsIgnored() {
 		t.Fatal("the file should be ignored")
 	}
+
+	// Remove the ignore file
+	if err := file.Unignore(); err != nil {
+		t.Fatalf("failed to remove ignore file: %q", err)
+	}
+
+	if file.IsIgnored() {
+		t.Fatal("the file should not be ignored anymore")
+	}
 }
 
 func TestIsVideo(t *testing.T) {
@@ -147,3 +156,31 @@ func TestExludedFile(t *testing.T) {
 		t.Fatalf("the file should be excluded")
 	}
 }
+
+func TestWriteJSONFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+
+	if err := WriteJSONFile(path, map[string]int{"a": 1}); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if expected := "{\n  \"a\": 1\n}\n"; string(data) != expected {
+		t.Errorf("expected %q, got %q", expected, data)
+	}
+
+	// The temporary file is removed
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("expected a single file, got %d", len(entries))
+	}
+}
@@ -11,6 +11,12 @@ type FsNotifierCtx struct {
 	Event chan string
 	Done  <-chan struct{}
 	Wg    *sync.WaitGroup
+
+	// Recursive makes the notifier watch the sub directories as well
+	Recursive bool
+	// AllChanges makes the notifier report the files written, removed and
+	// renamed, only the created files are reported otherwise
+	AllChanges bool
 }
 
 // FsNotifier is an interface to notify on filesystem change
@@ -0,0 +1,137 @@
+package polochon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidHint is returned when a hint is not valid
+var ErrInvalidHint = errors.New("polochon: invalid hint")
+
+var hintRegexp = regexp.MustCompile(`(?i)^(tt[0-9]+)(?:\s+S([0-9]+)E([0-9]+))?$`)
+
+// Hint represents the identification forced for a video, it's read from a
+// ".imdb" file next to the video. It contains the IMDb ID of the movie or the
+// IMDb ID of the show followed by the episode number, e.g. "tt0944947 S01E02".
+type Hint struct {
+	ImdbID  string `json:"imdb_id"`
+	Season  int    `json:"season,omitempty"`
+	Episode int    `json:"episode,omitempty"`
+}
+
+// ParseHint parses a hint
+func ParseHint(s string) (*Hint, error) {
+	m := hintRegexp.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidHint, s)
+	}
+
+	h := &Hint{ImdbID: m[1]}
+	if m[2] != "" {
+		// The regexp ensures the numbers are valid
+		h.Season, _ = strconv.Atoi(m[2])
+		h.Episode, _ = strconv.Atoi(m[3])
+	}
+
+	return h, nil
+}
+
+// Validate returns an error if the hint is not valid
+func (h *Hint) Validate() error {
+	_, err := ParseHint(h.String())
+	return err
+}
+
+// String implements the Stringer interface
+func (h *Hint) String() string {
+	if h.Season == 0 && h.Episode == 0 {
+		return h.ImdbID
+	}
+
+	return fmt.Sprintf("%s S%02dE%02d", h.ImdbID, h.Season, h.Episode)
+}
+
+// IsEpisode returns true if the hint identifies an episode
+func (h *Hint) IsEpisode() bool {
+	return h.Season != 0 || h.Episode != 0
+}
+
+// Matches returns true if the video is of the kind identified by the hint
+func (h *Hint) Matches(v Video) bool {
+	switch v.(type) {
+	case *Movie:
+		return !h.IsEpisode()
+	case *ShowEpisode:
+		return h.IsEpisode()
+	default:
+		return false
+	}
+}
+
+// Apply forces the identification of a video, the ids guessed for it are
+// reset. Nothing is done if the video is not of the kind of the hint, a new
+// video should be created with NewVideo instead.
+func (h *Hint) Apply(v Video) {
+	if !h.Matches(v) {
+		return
+	}
+
+	switch v := v.(type) {
+	case *Movie:
+		v.ImdbID = h.ImdbID
+		v.TmdbID = 0
+	case *ShowEpisode:
+		v.ShowImdbID = h.ImdbID
+		v.ShowTvdbID = 0
+		v.TvdbID = 0
+		v.EpisodeImdbID = ""
+		v.Show = nil
+		v.Season = h.Season
+		v.Episode = h.Episode
+	}
+}
+
+// NewVideo returns the video of a file identified by the hint
+func (h *Hint) NewVideo(file File, movieConf MovieConfig, showConf ShowConfig) Video {
+	if h.IsEpisode() {
+		e := NewShowEpisodeFromFile(showConf, file)
+		h.Apply(e)
+		return e
+	}
+
+	m := NewMovieFromFile(movieConf, file)
+	h.Apply(m)
+	return m
+}
+
+// ReadHint reads the hint file of the file
+func (f *File) ReadHint() (*Hint, error) {
+	data, err := os.ReadFile(f.HintPath())
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseHint(string(data))
+}
+
+// WriteHint writes the hint file of the file
+func (f *File) WriteHint(h *Hint) error {
+	if err := h.Validate(); err != nil {
+		return err
+	}
+
+	return os.WriteFile(f.HintPath(), []byte(h.String()+"\n"), 0o644)
+}
+
+// RemoveHint removes the hint file of the file
+func (f *File) RemoveHint() error {
+	err := os.Remove(f.HintPath())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package polochon
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseHint(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected *Hint
+		err      error
+	}{
+		{input: "tt0133093\n", expected: &Hint{ImdbID: "tt0133093"}},
+		{input: "  tt0133093  ", expected: &Hint{ImdbID: "tt0133093"}},
+		{input: "tt0944947 S01E02", expected: &Hint{ImdbID: "tt0944947", Season: 1, Episode: 2}},
+		{input: "tt0944947 s1e12", expected: &Hint{ImdbID: "tt0944947", Season: 1, Episode: 12}},
+		{input: "https://www.imdb.com/title/tt0133093", err: ErrInvalidHint},
+		{input: "tt0944947 S01", err: ErrInvalidHint},
+		{input: "", err: ErrInvalidHint},
+	} {
+		got, err := ParseHint(tc.input)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%q: expected error %v, got %v", tc.input, tc.err, err)
+		}
+
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%q: expected %+v, got %+v", tc.input, tc.expected, got)
+		}
+	}
+}
+
+func TestHintFile(t *testing.T) {
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "polochon-hint-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir for hint tests")
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	file := NewFile(filepath.Join(tmpDir, "episode.mp4"))
+
+	// No hint file
+	if _, err := file.ReadHint(); !os.IsNotExist(err) {
+		t.Fatalf("expected a not exist error, got %v", err)
+	}
+
+	if err := file.WriteHint(&Hint{ImdbID: "yo"}); !errors.Is(err, ErrInvalidHint) {
+		t.Fatalf("expected error %q, got %v", ErrInvalidHint, err)
+	}
+
+	hint := &Hint{ImdbID: "tt0944947", Season: 1, Episode: 2}
+	if err := file.WriteHint(hint); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	got, err := file.ReadHint()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if !reflect.DeepEqual(got, hint) {
+		t.Errorf("expected %+v, got %+v", hint, got)
+	}
+
+	v := got.NewVideo(*file, MovieConfig{}, ShowConfig{})
+	e, ok := v.(*ShowEpisode)
+	if !ok {
+		t.Fatalf("expected an episode, got %T", v)
+	}
+
+	if e.ShowImdbID != "tt0944947" || e.Season != 1 || e.Episode != 2 || e.Path != file.Path {
+		t.Errorf("unexpected episode %+v", e)
+	}
+
+	if err := file.RemoveHint(); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if _, err := file.ReadHint(); !os.IsNotExist(err) {
+		t.Fatalf("expected a not exist error, got %v", err)
+	}
+}
+
+func TestHintApply(t *testing.T) {
+	movieHint := &Hint{ImdbID: "tt0133093"}
+	episodeHint := &Hint{ImdbID: "tt0944947", Season: 1, Episode: 2}
+
+	m := &Movie{ImdbID: "tt001", TmdbID: 1}
+	e := &ShowEpisode{ShowImdbID: "tt002", Season: 3, Episode: 4}
+
+	for _, tc := range []struct {
+		hint     *Hint
+		video    Video
+		expected bool
+	}{
+		{hint: movieHint, video: m, expected: true},
+		{hint: movieHint, video: e, expected: false},
+		{hint: episodeHint, video: m, expected: false},
+		{hint: episodeHint, video: e, expected: true},
+	} {
+		if got := tc.hint.Matches(tc.video); got != tc.expected {
+			t.Errorf("%s on %T: expected %t, got %t", tc.hint, tc.video, tc.expected, got)
+		}
+	}
+
+	// A hint of another kind leaves the video untouched
+	episodeHint.Apply(m)
+	if m.ImdbID != "tt001" {
+		t.Errorf("expected the movie to be untouched, got %+v", m)
+	}
+
+	episodeHint.Apply(e)
+	if e.ShowImdbID != "tt0944947" || e.Season != 1 || e.Episode != 2 {
+		t.Errorf("unexpected episode %+v", e)
+	}
+
+	movieHint.Apply(m)
+	if m.ImdbID != "tt0133093" || m.TmdbID != 0 {
+		t.Errorf("unexpected movie %+v", m)
+	}
+}
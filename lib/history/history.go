@@ -0,0 +1,526 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+// Custom errors
+var (
+	ErrMissingPath     = errors.New("history: missing history file path")
+	ErrNotFound        = errors.New("history: not found")
+	ErrMissingRelease  = errors.New("history: missing release url or name")
+	ErrAlreadyBlocked  = errors.New("history: release already blocked")
+	ErrInvalidDuration = errors.New("history: invalid duration")
+)
+
+// Default values of the configuration
+const (
+	DefaultBackoffBase = time.Hour
+	DefaultBackoffMax  = 7 * 24 * time.Hour
+	DefaultGrabTimeout = 2 * 24 * time.Hour
+	DefaultMaxEntries  = 1000
+)
+
+// Outcome represents the outcome of a grab attempt
+type Outcome string
+
+// Possible outcomes
+const (
+	// OutcomeNotFound is used when no torrent matched the wanted video
+	OutcomeNotFound Outcome = "not_found"
+	// OutcomeFailed is used when the torrent could not be added to the
+	// downloader
+	OutcomeFailed Outcome = "failed"
+	// OutcomeGrabbed is used when the torrent has been added to the downloader
+	OutcomeGrabbed Outcome = "grabbed"
+	// OutcomeCompleted is used when the torrent has been downloaded and
+	// added to the library
+	OutcomeCompleted Outcome = "completed"
+	// OutcomeRejected is used when the downloaded torrent could not be added
+	// to the library, its release is blocked
+	OutcomeRejected Outcome = "rejected"
+)
+
+// Config represents the configuration of the history
+type Config struct {
+	Path        string        `yaml:"path"`
+	BackoffBase time.Duration `yaml:"backoff_base"`
+	BackoffMax  time.Duration `yaml:"backoff_max"`
+	GrabTimeout time.Duration `yaml:"grab_timeout"`
+	MaxEntries  int           `yaml:"max_entries"`
+}
+
+// Entry represents a grab attempt
+type Entry struct {
+	ID        uint64             `json:"id"`
+	ImdbID    string             `json:"imdb_id"`
+	Type      polochon.VideoType `json:"type"`
+	Season    int                `json:"season,omitempty"`
+	Episode   int                `json:"episode,omitempty"`
+	Quality   polochon.Quality   `json:"quality,omitempty"`
+	Name      string             `json:"name,omitempty"`
+	URL       string             `json:"url,omitempty"`
+	Hash      string             `json:"hash,omitempty"`
+	Source    string             `json:"source,omitempty"`
+	Outcome   Outcome            `json:"outcome"`
+	Error     string             `json:"error,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// NewEntry returns a new entry from a torrent
+func NewEntry(torrent *polochon.Torrent, outcome Outcome) *Entry {
+	e := &Entry{
+		ImdbID:  torrent.ImdbID,
+		Type:    torrent.Type,
+		Season:  torrent.Season,
+		Episode: torrent.Episode,
+		Quality: torrent.Quality,
+		Outcome: outcome,
+	}
+
+	if torrent.Result != nil {
+		e.Name = torrent.Result.Name
+		e.URL = torrent.Result.URL
+		e.Hash = InfoHash(torrent.Result.URL)
+		e.Source = torrent.Result.Source
+	}
+
+	return e
+}
+
+// Key returns the key of the entry used to track the attempts of a video
+func (e *Entry) Key() string {
+	return polochon.VideoKey(e.ImdbID, e.Season, e.Episode)
+}
+
+// Item represents the retry state of a video
+type Item struct {
+	Outcome     Outcome   `json:"outcome"`
+	Failures    int       `json:"failures"`
+	LastAttempt time.Time `json:"last_attempt"`
+	NextAttempt time.Time `json:"next_attempt"`
+}
+
+// BlockedRelease represents a release that should never be grabbed again
+type BlockedRelease struct {
+	ID        uint64    `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	Hash      string    `json:"hash,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// matches returns true if the torrent is the blocked release
+func (b *BlockedRelease) matches(r *polochon.TorrentResult) bool {
+	switch {
+	case b.Hash != "" && strings.EqualFold(b.Hash, InfoHash(r.URL)):
+		return true
+	case b.URL != "" && b.URL == r.URL:
+		return true
+	case b.Name != "" && b.Name == r.Name:
+		return true
+	default:
+		return false
+	}
+}
+
+// Filter represents the filters of the history entries
+type Filter struct {
+	ImdbID  string
+	Type    polochon.VideoType
+	Season  int
+	Episode int
+	Outcome Outcome
+	Since   time.Time
+	Limit   int
+}
+
+func (f *Filter) match(e *Entry) bool {
+	switch {
+	case f.ImdbID != "" && e.ImdbID != f.ImdbID:
+		return false
+	case f.Type != "" && e.Type != f.Type:
+		return false
+	case f.Season != 0 && e.Season != f.Season:
+		return false
+	case f.Episode != 0 && e.Episode != f.Episode:
+		return false
+	case f.Outcome != "" && e.Outcome != f.Outcome:
+		return false
+	case !f.Since.IsZero() && e.UpdatedAt.Before(f.Since):
+		return false
+	default:
+		return true
+	}
+}
+
+// data represents the content of the history file
+type data struct {
+	LastID    uint64            `json:"last_id"`
+	Entries   []*Entry          `json:"entries"`
+	Items     map[string]*Item  `json:"items"`
+	Blocklist []*BlockedRelease `json:"blocklist"`
+}
+
+// Store keeps the history of the grab attempts in a file, all the methods
+// can be called on a nil store in which case nothing is recorded
+type Store struct {
+	Config
+	// now is overwritten during the tests
+	now func() time.Time
+
+	// Mutex to protect the data, the store is shared between the apps
+	mu   sync.Mutex
+	data *data
+}
+
+// Open opens the history file, a missing file is an empty history
+func Open(config Config) (*Store, error) {
+	if config.Path == "" {
+		return nil, ErrMissingPath
+	}
+
+	for _, d := range []*time.Duration{
+		&config.BackoffBase,
+		&config.BackoffMax,
+		&config.GrabTimeout,
+	} {
+		if *d < 0 {
+			return nil, ErrInvalidDuration
+		}
+	}
+
+	if config.BackoffBase == 0 {
+		config.BackoffBase = DefaultBackoffBase
+	}
+	if config.BackoffMax == 0 {
+		config.BackoffMax = DefaultBackoffMax
+	}
+	if config.GrabTimeout == 0 {
+		config.GrabTimeout = DefaultGrabTimeout
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultMaxEntries
+	}
+
+	s := &Store{
+		Config: config,
+		now:    time.Now,
+		data: &data{
+			Entries:   []*Entry{},
+			Items:     map[string]*Item{},
+			Blocklist: []*BlockedRelease{},
+		},
+	}
+
+	file, err := os.Open(config.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := json.NewDecoder(file).Decode(s.data); err != nil {
+		return nil, fmt.Errorf("history: invalid history file: %w", err)
+	}
+
+	return s, nil
+}
+
+// backoff returns the delay before the next attempt after n failures
+func (s *Store) backoff(n int) time.Duration {
+	d := s.BackoffBase
+	for i := 1; i < n && d < s.BackoffMax; i++ {
+		d *= 2
+	}
+
+	return min(d, s.BackoffMax)
+}
+
+// Record records an attempt and updates the retry state of the video
+func (s *Store) Record(e *Entry) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.data.LastID++
+	e.ID = s.data.LastID
+	e.CreatedAt = now
+	e.UpdatedAt = now
+
+	s.data.Entries = append(s.data.Entries, e)
+	if l := len(s.data.Entries); l > s.MaxEntries {
+		s.data.Entries = slices.Delete(s.data.Entries, 0, l-s.MaxEntries)
+	}
+
+	s.updateItem(e.Key(), e.Outcome, now)
+
+	return s.write()
+}
+
+// updateItem updates the retry state of a video according to an outcome
+func (s *Store) updateItem(key string, outcome Outcome, now time.Time) {
+	item, ok := s.data.Items[key]
+	if !ok {
+		item = &Item{}
+		s.data.Items[key] = item
+	}
+	item.LastAttempt = now
+	item.Outcome = outcome
+
+	switch outcome {
+	case OutcomeCompleted:
+		delete(s.data.Items, key)
+	case OutcomeGrabbed:
+		// Do not try again while the torrent is downloading, if it never
+		// finishes the video will be retried after the grab timeout
+		item.NextAttempt = now.Add(s.GrabTimeout)
+	default:
+		item.Failures++
+		item.NextAttempt = now.Add(s.backoff(item.Failures))
+	}
+}
+
+// Complete marks the last grab of the torrent video as completed
+func (s *Store) Complete(torrent *polochon.Torrent) error {
+	return s.resolve(torrent, OutcomeCompleted, "")
+}
+
+// Fail marks the last grab of the torrent video as failed without blocking
+// its release, the video is retried after the backoff delay
+func (s *Store) Fail(torrent *polochon.Torrent, reason string) error {
+	return s.resolve(torrent, OutcomeFailed, reason)
+}
+
+// Reject marks the last grab of the torrent video as rejected and blocks its
+// release
+func (s *Store) Reject(torrent *polochon.Torrent, reason string) error {
+	return s.resolve(torrent, OutcomeRejected, reason)
+}
+
+// resolve updates the outcome of the last grab of the torrent video
+func (s *Store) resolve(torrent *polochon.Torrent, outcome Outcome, reason string) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	key := polochon.VideoKey(torrent.ImdbID, torrent.Season, torrent.Episode)
+
+	var entry *Entry
+	for _, e := range slices.Backward(s.data.Entries) {
+		if e.Key() == key && e.Outcome == OutcomeGrabbed {
+			entry = e
+			break
+		}
+	}
+
+	if entry == nil {
+		return ErrNotFound
+	}
+
+	entry.Outcome = outcome
+	entry.Error = reason
+	entry.UpdatedAt = now
+	s.updateItem(key, outcome, now)
+
+	if outcome == OutcomeRejected && !s.isBlocked(entry.Name, entry.URL) {
+		s.data.LastID++
+		s.data.Blocklist = append(s.data.Blocklist, &BlockedRelease{
+			ID:        s.data.LastID,
+			Name:      entry.Name,
+			URL:       entry.URL,
+			Hash:      entry.Hash,
+			Reason:    reason,
+			CreatedAt: now,
+		})
+	}
+
+	return s.write()
+}
+
+// CanAttempt returns true if the video can be grabbed, false if it's waiting
+// for its backoff to expire
+func (s *Store) CanAttempt(imdbID string, season, episode int) bool {
+	if s == nil {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.data.Items[polochon.VideoKey(imdbID, season, episode)]
+	if !ok {
+		return true
+	}
+
+	return !s.now().Before(item.NextAttempt)
+}
+
+// IsPending returns true if a torrent of the video has been grabbed and is
+// still expected to complete
+func (s *Store) IsPending(imdbID string, season, episode int) bool {
+	if s == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.data.Items[polochon.VideoKey(imdbID, season, episode)]
+	if !ok || item.Outcome != OutcomeGrabbed {
+		return false
+	}
+
+	return s.now().Before(item.NextAttempt)
+}
+
+// Entries returns the entries matching the filter, the latest first
+func (s *Store) Entries(filter *Filter) []*Entry {
+	entries := []*Entry{}
+	if s == nil {
+		return entries
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range slices.Backward(s.data.Entries) {
+		if filter != nil && !filter.match(e) {
+			continue
+		}
+
+		entries = append(entries, e)
+		if filter != nil && filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+	}
+
+	return entries
+}
+
+// Blocklist returns the blocked releases
+func (s *Store) Blocklist() []*BlockedRelease {
+	if s == nil {
+		return []*BlockedRelease{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.data.Blocklist)
+}
+
+// Block adds a release to the blocklist
+func (s *Store) Block(b *BlockedRelease) error {
+	if s == nil {
+		return nil
+	}
+
+	if b.URL == "" && b.Name == "" && b.Hash == "" {
+		return ErrMissingRelease
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if b.Hash == "" {
+		b.Hash = InfoHash(b.URL)
+	}
+
+	for _, blocked := range s.data.Blocklist {
+		if blocked.Name == b.Name && blocked.URL == b.URL && blocked.Hash == b.Hash {
+			return ErrAlreadyBlocked
+		}
+	}
+
+	s.data.LastID++
+	b.ID = s.data.LastID
+	b.CreatedAt = s.now()
+	s.data.Blocklist = append(s.data.Blocklist, b)
+
+	return s.write()
+}
+
+// Unblock removes a release from the blocklist
+func (s *Store) Unblock(id uint64) error {
+	if s == nil {
+		return ErrNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := len(s.data.Blocklist)
+	s.data.Blocklist = slices.DeleteFunc(s.data.Blocklist, func(b *BlockedRelease) bool {
+		return b.ID == id
+	})
+
+	if len(s.data.Blocklist) == l {
+		return ErrNotFound
+	}
+
+	return s.write()
+}
+
+// IsBlocked returns true if the torrent release is in the blocklist
+func (s *Store) IsBlocked(torrent *polochon.Torrent) bool {
+	if s == nil || torrent == nil || torrent.Result == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.isBlocked(torrent.Result.Name, torrent.Result.URL)
+}
+
+func (s *Store) isBlocked(name, url string) bool {
+	r := &polochon.TorrentResult{Name: name, URL: url}
+	return slices.ContainsFunc(s.data.Blocklist, func(b *BlockedRelease) bool {
+		return b.matches(r)
+	})
+}
+
+// write saves the history
+func (s *Store) write() error {
+	return polochon.WriteJSONFile(s.Path, s.data)
+}
+
+// InfoHash returns the info hash of a magnet link, an empty string is
+// returned if the URL is not a magnet link
+func InfoHash(url string) string {
+	const prefix = "xt=urn:btih:"
+
+	i := strings.Index(url, prefix)
+	if !strings.HasPrefix(url, "magnet:") || i == -1 {
+		return ""
+	}
+
+	hash := url[i+len(prefix):]
+	if j := strings.IndexByte(hash, '&'); j != -1 {
+		hash = hash[:j]
+	}
+
+	return strings.ToLower(hash)
+}
@@ -0,0 +1,247 @@
+package history
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+var testTime = time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestStore(t *testing.T) (*Store, *time.Time) {
+	s, err := Open(Config{
+		Path:        filepath.Join(t.TempDir(), "history.json"),
+		BackoffBase: time.Hour,
+		BackoffMax:  5 * time.Hour,
+		GrabTimeout: 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	now := testTime
+	s.now = func() time.Time { return now }
+
+	return s, &now
+}
+
+var testTorrent = &polochon.Torrent{
+	ImdbID:  "tt001",
+	Type:    polochon.TypeEpisode,
+	Season:  1,
+	Episode: 2,
+	Quality: polochon.Quality720p,
+	Result: &polochon.TorrentResult{
+		Name:   "Show.S01E02.720p-GRP",
+		URL:    "magnet:?xt=urn:btih:ABCDEF&dn=Show",
+		Source: "eztv",
+	},
+}
+
+func TestInfoHash(t *testing.T) {
+	for url, expected := range map[string]string{
+		"magnet:?xt=urn:btih:ABCDEF&dn=Show": "abcdef",
+		"magnet:?dn=Show&xt=urn:btih:abcdef": "abcdef",
+		"http://example.com/file.torrent":    "",
+	} {
+		if got := InfoHash(url); got != expected {
+			t.Errorf("%q: expected %q, got %q", url, expected, got)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	s, now := newTestStore(t)
+
+	for i, expected := range []time.Duration{
+		time.Hour,
+		2 * time.Hour,
+		4 * time.Hour,
+		5 * time.Hour,
+		5 * time.Hour,
+	} {
+		if err := s.Record(NewEntry(&polochon.Torrent{ImdbID: "tt001"}, OutcomeNotFound)); err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+
+		if s.CanAttempt("tt001", 0, 0) {
+			t.Fatalf("attempt %d: should wait before the next attempt", i)
+		}
+
+		*now = now.Add(expected - time.Second)
+		if s.CanAttempt("tt001", 0, 0) {
+			t.Fatalf("attempt %d: should wait %s before the next attempt", i, expected)
+		}
+
+		*now = now.Add(time.Second)
+		if !s.CanAttempt("tt001", 0, 0) {
+			t.Fatalf("attempt %d: should be able to attempt after %s", i, expected)
+		}
+	}
+
+	// Other videos are not affected
+	if !s.CanAttempt("tt002", 0, 0) {
+		t.Fatal("should be able to attempt another video")
+	}
+}
+
+func TestGrabLifecycle(t *testing.T) {
+	s, now := newTestStore(t)
+
+	if err := s.Complete(testTorrent); err != ErrNotFound {
+		t.Fatalf("expected %q, got %q", ErrNotFound, err)
+	}
+
+	if err := s.Record(NewEntry(testTorrent, OutcomeGrabbed)); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	// The episode should not be grabbed again while downloading
+	if s.CanAttempt("tt001", 1, 2) || !s.IsPending("tt001", 1, 2) {
+		t.Fatal("should not attempt while the torrent is downloading")
+	}
+
+	*now = now.Add(time.Hour)
+	if err := s.Reject(testTorrent, "video file not found"); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if !s.IsBlocked(testTorrent) {
+		t.Fatal("the rejected release should be blocked")
+	}
+
+	if s.IsPending("tt001", 1, 2) {
+		t.Fatal("the rejected torrent should not be pending")
+	}
+
+	// The same release from another URL is still blocked by its hash
+	other := &polochon.Torrent{Result: &polochon.TorrentResult{URL: "magnet:?xt=urn:btih:abcdef"}}
+	if !s.IsBlocked(other) {
+		t.Fatal("the release should be blocked by its hash")
+	}
+
+	// Reopen the store to make sure everything is persisted
+	reopened, err := Open(s.Config)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expected := []*Entry{
+		{
+			ID:        1,
+			ImdbID:    "tt001",
+			Type:      polochon.TypeEpisode,
+			Season:    1,
+			Episode:   2,
+			Quality:   polochon.Quality720p,
+			Name:      "Show.S01E02.720p-GRP",
+			URL:       "magnet:?xt=urn:btih:ABCDEF&dn=Show",
+			Hash:      "abcdef",
+			Source:    "eztv",
+			Outcome:   OutcomeRejected,
+			Error:     "video file not found",
+			CreatedAt: testTime,
+			UpdatedAt: testTime.Add(time.Hour),
+		},
+	}
+
+	got := reopened.Entries(&Filter{ImdbID: "tt001", Outcome: OutcomeRejected})
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected[0], got)
+	}
+
+	blocklist := reopened.Blocklist()
+	if len(blocklist) != 1 || blocklist[0].Reason != "video file not found" {
+		t.Fatalf("invalid blocklist %+v", blocklist)
+	}
+
+	if err := reopened.Unblock(blocklist[0].ID); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if reopened.IsBlocked(testTorrent) {
+		t.Fatal("the release should not be blocked anymore")
+	}
+}
+
+func TestGrabFailure(t *testing.T) {
+	s, now := newTestStore(t)
+
+	if err := s.Record(NewEntry(testTorrent, OutcomeGrabbed)); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := s.Fail(testTorrent, "no video found in the torrent"); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if s.IsBlocked(testTorrent) {
+		t.Fatal("the failed release should not be blocked")
+	}
+
+	if s.CanAttempt("tt001", 1, 2) {
+		t.Fatal("should wait before the next attempt")
+	}
+
+	*now = now.Add(time.Hour)
+	if !s.CanAttempt("tt001", 1, 2) {
+		t.Fatal("should be able to attempt after the backoff delay")
+	}
+}
+
+func TestEntriesFilter(t *testing.T) {
+	s, now := newTestStore(t)
+	s.MaxEntries = 3
+
+	for _, e := range []*Entry{
+		{ImdbID: "tt001", Type: polochon.TypeMovie, Outcome: OutcomeNotFound},
+		{ImdbID: "tt001", Type: polochon.TypeMovie, Outcome: OutcomeGrabbed},
+		{ImdbID: "tt002", Type: polochon.TypeEpisode, Season: 1, Episode: 1, Outcome: OutcomeGrabbed},
+		{ImdbID: "tt002", Type: polochon.TypeEpisode, Season: 1, Episode: 2, Outcome: OutcomeFailed},
+	} {
+		*now = now.Add(time.Hour)
+		if err := s.Record(e); err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		filter   *Filter
+		expected []uint64
+	}{
+		{name: "no filter, oldest entry dropped", expected: []uint64{4, 3, 2}},
+		{name: "imdb id", filter: &Filter{ImdbID: "tt002"}, expected: []uint64{4, 3}},
+		{name: "type", filter: &Filter{Type: polochon.TypeMovie}, expected: []uint64{2}},
+		{name: "outcome", filter: &Filter{Outcome: OutcomeGrabbed}, expected: []uint64{3, 2}},
+		{name: "episode", filter: &Filter{Season: 1, Episode: 1}, expected: []uint64{3}},
+		{name: "since", filter: &Filter{Since: testTime.Add(3 * time.Hour)}, expected: []uint64{4, 3}},
+		{name: "limit", filter: &Filter{Limit: 1}, expected: []uint64{4}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := []uint64{}
+			for _, e := range s.Entries(tc.filter) {
+				got = append(got, e.ID)
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNilStore(t *testing.T) {
+	var s *Store
+
+	if err := s.Record(NewEntry(testTorrent, OutcomeGrabbed)); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if !s.CanAttempt("tt001", 1, 2) || s.IsBlocked(testTorrent) || len(s.Entries(nil)) != 0 {
+		t.Fatal("a nil store should not record anything")
+	}
+}
@@ -1,6 +1,11 @@
 package polochon
 
-import "errors"
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+)
 
 // Language type
 type Language string
@@ -74,3 +79,19 @@ func NewLanguageFromISO6392(code string) (Language, error) {
 	}
 	return "", ErrInvalidLanguage
 }
+
+// LanguageFromFilename returns the Language found in a file name e.g.
+// "Movie.2019.en.srt" or "Subs/2_English.srt", the last tokens of the name
+// are checked first
+func LanguageFromFilename(name string) (Language, error) {
+	tokens := nameTokens(removeExt(filepath.Base(name)))
+	for _, token := range slices.Backward(tokens) {
+		for lang, info := range langInfo {
+			if token == info.ShortForm || token == info.ISO6392 || token == strings.ToLower(info.Name) {
+				return lang, nil
+			}
+		}
+	}
+
+	return "", ErrInvalidLanguage
+}
@@ -108,3 +108,29 @@ func TestNewLanguageFromISO6392(t *testing.T) {
 		})
 	}
 }
+
+func TestLanguageFromFilename(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		want    Language
+		wantErr error
+	}{
+		{name: "Movie.2019.720p.en.srt", want: EN},
+		{name: "Movie.2019.720p.fre.srt", want: FR},
+		{name: "Subs/Show.S01E01/2_English.srt", want: EN},
+		{name: "Subs/French.srt", want: FR},
+		{name: "Movie.2019.en.fr.srt", want: FR},
+		{name: "Movie.2019.720p.srt", wantErr: ErrInvalidLanguage},
+		{name: "Subs/english/forced.srt", wantErr: ErrInvalidLanguage},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := LanguageFromFilename(tc.name)
+			if err != tc.wantErr {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
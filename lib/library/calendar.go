@@ -0,0 +1,232 @@
+package library
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/sirupsen/logrus"
+)
+
+// calendarTTL is the duration during which the calendar of a show is kept
+const calendarTTL = time.Hour
+
+// cachedCalendar represents a show calendar kept in cache
+type cachedCalendar struct {
+	title     string
+	calendar  *polochon.ShowCalendar
+	fetchedAt time.Time
+}
+
+// CalendarEpisode represents an episode of a show calendar
+type CalendarEpisode struct {
+	ShowImdbID     string     `json:"show_imdb_id"`
+	ShowTitle      string     `json:"show_title"`
+	Season         int        `json:"season"`
+	Episode        int        `json:"episode"`
+	AbsoluteNumber int        `json:"absolute_number,omitempty"`
+	AiredDate      *time.Time `json:"aired_date"`
+	InLibrary      bool       `json:"in_library"`
+}
+
+// CalendarShow represents a show to get the calendar of
+type CalendarShow struct {
+	ImdbID string
+	// Wished holds the wish of the show, nil if the show is not wished
+	Wished *polochon.WishedShow
+}
+
+// specials tells if the specials of the show are wanted
+func (s CalendarShow) specials() bool {
+	return s.Wished != nil && s.Wished.Specials
+}
+
+// wanted tells if an episode is wanted, the episodes before the start of the
+// wish and the old episodes of the daily shows are skipped like the
+// downloader does
+func (s CalendarShow) wanted(e *polochon.ShowCalendarEpisode) bool {
+	if e.IsSpecial() && !s.specials() {
+		return false
+	}
+
+	if s.Wished == nil {
+		return true
+	}
+
+	if s.Wished.Daily {
+		return e.IsRecent(s.Wished.WantedDays())
+	}
+
+	return !e.IsOlder(s.Wished)
+}
+
+// ShowCalendar returns the calendar of a show, the calendars are kept in
+// cache for an hour
+func (l *Library) ShowCalendar(show *polochon.Show, log *logrus.Entry) (*polochon.ShowCalendar, error) {
+	if c := l.cachedCalendar(show.ImdbID); c != nil {
+		return c.calendar, nil
+	}
+
+	calendar, err := show.GetCalendar(log)
+	if err != nil {
+		return nil, err
+	}
+
+	if calendar == nil {
+		return nil, polochon.ErrCalendarNotFound
+	}
+
+	l.calendarMu.Lock()
+	defer l.calendarMu.Unlock()
+	if l.calendars == nil {
+		l.calendars = map[string]*cachedCalendar{}
+	}
+	l.calendars[show.ImdbID] = &cachedCalendar{
+		title:     show.Title,
+		calendar:  calendar,
+		fetchedAt: time.Now(),
+	}
+
+	return calendar, nil
+}
+
+// cachedCalendar returns the calendar of a show from the cache, nil is
+// returned if it's not in the cache or if it expired, the expired calendars
+// are removed from the cache on a miss
+func (l *Library) cachedCalendar(id string) *cachedCalendar {
+	l.calendarMu.Lock()
+	defer l.calendarMu.Unlock()
+
+	c, ok := l.calendars[id]
+	if ok && time.Since(c.fetchedAt) <= calendarTTL {
+		return c
+	}
+
+	for id, c := range l.calendars {
+		if time.Since(c.fetchedAt) > calendarTTL {
+			delete(l.calendars, id)
+		}
+	}
+
+	return nil
+}
+
+// showCalendar returns the calendar of a show from its id along with the show
+// title, the show details are read from the library if the show is in it
+func (l *Library) showCalendar(id string, log *logrus.Entry) (string, *polochon.ShowCalendar, error) {
+	if c := l.cachedCalendar(id); c != nil {
+		return c.title, c.calendar, nil
+	}
+
+	show, err := l.GetShow(id)
+	if err != nil {
+		show = polochon.NewShow(l.showConfig)
+		show.ImdbID = id
+		if err := polochon.GetDetails(show, log); err != nil {
+			return "", nil, err
+		}
+	}
+
+	calendar, err := l.ShowCalendar(show, log)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return show.Title, calendar, nil
+}
+
+// newCalendarEpisode returns the calendar episode of a show
+func (l *Library) newCalendarEpisode(id, title string, e *polochon.ShowCalendarEpisode) *CalendarEpisode {
+	ok, _ := l.HasShowEpisode(id, e.Season, e.Episode)
+
+	aired := e.AiredDate
+	if aired != nil && aired.IsZero() {
+		aired = nil
+	}
+
+	return &CalendarEpisode{
+		ShowImdbID:     id,
+		ShowTitle:      title,
+		Season:         e.Season,
+		Episode:        e.Episode,
+		AbsoluteNumber: e.AbsoluteNumber,
+		AiredDate:      aired,
+		InLibrary:      ok,
+	}
+}
+
+// Calendar returns the episodes of the shows aired between two dates sorted
+// by aired date, the shows without calendar are skipped
+func (l *Library) Calendar(shows []CalendarShow, from, to time.Time, log *logrus.Entry) []*CalendarEpisode {
+	episodes := []*CalendarEpisode{}
+	for _, s := range shows {
+		slog := log.WithField("imdb_id", s.ImdbID)
+
+		title, calendar, err := l.showCalendar(s.ImdbID, slog)
+		if err != nil {
+			slog.Warnf("failed to get show calendar: %s", err)
+			continue
+		}
+
+		for _, e := range calendar.Episodes {
+			if e.IsSpecial() && !s.specials() {
+				continue
+			}
+
+			if e.AiredDate == nil || e.AiredDate.IsZero() ||
+				e.AiredDate.Before(from) || e.AiredDate.After(to) {
+				continue
+			}
+
+			episodes = append(episodes, l.newCalendarEpisode(s.ImdbID, title, e))
+		}
+	}
+
+	slices.SortStableFunc(episodes, func(a, b *CalendarEpisode) int {
+		return cmp.Or(
+			a.AiredDate.Compare(*b.AiredDate),
+			cmp.Compare(a.ShowTitle, b.ShowTitle),
+			cmp.Compare(a.Season, b.Season),
+			cmp.Compare(a.Episode, b.Episode),
+		)
+	})
+
+	return episodes
+}
+
+// MissingEpisodes returns the aired episodes of a show that are not in the
+// library, only the episodes wanted by the wish of the show are returned
+func (l *Library) MissingEpisodes(show CalendarShow, log *logrus.Entry) ([]*CalendarEpisode, error) {
+	title, calendar, err := l.showCalendar(show.ImdbID, log)
+	if err != nil {
+		return nil, err
+	}
+
+	episodes := []*CalendarEpisode{}
+	for _, e := range calendar.Episodes {
+		if !show.wanted(e) {
+			continue
+		}
+
+		if !e.IsAvailable() {
+			continue
+		}
+
+		ce := l.newCalendarEpisode(show.ImdbID, title, e)
+		if ce.InLibrary {
+			continue
+		}
+
+		episodes = append(episodes, ce)
+	}
+
+	slices.SortStableFunc(episodes, func(a, b *CalendarEpisode) int {
+		return cmp.Or(
+			cmp.Compare(a.Season, b.Season),
+			cmp.Compare(a.Episode, b.Episode),
+		)
+	})
+
+	return episodes, nil
+}
@@ -0,0 +1,134 @@
+package library
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/sirupsen/logrus"
+)
+
+// fakeCalendar is a calendar returning the same episodes for every show
+type fakeCalendar struct {
+	episodes []*polochon.ShowCalendarEpisode
+	calls    int
+}
+
+func (c *fakeCalendar) Init([]byte) error                      { return nil }
+func (c *fakeCalendar) Name() string                           { return "fake" }
+func (c *fakeCalendar) Status() (polochon.ModuleStatus, error) { return polochon.StatusOK, nil }
+func (c *fakeCalendar) GetShowCalendar(s *polochon.Show, _ *logrus.Entry) (*polochon.ShowCalendar, error) {
+	c.calls++
+	calendar := polochon.NewShowCalendar(s.ImdbID)
+	calendar.Episodes = c.episodes
+	return calendar, nil
+}
+
+func TestCalendar(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	show, err := lib.mockShow()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	// The first episode is in the library
+	ep, err := lib.mockEpisode(show, "episode1.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := lib.Add(ep, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the episode: %q", err)
+	}
+
+	now := time.Now()
+	date := func(days int) *time.Time {
+		d := now.Add(time.Duration(days) * 24 * time.Hour)
+		return &d
+	}
+
+	calendar := &fakeCalendar{
+		episodes: []*polochon.ShowCalendarEpisode{
+			{Season: 0, Episode: 1, AiredDate: date(-20)},
+			{Season: 1, Episode: 1, AiredDate: date(-20)},
+			{Season: 1, Episode: 2, AiredDate: date(-10)},
+			{Season: 1, Episode: 3, AiredDate: date(-2)},
+			{Season: 1, Episode: 4, AiredDate: date(5)},
+			{Season: 1, Episode: 5, AiredDate: date(40)},
+		},
+	}
+	lib.showConfig.Calendar = calendar
+
+	shows := []CalendarShow{{ImdbID: ep.ShowImdbID}}
+	episodes := lib.Calendar(shows, now.Add(-7*24*time.Hour), now.Add(30*24*time.Hour), mockLogEntry)
+	if len(episodes) != 2 || episodes[0].Episode != 3 || episodes[1].Episode != 4 {
+		t.Fatalf("expected the episodes 3 and 4, got %+v", episodes)
+	}
+
+	if episodes[0].ShowTitle != show.Title {
+		t.Errorf("expected show title %q, got %q", show.Title, episodes[0].ShowTitle)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		wished   *polochon.WishedShow
+		expected []int
+	}{
+		{name: "not wished", expected: []int{102, 103}},
+		{name: "with specials", wished: &polochon.WishedShow{Specials: true}, expected: []int{1, 102, 103}},
+		{name: "from an episode", wished: &polochon.WishedShow{Season: 1, Episode: 3}, expected: []int{103}},
+		{name: "daily", wished: &polochon.WishedShow{Daily: true, DailyDays: 5}, expected: []int{103}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			missing, err := lib.MissingEpisodes(CalendarShow{ImdbID: ep.ShowImdbID, Wished: tc.wished}, mockLogEntry)
+			if err != nil {
+				t.Fatalf("expected no error, got %q", err)
+			}
+
+			var got []int
+			for _, e := range missing {
+				got = append(got, e.Season*100+e.Episode)
+			}
+
+			if !slices.Equal(got, tc.expected) {
+				t.Errorf("expected the missing episodes %v, got %v", tc.expected, got)
+			}
+		})
+	}
+
+	// The calendar is kept in cache
+	if calendar.calls != 1 {
+		t.Errorf("expected the calendar to be fetched once, got %d calls", calendar.calls)
+	}
+}
+
+func TestCachedCalendarExpiration(t *testing.T) {
+	lib := &Library{calendars: map[string]*cachedCalendar{
+		"tt001": {fetchedAt: time.Now()},
+		"tt002": {fetchedAt: time.Now().Add(-2 * calendarTTL)},
+		"tt003": {fetchedAt: time.Now().Add(-2 * calendarTTL)},
+	}}
+
+	if c := lib.cachedCalendar("tt001"); c == nil {
+		t.Fatal("expected a cached calendar")
+	}
+
+	if c := lib.cachedCalendar("tt002"); c != nil {
+		t.Fatal("expected the calendar to be expired")
+	}
+
+	// The expired calendars are removed on a miss
+	if _, ok := lib.calendars["tt003"]; ok {
+		t.Error("expected the expired calendars to be removed")
+	}
+
+	if _, ok := lib.calendars["tt001"]; !ok {
+		t.Error("expected the valid calendar to be kept")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/configuration"
 	index "github.com/odwrtw/polochon/lib/media_index"
 	"github.com/sirupsen/logrus"
 )
@@ -18,6 +19,11 @@ func (l *Library) HasShowEpisode(imdbID string, season, episode int) (bool, erro
 
 // AddShowEpisode adds an episode to the store
 func (l *Library) AddShowEpisode(ep *polochon.ShowEpisode, logEntry *logrus.Entry) error {
+	return l.importShowEpisode(ep, configuration.ImportMove, logEntry)
+}
+
+// importShowEpisode adds an episode to the store using the import mode
+func (l *Library) importShowEpisode(ep *polochon.ShowEpisode, mode configuration.ImportMode, logEntry *logrus.Entry) error {
 	log := logEntry.WithFields(logrus.Fields{
 		"type":         "show_episode",
 		"show_title":   ep.ShowTitle,
@@ -29,29 +35,35 @@ func (l *Library) AddShowEpisode(ep *polochon.ShowEpisode, logEntry *logrus.Entr
 		return ErrMissingShowEpisodeFilePath
 	}
 
-	ok, err := l.HasShowEpisode(ep.ShowImdbID, ep.Season, ep.Episode)
-	if err != nil {
-		return err
-	}
-	if ok {
-		// Get the old episode from the index
-		oldEpisode, err := l.GetEpisode(ep.ShowImdbID, ep.Season, ep.Episode)
+	// The old episodes are only removed once the new one is in place, a
+	// multi-episode file replaces each of the episodes it contains
+	oldEpisodes := map[string]*polochon.ShowEpisode{}
+	for _, eNum := range ep.EpisodeNumbers() {
+		ok, err := l.HasShowEpisode(ep.ShowImdbID, ep.Season, eNum)
 		if err != nil {
 			return err
 		}
 
-		if err := l.DeleteShowEpisode(oldEpisode, log); err != nil {
+		if !ok {
+			continue
+		}
+
+		// Get the old episode from the index
+		oldEpisode, err := l.GetEpisode(ep.ShowImdbID, ep.Season, eNum)
+		if err != nil {
 			return err
 		}
+		oldEpisodes[oldEpisode.Path] = oldEpisode
 	}
 
 	// Add the show
-	if err := l.addShow(ep, log); err != nil {
+	showDir, err := l.addShow(ep, log)
+	if err != nil {
 		return err
 	}
 
 	// Create show season dir if necessary
-	seasonDir := l.getSeasonDir(ep)
+	seasonDir := l.getSeasonDir(ep, showDir)
 	if !exists(seasonDir) {
 		if err := os.Mkdir(seasonDir, os.ModePerm); err != nil {
 			return err
@@ -68,22 +80,35 @@ func (l *Library) AddShowEpisode(ep *polochon.ShowEpisode, logEntry *logrus.Entr
 	// Save the old path
 	oldPath := ep.Path
 
-	// Move the episode into the folder
-	newPath := filepath.Join(seasonDir, path.Base(ep.Path))
+	// Import the episode into the folder
+	newPath := filepath.Join(seasonDir, l.getEpisodeFileName(ep))
 	log.WithFields(logrus.Fields{
-		"old_path": ep.Path,
-		"new_path": newPath,
-	}).Debugf("moving episode")
-	if err := MoveFile(ep.Path, newPath); err != nil {
+		"old_path":    ep.Path,
+		"new_path":    newPath,
+		"import_mode": mode,
+	}).Debugf("importing episode")
+	if err := importFile(ep.Path, newPath, mode); err != nil {
 		return err
 	}
 
 	// Set the new episode path
 	ep.Path = newPath
 
+	// The new episode is in place, the old ones can be removed
+	for path, oldEpisode := range oldEpisodes {
+		if path == ep.Path {
+			continue
+		}
+
+		log.WithField("path", path).Info("removing replaced episode file")
+		if err := removeVideoFiles(oldEpisode); err != nil {
+			return err
+		}
+	}
+
 	// Create a symlink between the new and the old location
-	// Only if the downloader is enabled
-	if l.downloaderConfig.Enabled {
+	// Only if the downloader is enabled and the file has been moved
+	if l.downloaderConfig.Enabled && !mode.KeepsSource() {
 		if err := os.Symlink(ep.Path, oldPath); err != nil {
 			log.Warnf("error while making symlink")
 		}
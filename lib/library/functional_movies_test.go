@@ -1,6 +1,7 @@
 package library
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +9,7 @@ import (
 	"testing"
 
 	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/configuration"
 	_ "github.com/odwrtw/polochon/modules/mock"
 )
 
@@ -215,3 +217,157 @@ func TestDeleteMovie(t *testing.T) {
 		t.Fatalf("the library should contains 0 movie instead of %d", movieCount)
 	}
 }
+
+func TestUpgradeMovie(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	old, err := lib.mockMovie("movieTest.480p.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+	old.Quality = polochon.Quality480p
+
+	if err := lib.Add(old, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the movie: %q", err)
+	}
+
+	m, err := lib.mockMovie("movieTest.1080p.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+	m.Quality = polochon.Quality1080p
+
+	quality, err := lib.IndexedQuality(m)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+	if quality != polochon.Quality480p {
+		t.Errorf("expected quality %q, got %q", polochon.Quality480p, quality)
+	}
+
+	if err := lib.Add(m, mockLogEntry); err != nil {
+		t.Fatalf("failed to upgrade the movie: %q", err)
+	}
+
+	// The old files should be removed and the new ones should be there
+	for path, expected := range map[string]bool{
+		old.Path:                      false,
+		old.NfoPath():                 false,
+		old.SubtitlePath(polochon.FR): false,
+		m.Path:                        true,
+		m.NfoPath():                   true,
+		m.SubtitlePath(polochon.FR):   true,
+	} {
+		if got := exists(path); got != expected {
+			t.Errorf("%q: expected exists to be %t, got %t", path, expected, got)
+		}
+	}
+
+	quality, err = lib.IndexedQuality(m)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+	if quality != polochon.Quality1080p {
+		t.Errorf("expected quality %q, got %q", polochon.Quality1080p, quality)
+	}
+}
+
+func TestAddMovieDirConflict(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	m, err := lib.mockMovie("movieTest.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := lib.Add(m, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the movie: %q", err)
+	}
+
+	// Another movie with the same title and year
+	remake, err := lib.mockMovie("remake.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+	remake.ImdbID = "tt67890"
+
+	if err := lib.Add(remake, mockLogEntry); !errors.Is(err, ErrMovieDirConflict) {
+		t.Fatalf("expected error %q, got %v", ErrMovieDirConflict, err)
+	}
+
+	if !polochon.NewFile(m.Path).Exists() {
+		t.Fatal("the movie already in the library should not be removed")
+	}
+}
+
+func TestImportMovie(t *testing.T) {
+	for _, mode := range []configuration.ImportMode{
+		configuration.ImportCopy,
+		configuration.ImportHardlink,
+		configuration.ImportReflink,
+	} {
+		t.Run(string(mode), func(t *testing.T) {
+			lib, err := newMockLibrary()
+			defer lib.cleanup()
+
+			if err != nil {
+				t.Fatalf("expected no error, got %q", err)
+			}
+			lib.downloaderConfig.Enabled = true
+
+			m, err := lib.mockMovie("movieTest.mp4")
+			if err != nil {
+				t.Fatalf("expected no error, got %q", err)
+			}
+
+			content := []byte("movie content")
+			if err := os.WriteFile(m.Path, content, 0644); err != nil {
+				t.Fatalf("expected no error, got %q", err)
+			}
+
+			source := polochon.NewFile(m.Path)
+			if err := lib.Import(m, mode, mockLogEntry); err != nil {
+				t.Fatalf("failed to import the movie: %q", err)
+			}
+
+			// The source file should be left untouched for the torrent to
+			// keep seeding
+			if !source.Exists() || source.IsSymlink() {
+				t.Fatalf("the source file %q should still be a regular file", source.Path)
+			}
+
+			got, err := os.ReadFile(m.Path)
+			if err != nil {
+				t.Fatalf("expected no error, got %q", err)
+			}
+
+			if !reflect.DeepEqual(got, content) {
+				t.Errorf("expected content %q, got %q", content, got)
+			}
+
+			sourceInfo, err := os.Stat(source.Path)
+			if err != nil {
+				t.Fatalf("expected no error, got %q", err)
+			}
+
+			movieInfo, err := os.Stat(m.Path)
+			if err != nil {
+				t.Fatalf("expected no error, got %q", err)
+			}
+
+			if same := os.SameFile(sourceInfo, movieInfo); same != (mode == configuration.ImportHardlink) {
+				t.Errorf("expected same file to be %t, got %t", mode == configuration.ImportHardlink, same)
+			}
+		})
+	}
+}
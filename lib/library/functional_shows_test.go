@@ -1,6 +1,7 @@
 package library
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -283,6 +284,51 @@ func testSeason(t *testing.T, episode *polochon.ShowEpisode, lib *mockLibrary) {
 	}
 }
 
+func TestAddEpisodeShowDirConflict(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	show, err := lib.mockShow()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+	show.Episodes = nil
+
+	episode, err := lib.mockEpisode(show, "episodeTest.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := lib.Add(episode, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the episode: %q", err)
+	}
+
+	// Another show with the same title
+	remake, err := lib.mockShow()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+	remake.Episodes = nil
+	remake.ImdbID = "tt67890"
+
+	remakeEpisode, err := lib.mockEpisode(remake, "remakeTest.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+	remakeEpisode.ShowImdbID = remake.ImdbID
+
+	if err := lib.Add(remakeEpisode, mockLogEntry); !errors.Is(err, ErrShowDirConflict) {
+		t.Fatalf("expected error %q, got %v", ErrShowDirConflict, err)
+	}
+
+	if has, _ := lib.HasShowEpisode(remake.ImdbID, remakeEpisode.Season, remakeEpisode.Episode); has {
+		t.Error("the episode of the other show should not be in the library")
+	}
+}
+
 func TestDeleteEpisode(t *testing.T) {
 	lib, err := newMockLibrary()
 	defer lib.cleanup()
@@ -333,3 +379,70 @@ func TestDeleteEpisode(t *testing.T) {
 		t.Errorf("invalid show ids, expected %+v got %+v", expectedIDs, gotIDs)
 	}
 }
+
+func TestAddMultiEpisode(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	show, err := lib.mockShow()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	// The second episode is already in the library
+	single, err := lib.mockEpisode(show, "episode2.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+	single.Episode = 2
+
+	if err := lib.Add(single, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the episode: %q", err)
+	}
+
+	multi, err := lib.mockEpisode(show, "episode1-2.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+	multi.LastEpisode = 2
+
+	if err := lib.Add(multi, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the episode: %q", err)
+	}
+
+	if exists(single.Path) {
+		t.Error("the episode contained in the multi-episode file should be replaced")
+	}
+
+	for _, eNum := range []int{1, 2} {
+		e, err := lib.GetIndexedEpisode(multi.ShowImdbID, multi.Season, eNum)
+		if err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+
+		if e.Path != multi.Path {
+			t.Errorf("expected episode %d to be in %q, got %q", eNum, multi.Path, e.Path)
+		}
+	}
+
+	season, err := lib.showIndex.IndexedSeason(multi.ShowImdbID, multi.Season)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if len(season.Episodes) != 1 {
+		t.Errorf("expected the multi-episode file to be indexed once, got %d episodes", len(season.Episodes))
+	}
+
+	// The index built from the NFO files is the same
+	if err := lib.RebuildIndex(mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if ok, _ := lib.HasShowEpisode(multi.ShowImdbID, multi.Season, 2); !ok {
+		t.Error("the second episode should be in the rebuilt index")
+	}
+}
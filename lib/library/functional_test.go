@@ -56,8 +56,9 @@ func newMockLibrary() (*mockLibrary, error) {
 
 	// Create the library configuration
 	config := configuration.LibraryConfig{
-		MovieDir: filepath.Join(tmpDir, "movies"),
-		ShowDir:  filepath.Join(tmpDir, "shows"),
+		MovieDir:  filepath.Join(tmpDir, "movies"),
+		ShowDir:   filepath.Join(tmpDir, "shows"),
+		IndexPath: filepath.Join(tmpDir, "index.json"),
 	}
 
 	// Create the folder to hold the movies, shows and downloads
@@ -0,0 +1,221 @@
+package library
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/sirupsen/logrus"
+)
+
+// Custom errors
+var (
+	ErrMovieAlreadyExists       = errors.New("library: movie already in the library")
+	ErrShowEpisodeAlreadyExists = errors.New("library: episode already in the library")
+)
+
+// EpisodeIdentity represents the identity of an episode, the empty fields
+// keep the current values, a season 0 is used for the specials
+type EpisodeIdentity struct {
+	ShowImdbID string `json:"show_imdb_id,omitempty"`
+	Season     *int   `json:"season,omitempty"`
+	Episode    *int   `json:"episode,omitempty"`
+}
+
+// IdentifyMovie identifies a movie of the library as another one, its details
+// are fetched again, its files are moved to the directory of the new movie and
+// its NFO and images are replaced. The events describing the changes made to
+// the index are returned.
+func (l *Library) IdentifyMovie(id, newID string, log *logrus.Entry) (*polochon.Movie, []*polochon.Event, error) {
+	log = log.WithFields(logrus.Fields{
+		"type":        "movie",
+		"imdb_id":     id,
+		"new_imdb_id": newID,
+	})
+
+	movie, err := l.GetMovie(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if newID != id {
+		ok, err := l.HasMovie(newID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if ok {
+			return nil, nil, ErrMovieAlreadyExists
+		}
+	}
+
+	m := polochon.NewMovieFromFile(l.movieConfig, movie.File)
+	m.ImdbID = newID
+	m.VideoMetadata = movie.VideoMetadata
+	if err := polochon.GetDetails(m, log); err != nil {
+		return nil, nil, err
+	}
+
+	// Move the movie directory
+	oldDir, newDir := filepath.Dir(movie.Path), l.getMovieDir(m)
+	if oldDir != newDir {
+		if exists(newDir) {
+			return nil, nil, ErrReorganizeConflict
+		}
+
+		log.WithField("path", newDir).Debug("moving movie directory")
+		if err := os.Rename(oldDir, newDir); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Rename the video along with its subtitles
+	m.Path = filepath.Join(newDir, filepath.Base(movie.Path))
+	newPath := filepath.Join(newDir, l.getMovieFileName(m))
+	if err := l.moveVideoFiles(m.Path, newPath); err != nil {
+		return nil, nil, err
+	}
+	m.Path = newPath
+
+	// The images and the NFO describe the previous movie
+	for _, path := range []string{
+		m.MovieFanartPath(),
+		m.MovieThumbPath(),
+		filepath.Join(newDir, movieDirNFOName),
+	} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, nil, err
+		}
+	}
+
+	l.UpdateSubtitles(m)
+	if err := writeNFOFile(m.NfoPath(), m); err != nil {
+		return nil, nil, err
+	}
+
+	downloadMissingImages(map[string]string{
+		m.MovieFanartPath(): m.Fanart,
+		m.MovieThumbPath():  m.Thumb,
+	}, log)
+
+	return m, l.RefreshPaths([]string{movie.Path, m.Path}, log), nil
+}
+
+// IdentifyShowEpisode identifies an episode of the library as another one,
+// its details are fetched again, its files are moved to the directory of the
+// new episode and its NFO is replaced. The events describing the changes made
+// to the index are returned.
+func (l *Library) IdentifyShowEpisode(id string, season, episode int, identity EpisodeIdentity, log *logrus.Entry) (*polochon.ShowEpisode, []*polochon.Event, error) {
+	newID, newSeason, newEpisode := id, season, episode
+	if identity.ShowImdbID != "" {
+		newID = identity.ShowImdbID
+	}
+	if identity.Season != nil {
+		newSeason = *identity.Season
+	}
+	if identity.Episode != nil {
+		newEpisode = *identity.Episode
+	}
+
+	log = log.WithFields(logrus.Fields{
+		"type":             "show_episode",
+		"show_imdb_id":     id,
+		"season":           season,
+		"episode":          episode,
+		"new_show_imdb_id": newID,
+		"new_season":       newSeason,
+		"new_episode":      newEpisode,
+	})
+
+	ep, err := l.GetEpisode(id, season, episode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if newID != id || newSeason != season || newEpisode != episode {
+		ok, err := l.HasShowEpisode(newID, newSeason, newEpisode)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if ok {
+			return nil, nil, ErrShowEpisodeAlreadyExists
+		}
+	}
+
+	indexedShow, err := l.showIndex.IndexedShow(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get the show of the episode
+	show, err := l.GetShow(newID)
+	if err != nil {
+		show = polochon.NewShow(l.showConfig)
+		show.ImdbID = newID
+		if err := polochon.GetDetails(show, log); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	e := polochon.NewShowEpisodeFromFile(l.showConfig, ep.File)
+	e.ShowImdbID = newID
+	e.ShowTvdbID = show.TvdbID
+	e.ShowTitle = show.Title
+	e.Season = newSeason
+	e.Episode = newEpisode
+	e.VideoMetadata = ep.VideoMetadata
+	e.Show = show
+	if err := polochon.GetDetails(e, log); err != nil {
+		return nil, nil, err
+	}
+
+	// Add the show if needed
+	showDir, err := l.addShow(e, log)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	seasonDir := l.getSeasonDir(e, showDir)
+	if !exists(seasonDir) {
+		if err := os.Mkdir(seasonDir, os.ModePerm); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Move the video along with its NFO and subtitles
+	newPath := filepath.Join(seasonDir, l.getEpisodeFileName(e))
+	if err := l.moveVideoFiles(ep.Path, newPath); err != nil {
+		return nil, nil, err
+	}
+	e.Path = newPath
+
+	l.UpdateSubtitles(e)
+	if err := writeNFOFile(e.NfoPath(), e); err != nil {
+		return nil, nil, err
+	}
+
+	paths := []string{ep.Path, e.Path}
+
+	// Remove the directories left without episode
+	oldSeasonDir := filepath.Dir(ep.Path)
+	if entries, err := os.ReadDir(oldSeasonDir); err == nil && len(entries) == 0 {
+		log.WithField("path", oldSeasonDir).Debug("removing empty season directory")
+		_ = os.Remove(oldSeasonDir)
+	}
+
+	episodes := 0
+	for _, s := range indexedShow.Seasons {
+		episodes += len(s.Episodes)
+	}
+
+	if newID != id && showDir != indexedShow.Path && episodes == 1 {
+		log.WithField("path", indexedShow.Path).Info("removing show directory left without episode")
+		if err := os.RemoveAll(indexedShow.Path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return e, l.RefreshPaths(paths, log), nil
+}
@@ -0,0 +1,180 @@
+package library
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+func TestIdentifyMovie(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	m, err := lib.mockMovie("movieTest.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := lib.Add(m, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the movie: %q", err)
+	}
+
+	if err := lib.RebuildIndex(mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	lib.movieConfig.Detailers = []polochon.Detailer{
+		&imageDetailer{Detailer: lib.movieConfig.Detailers[0], url: lib.httpServer.URL},
+	}
+
+	// Only the movies of the library can be identified
+	if _, _, err := lib.IdentifyMovie("tt00000", "tt99999", mockLogEntry); err == nil {
+		t.Fatal("expected an error for a movie not in the library")
+	}
+
+	got, events, err := lib.IdentifyMovie(m.ImdbID, "tt99999", mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expectedPath := filepath.Join(lib.MovieDir, "Movie tt99999 (2000)", "movieTest.mp4")
+	if got.Path != expectedPath {
+		t.Errorf("expected path %q, got %q", expectedPath, got.Path)
+	}
+
+	for _, path := range []string{
+		got.NfoPath(),
+		got.MovieFanartPath(),
+		got.MovieThumbPath(),
+	} {
+		if !exists(path) {
+			t.Errorf("%s should exist", path)
+		}
+	}
+
+	if exists(filepath.Dir(m.Path)) {
+		t.Error("the previous movie directory should be moved")
+	}
+
+	if len(events) != 2 {
+		t.Errorf("expected 2 events, got %+v", events)
+	}
+
+	if ok, _ := lib.HasMovie(m.ImdbID); ok {
+		t.Error("the previous movie should not be in the index")
+	}
+
+	indexed, err := lib.GetIndexedMovie("tt99999")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if indexed.Path != expectedPath {
+		t.Errorf("expected indexed path %q, got %q", expectedPath, indexed.Path)
+	}
+
+	movie, err := lib.GetMovie("tt99999")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if movie.Title != "Movie tt99999" {
+		t.Errorf("expected the NFO to be rewritten, got title %q", movie.Title)
+	}
+}
+
+func TestIdentifyShowEpisode(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	show, err := lib.mockShow()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	var episodes []*polochon.ShowEpisode
+	for _, name := range []string{"episode1.mp4", "episode2.mp4"} {
+		ep, err := lib.mockEpisode(show, name)
+		if err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+		ep.Episode = len(episodes) + 1
+
+		if err := lib.Add(ep, mockLogEntry); err != nil {
+			t.Fatalf("failed to add the episode: %q", err)
+		}
+		episodes = append(episodes, ep)
+	}
+
+	if err := lib.RebuildIndex(mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	ep := episodes[0]
+	_, _, err = lib.IdentifyShowEpisode(ep.ShowImdbID, ep.Season, ep.Episode, EpisodeIdentity{Episode: intPtr(2)}, mockLogEntry)
+	if !errors.Is(err, ErrShowEpisodeAlreadyExists) {
+		t.Fatalf("expected error %q, got %q", ErrShowEpisodeAlreadyExists, err)
+	}
+
+	got, _, err := lib.IdentifyShowEpisode(ep.ShowImdbID, ep.Season, ep.Episode, EpisodeIdentity{Season: intPtr(2), Episode: intPtr(3)}, mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expectedPath := filepath.Join(lib.ShowDir, show.Title, "Season 2", "episode1.mp4")
+	if got.Path != expectedPath {
+		t.Errorf("expected path %q, got %q", expectedPath, got.Path)
+	}
+
+	if !exists(got.NfoPath()) {
+		t.Error("the episode NFO should be moved")
+	}
+
+	if has, _ := lib.HasShowEpisode(ep.ShowImdbID, ep.Season, ep.Episode); has {
+		t.Error("the previous episode should not be in the index")
+	}
+
+	indexed, err := lib.GetIndexedEpisode(ep.ShowImdbID, 2, 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if indexed.Path != expectedPath {
+		t.Errorf("expected indexed path %q, got %q", expectedPath, indexed.Path)
+	}
+
+	e, err := lib.GetEpisode(ep.ShowImdbID, 2, 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if e.Season != 2 || e.Episode != 3 {
+		t.Errorf("expected the NFO to be rewritten, got S%02dE%02d", e.Season, e.Episode)
+	}
+
+	// The season 0 is used for the specials
+	got, _, err = lib.IdentifyShowEpisode(ep.ShowImdbID, 2, 3, EpisodeIdentity{Season: intPtr(0)}, mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if got.Season != 0 || got.Episode != 3 {
+		t.Errorf("expected S00E03, got S%02dE%02d", got.Season, got.Episode)
+	}
+
+	if has, _ := lib.HasShowEpisode(ep.ShowImdbID, 0, 3); !has {
+		t.Error("the special should be in the index")
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
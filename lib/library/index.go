@@ -1,195 +1,368 @@
 package library
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
 
+	polochon "github.com/odwrtw/polochon/lib"
+	index "github.com/odwrtw/polochon/lib/media_index"
 	"github.com/sirupsen/logrus"
 )
 
-// RebuildIndex rebuilds both the movie and show index
+// movieDirNFOName is the name of the NFO describing the movie of a directory
+// whatever the name of the video file
+const movieDirNFOName = "movie.nfo"
+
+var errNoVideoFile = errors.New("library: no video file found")
+
+// IndexChanges represents the changes found while refreshing the index
+type IndexChanges struct {
+	Movies   ChangeCount `json:"movies"`
+	Shows    ChangeCount `json:"shows"`
+	Episodes ChangeCount `json:"episodes"`
+}
+
+// ChangeCount holds the number of items added, removed and changed in the
+// index
+type ChangeCount struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Changed int `json:"changed"`
+}
+
+func (c *ChangeCount) add(t changeType) {
+	switch t {
+	case changeAdded:
+		c.Added++
+	case changeRemoved:
+		c.Removed++
+	case changeChanged:
+		c.Changed++
+	}
+}
+
+type changeType int
+
+const (
+	changeNone changeType = iota
+	changeAdded
+	changeRemoved
+	changeChanged
+)
+
+// RebuildIndex rebuilds both the movie and show index, every directory of the
+// library is parsed again
 func (l *Library) RebuildIndex(log *logrus.Entry) error {
+	_, err := l.refreshIndex(true, log)
+	return err
+}
+
+// RefreshIndex refreshes both the movie and show index, only the directories
+// modified since the last refresh are parsed again
+func (l *Library) RefreshIndex(log *logrus.Entry) (*IndexChanges, error) {
+	return l.refreshIndex(false, log)
+}
+
+func (l *Library) refreshIndex(full bool, log *logrus.Entry) (*IndexChanges, error) {
+	l.indexMu.Lock()
+	defer l.indexMu.Unlock()
+
+	return l.refreshIndexLocked(full, log)
+}
+
+// refreshIndexLocked refreshes the index, the index lock must be held. The
+// current index is kept and nothing is saved if a directory could not be
+// scanned, e.g. if the library is not mounted yet.
+func (l *Library) refreshIndexLocked(full bool, log *logrus.Entry) (*IndexChanges, error) {
+	current := l.snapshot
+	prev := current
+	if full || prev == nil {
+		prev = l.newIndexSnapshot()
+	}
+
+	next := l.newIndexSnapshot()
+	changes := &IndexChanges{}
+
 	var wg sync.WaitGroup
 	errc := make(chan error, 2)
-	wg.Add(2)
 
 	// Build the movie index
-	go func() {
-		defer wg.Done()
-		if err := l.buildMovieIndex(log); err != nil {
+	wg.Go(func() {
+		movies, err := l.scanMovies(prev.Movies, log)
+		if err != nil {
 			errc <- err
+			if current != nil {
+				next.Movies = current.Movies
+			}
+			return
 		}
-	}()
+
+		next.Movies = movies
+		changes.Movies = l.replaceMovieIndex(movies)
+	})
 
 	// Build the show index
-	go func() {
-		defer wg.Done()
-		if err := l.buildShowIndex(log); err != nil {
+	wg.Go(func() {
+		shows, err := l.scanShows(prev.Shows, log)
+		if err != nil {
 			errc <- err
+			if current != nil {
+				next.Shows = current.Shows
+			}
+			return
 		}
-	}()
+
+		next.Shows = shows
+		changes.Shows, changes.Episodes = l.replaceShowIndex(shows)
+	})
 
 	// Wait for them to be done
 	wg.Wait()
 	close(errc)
 
-	// Return the first error found
-	err, ok := <-errc
-	if ok {
-		return err
+	// Keep the first error found
+	err := <-errc
+
+	l.snapshot = next
+	if err == nil {
+		if err := l.saveIndex(next); err != nil {
+			log.WithField("function", "save_index").Error(err)
+		}
 	}
 
-	return nil
+	log.WithFields(logrus.Fields{
+		"movies":   changes.Movies,
+		"shows":    changes.Shows,
+		"episodes": changes.Episodes,
+	}).Info("library index refreshed")
+
+	return changes, err
 }
 
-func (l *Library) buildMovieIndex(log *logrus.Entry) error {
+// scanMovies returns the movies found in the movie directory, the snapshots
+// of the directories left untouched are reused
+func (l *Library) scanMovies(prev map[string]*movieSnapshot, log *logrus.Entry) (map[string]*movieSnapshot, error) {
 	start := time.Now()
 	defer func() {
 		log.Infof("movie index built in %s", time.Since(start))
 	}()
-	l.movieIndex.Clear()
-
-	root, err := os.Open(l.MovieDir)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = root.Close() }()
 
-	dirs, err := root.Readdirnames(-1)
+	dirs, err := readDirNames(l.MovieDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	reg := regexp.MustCompile(`.*\(\d{4}\)$`)
-
+	movies := make(map[string]*movieSnapshot, len(dirs))
 	for _, d := range dirs {
-		if !reg.MatchString(d) {
-			log.WithField("dir", d).Warn("invalid movie dir")
-			continue
-		}
-
-		if err := l.buildFromMovieDir(d); err != nil {
-			log.WithField("dir", d).Error(err)
+		dlog := log.WithField("dir", d)
+		m, err := l.scanMovieDir(d, prev[d])
+		switch err {
+		case nil:
+			movies[d] = m
+		case errNoVideoFile:
+			dlog.Warn("no movie found in the dir")
+		default:
+			dlog.Error(err)
 		}
 	}
 
-	return nil
+	return movies, nil
 }
 
-func (l *Library) buildFromMovieDir(d string) error {
+// scanMovieDir returns the snapshot of a movie directory, the previous
+// snapshot is reused if the directory is left untouched
+func (l *Library) scanMovieDir(d string, prev *movieSnapshot) (*movieSnapshot, error) {
 	movieDir := filepath.Join(l.MovieDir, d)
+	fp, err := fingerprint(movieDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if prev != nil && prev.Fingerprint == fp {
+		return prev, nil
+	}
 
-	dir, err := os.Open(movieDir)
+	m, err := l.buildFromMovieDir(movieDir)
 	if err != nil {
-		return fmt.Errorf("failed to read movie dir %w", err)
+		return nil, err
 	}
-	defer func() { _ = dir.Close() }()
 
-	files, err := dir.Readdirnames(-1)
+	m.Fingerprint = fp
+	return m, nil
+}
+
+func (l *Library) buildFromMovieDir(movieDir string) (*movieSnapshot, error) {
+	files, err := readDirNames(movieDir)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to read movie dir %w", err)
 	}
 
+	// The movie is the video described by a NFO
 	var moviePath string
+	var moviePaths []string
 	for _, file := range files {
-		if l.fileConfig.IsVideo(file) {
-			moviePath = filepath.Join(movieDir, file)
+		if !l.fileConfig.IsVideo(file) {
+			continue
+		}
+
+		path := filepath.Join(movieDir, file)
+		if exists(polochon.NewFile(path).NfoPath()) {
+			moviePath = path
 			break
 		}
+
+		moviePaths = append(moviePaths, path)
+	}
+
+	// Without any NFO named after a video, the largest video is described by
+	// the directory NFO
+	if moviePath == "" && len(moviePaths) > 0 && exists(filepath.Join(movieDir, movieDirNFOName)) {
+		moviePath = largestFile(moviePaths)
 	}
 
 	if moviePath == "" {
-		return fmt.Errorf("no video file found")
+		return nil, errNoVideoFile
 	}
 
 	// Read the movie informations
 	movie, err := l.newMovieFromPath(moviePath)
 	if err != nil {
-		return fmt.Errorf("library: failed to read movie NFO: %w", err)
+		return nil, fmt.Errorf("library: failed to read movie NFO: %w", err)
 	}
 
-	return l.movieIndex.Add(movie)
+	return &movieSnapshot{
+		ImdbID: movie.ImdbID,
+		Path:   movie.Path,
+		Movie:  index.NewMovie(movie),
+	}, nil
 }
 
-func (l *Library) buildShowIndex(log *logrus.Entry) error {
+// scanShows returns the shows found in the show directory, the snapshots of
+// the directories left untouched are reused
+func (l *Library) scanShows(prev map[string]*showSnapshot, log *logrus.Entry) (map[string]*showSnapshot, error) {
 	start := time.Now()
 	defer func() {
 		log.Infof("show index built in %s", time.Since(start))
 	}()
 
-	l.showIndex.Clear()
-
-	root, err := os.Open(l.ShowDir)
+	dirs, err := readDirNames(l.ShowDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func() { _ = root.Close() }()
 
-	dirs, err := root.Readdirnames(-1)
-	if err != nil {
-		return err
+	shows := make(map[string]*showSnapshot, len(dirs))
+	for _, d := range dirs {
+		show, err := l.scanShow(d, prev[d], log)
+		if err != nil {
+			log.WithField("dir", d).Error(err)
+			continue
+		}
+
+		shows[d] = show
 	}
 
-	for _, d := range dirs {
-		showDir := filepath.Join(l.ShowDir, d)
-		nfoPath := l.showNFOPath(showDir)
+	return shows, nil
+}
+
+// scanShow returns the snapshot of a show directory, the previous snapshots
+// of the show and its seasons are reused if they're left untouched
+func (l *Library) scanShow(d string, prev *showSnapshot, log *logrus.Entry) (*showSnapshot, error) {
+	showDir := filepath.Join(l.ShowDir, d)
+	fp, err := fingerprint(showDir)
+	if err != nil {
+		return nil, err
+	}
 
-		show, err := l.newShowFromPath(nfoPath)
+	show := prev
+	if show == nil || show.Fingerprint != fp {
+		s, err := l.newShowFromPath(l.showNFOPath(showDir))
 		if err != nil {
-			log.Errorf("library: failed to read tv show NFO: %q", err)
-			continue
+			return nil, fmt.Errorf("library: failed to read tv show NFO: %w", err)
 		}
 
-		if err := l.buildFromShowDir(show.ImdbID, showDir, log); err != nil {
-			log.WithField("dir", d).Error(err)
+		show = &showSnapshot{
+			Fingerprint: fp,
+			ImdbID:      s.ImdbID,
+			Path:        showDir,
+			Show:        index.NewShow(s.Title, showDir),
 		}
 	}
 
-	return nil
-}
+	var prevSeasons map[string]*seasonSnapshot
+	if prev != nil && show.ImdbID == prev.ImdbID {
+		prevSeasons = prev.Seasons
+	}
 
-func (l *Library) buildFromShowDir(imdbID, showDir string, log *logrus.Entry) error {
-	dir, err := os.Open(showDir)
+	seasons, err := l.scanShowDir(show.ImdbID, showDir, prevSeasons, log.WithField("dir", d))
 	if err != nil {
-		return fmt.Errorf("failed to read movie dir %w", err)
+		return nil, err
 	}
-	defer func() { _ = dir.Close() }()
 
-	files, err := dir.Readdirnames(-1)
+	return &showSnapshot{
+		Fingerprint: show.Fingerprint,
+		ImdbID:      show.ImdbID,
+		Path:        show.Path,
+		Show:        show.Show,
+		Seasons:     seasons,
+	}, nil
+}
+
+func (l *Library) scanShowDir(imdbID, showDir string, prev map[string]*seasonSnapshot, log *logrus.Entry) (map[string]*seasonSnapshot, error) {
+	entries, err := os.ReadDir(showDir)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("failed to read show dir %w", err)
 	}
 
-	for _, file := range files {
-		if !strings.Contains(file, "Season") {
+	// Any directory of the show may hold episodes, their season is read from
+	// their NFO
+	seasons := map[string]*seasonSnapshot{}
+	for _, entry := range entries {
+		file := entry.Name()
+		if !entry.IsDir() || strings.HasPrefix(file, ".") {
 			continue
 		}
 
 		seasonDir := filepath.Join(showDir, file)
-		if err := l.buildFromShowSeasonDir(imdbID, seasonDir, log); err != nil {
+		fp, err := fingerprint(seasonDir)
+		if err != nil {
 			log.WithField("path", seasonDir).Error(err)
 			continue
 		}
+
+		if s, ok := prev[file]; ok && s.Fingerprint == fp {
+			seasons[file] = s
+			continue
+		}
+
+		s, err := l.buildFromShowSeasonDir(imdbID, seasonDir, log)
+		if err != nil {
+			log.WithField("path", seasonDir).Error(err)
+			continue
+		}
+
+		s.Fingerprint = fp
+		seasons[file] = s
 	}
 
-	return nil
+	return seasons, nil
 }
 
-func (l *Library) buildFromShowSeasonDir(imdbID, seasonDir string, log *logrus.Entry) error {
-	dir, err := os.Open(seasonDir)
+func (l *Library) buildFromShowSeasonDir(imdbID, seasonDir string, log *logrus.Entry) (*seasonSnapshot, error) {
+	files, err := readDirNames(seasonDir)
 	if err != nil {
-		return fmt.Errorf("failed to read movie dir %w", err)
+		return nil, fmt.Errorf("failed to read season dir %w", err)
 	}
-	defer func() { _ = dir.Close() }()
 
-	files, err := dir.Readdirnames(-1)
-	if err != nil {
-		return err
+	season := &seasonSnapshot{
+		Path:     seasonDir,
+		Episodes: []*episodeSnapshot{},
 	}
 
 	for _, file := range files {
@@ -207,12 +380,214 @@ func (l *Library) buildFromShowSeasonDir(imdbID, seasonDir string, log *logrus.E
 
 		episode.ShowImdbID = imdbID
 		episode.ShowConfig = l.showConfig
-		err = l.showIndex.Add(episode)
+		season.Episodes = append(season.Episodes, &episodeSnapshot{
+			Path:    episodePath,
+			Season:  episode.Season,
+			Episode: episode.Episode,
+			Entry:   index.NewEpisode(episode),
+		})
+	}
+
+	return season, nil
+}
+
+// replaceMovieIndex replaces the content of the movie index and returns the
+// changes made
+func (l *Library) replaceMovieIndex(snapshots map[string]*movieSnapshot) ChangeCount {
+	movies := make(map[string]*index.Movie, len(snapshots))
+	for _, m := range snapshots {
+		movies[m.ImdbID] = m.Movie
+	}
+
+	changes := ChangeCount{}
+	current := l.movieIndex.Index()
+	for id, m := range movies {
+		changes.add(diffMovie(current[id], m))
+	}
+
+	for id, m := range current {
+		if _, ok := movies[id]; !ok {
+			changes.add(diffMovie(m, nil))
+		}
+	}
+
+	l.movieIndex.Replace(movies)
+	return changes
+}
+
+// replaceShowIndex replaces the content of the show index and returns the
+// changes made on the shows and the episodes
+func (l *Library) replaceShowIndex(snapshots map[string]*showSnapshot) (ChangeCount, ChangeCount) {
+	shows := make(map[string]*index.Show, len(snapshots))
+	for _, s := range snapshots {
+		// Only the shows with episodes are indexed
+		if show := s.indexedShow(); show != nil {
+			shows[s.ImdbID] = show
+		}
+	}
+
+	showChanges, episodeChanges := ChangeCount{}, ChangeCount{}
+	countEpisode := func(_, _ int, c changeType) {
+		episodeChanges.add(c)
+	}
+
+	current := l.showIndex.Index()
+	for id, s := range shows {
+		showChanges.add(diffShow(current[id], s, countEpisode))
+	}
+
+	for id, s := range current {
+		if _, ok := shows[id]; !ok {
+			showChanges.add(diffShow(s, nil, countEpisode))
+		}
+	}
+
+	l.showIndex.Replace(shows)
+	return showChanges, episodeChanges
+}
+
+// LoadIndex loads the index saved on disk, the directories left untouched
+// since the index was saved won't be parsed during the next refresh
+func (l *Library) LoadIndex(log *logrus.Entry) error {
+	l.indexMu.Lock()
+	defer l.indexMu.Unlock()
+
+	s, err := l.loadIndex()
+	if err != nil {
+		return err
+	}
+
+	l.snapshot = s
+	l.replaceMovieIndex(s.Movies)
+	l.replaceShowIndex(s.Shows)
+
+	log.WithFields(logrus.Fields{
+		"movies": len(l.movieIndex.Index()),
+		"shows":  len(l.showIndex.Index()),
+	}).Info("library index loaded")
+
+	return nil
+}
+
+// diffMovie returns the change between two versions of an indexed movie, nil
+// meaning that the movie is not indexed
+func diffMovie(prev, next *index.Movie) changeType {
+	switch {
+	case prev == nil && next == nil:
+		return changeNone
+	case prev == nil:
+		return changeAdded
+	case next == nil:
+		return changeRemoved
+	case prev.Path != next.Path || !sameJSON(prev, next):
+		return changeChanged
+	default:
+		return changeNone
+	}
+}
+
+// diffEpisode returns the change between two versions of an indexed episode,
+// nil meaning that the episode is not indexed
+func diffEpisode(prev, next *index.Episode) changeType {
+	switch {
+	case prev == nil && next == nil:
+		return changeNone
+	case prev == nil:
+		return changeAdded
+	case next == nil:
+		return changeRemoved
+	case prev.Path != next.Path || !sameJSON(prev, next):
+		return changeChanged
+	default:
+		return changeNone
+	}
+}
+
+// diffShow returns the change between two versions of an indexed show, nil
+// meaning that the show is not indexed, fn is called with the change of each
+// episode
+func diffShow(prev, next *index.Show, fn func(season, episode int, c changeType)) changeType {
+	episode := func(s *index.Show, season, episode int) *index.Episode {
+		if s == nil || s.Seasons[season] == nil {
+			return nil
+		}
+		return s.Seasons[season].Episodes[episode]
+	}
+
+	for _, s := range []*index.Show{prev, next} {
+		if s == nil {
+			continue
+		}
+
+		for sNum, season := range s.Seasons {
+			for eNum := range season.Episodes {
+				// The episodes found in both versions are only compared once
+				if s == next && episode(prev, sNum, eNum) != nil {
+					continue
+				}
+
+				c := diffEpisode(episode(prev, sNum, eNum), episode(next, sNum, eNum))
+				if c != changeNone {
+					fn(sNum, eNum, c)
+				}
+			}
+		}
+	}
+
+	switch {
+	case prev == nil && next == nil:
+		return changeNone
+	case prev == nil:
+		return changeAdded
+	case next == nil:
+		return changeRemoved
+	case prev.Path != next.Path || !sameJSON(prev, next):
+		return changeChanged
+	default:
+		return changeNone
+	}
+}
+
+// sameJSON returns true if both values have the same JSON representation
+func sameJSON(a, b any) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	jb, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(ja, jb)
+}
+
+// largestFile returns the path of the largest file
+func largestFile(paths []string) string {
+	var largest string
+	var size int64 = -1
+	for _, p := range paths {
+		info, err := os.Stat(p)
 		if err != nil {
-			log.Errorf("library: failed to add episode to the library: %q", err)
 			continue
 		}
+
+		if info.Size() > size {
+			largest, size = p, info.Size()
+		}
 	}
 
-	return nil
+	return largest
+}
+
+// readDirNames returns the names of the entries of a directory
+func readDirNames(path string) ([]string, error) {
+	dir, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = dir.Close() }()
+
+	return dir.Readdirnames(-1)
 }
@@ -0,0 +1,240 @@
+package library
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/configuration"
+)
+
+func TestRefreshIndex(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	m, err := lib.mockMovie("movieTest.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	show, err := lib.mockShow()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	e, err := lib.mockEpisode(show, "episodeTest.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := lib.Add(m, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the movie: %q", err)
+	}
+
+	if err := lib.Add(e, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the episode: %q", err)
+	}
+
+	// The videos added are already indexed
+	changes, err := lib.RefreshIndex(mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expected := &IndexChanges{}
+	if !reflect.DeepEqual(expected, changes) {
+		t.Fatalf("invalid changes, expected %+v got %+v", expected, changes)
+	}
+
+	// Load the saved index in a new library
+	loaded := New(&configuration.Config{
+		Library: lib.LibraryConfig,
+		Movie:   lib.movieConfig,
+		Show:    lib.showConfig,
+		File:    lib.fileConfig,
+	})
+
+	if err := loaded.LoadIndex(mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if !reflect.DeepEqual(lib.MovieIDs(), loaded.MovieIDs()) {
+		t.Fatalf("invalid movie ids, expected %+v got %+v", lib.MovieIDs(), loaded.MovieIDs())
+	}
+
+	if !reflect.DeepEqual(lib.ShowIDs(), loaded.ShowIDs()) {
+		t.Fatalf("invalid show ids, expected %+v got %+v", lib.ShowIDs(), loaded.ShowIDs())
+	}
+
+	// Update the episode and remove the movie
+	if err := os.WriteFile(e.Path, []byte("updated"), 0o644); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := os.RemoveAll(lib.getMovieDir(m)); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	changes, err = loaded.RefreshIndex(mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expected = &IndexChanges{
+		Movies:   ChangeCount{Removed: 1},
+		Episodes: ChangeCount{Changed: 1},
+	}
+	if !reflect.DeepEqual(expected, changes) {
+		t.Fatalf("invalid changes, expected %+v got %+v", expected, changes)
+	}
+
+	// A full rebuild finds the same content
+	if err := loaded.RebuildIndex(mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if len(loaded.MovieIDs()) != 0 {
+		t.Fatalf("expected no movie, got %+v", loaded.MovieIDs())
+	}
+
+	if len(loaded.ShowIDs()) != 1 {
+		t.Fatalf("expected one show, got %+v", loaded.ShowIDs())
+	}
+}
+
+func TestRefreshPaths(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	m, err := lib.mockMovie("movieTest.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	show, err := lib.mockShow()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	e, err := lib.mockEpisode(show, "episodeTest.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	// Nothing is done until the index is built
+	if events := lib.RefreshPaths([]string{m.Path}, mockLogEntry); events != nil {
+		t.Fatalf("expected no events, got %+v", events)
+	}
+
+	if err := lib.Add(m, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the movie: %q", err)
+	}
+
+	if err := lib.Add(e, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the episode: %q", err)
+	}
+
+	if _, err := lib.RefreshIndex(mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	// Update the episode and remove the movie
+	if err := os.WriteFile(e.Path, []byte("updated"), 0o644); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := os.RemoveAll(lib.getMovieDir(m)); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	events := lib.RefreshPaths([]string{m.Path, e.Path, lib.tmpDir}, mockLogEntry)
+	got := map[polochon.EventType]*polochon.Event{}
+	for _, e := range events {
+		got[e.Type] = e
+	}
+
+	if len(events) != 2 || got[polochon.EventMovieDeleted] == nil || got[polochon.EventEpisodeUpdated] == nil {
+		t.Fatalf("invalid events: %+v", events)
+	}
+
+	ev := got[polochon.EventEpisodeUpdated]
+	if ev.ImdbID != e.ShowImdbID || ev.Season != e.Season || ev.Episode != e.Episode {
+		t.Fatalf("invalid episode event: %+v", ev)
+	}
+
+	if len(lib.MovieIDs()) != 0 {
+		t.Fatalf("expected no movie, got %+v", lib.MovieIDs())
+	}
+
+	indexed, err := lib.GetIndexedEpisode(e.ShowImdbID, e.Season, e.Episode)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if indexed.Size != int64(len("updated")) {
+		t.Fatalf("expected the episode size to be updated, got %d", indexed.Size)
+	}
+
+	// The changes are already indexed
+	if events := lib.RefreshPaths([]string{e.Path}, mockLogEntry); len(events) != 0 {
+		t.Fatalf("expected no events, got %+v", events)
+	}
+}
+
+func TestRebuildIndexScanError(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	m, err := lib.mockMovie("movieTest.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := lib.Add(m, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the movie: %q", err)
+	}
+
+	if _, err := lib.RefreshIndex(mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	// The movie directory is not available anymore
+	movieDir := lib.MovieDir
+	if err := os.Rename(movieDir, movieDir+".unmounted"); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := lib.RebuildIndex(mockLogEntry); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// The index is kept in memory and on disk
+	if expected := []string{m.ImdbID}; !reflect.DeepEqual(expected, lib.MovieIDs()) {
+		t.Fatalf("expected movie ids %+v, got %+v", expected, lib.MovieIDs())
+	}
+
+	loaded := New(&configuration.Config{
+		Library: lib.LibraryConfig,
+		Movie:   lib.movieConfig,
+		Show:    lib.showConfig,
+		File:    lib.fileConfig,
+	})
+
+	if err := loaded.LoadIndex(mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if expected := []string{m.ImdbID}; !reflect.DeepEqual(expected, loaded.MovieIDs()) {
+		t.Fatalf("expected saved movie ids %+v, got %+v", expected, loaded.MovieIDs())
+	}
+}
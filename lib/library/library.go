@@ -2,6 +2,7 @@ package library
 
 import (
 	"errors"
+	"sync"
 
 	polochon "github.com/odwrtw/polochon/lib"
 	"github.com/odwrtw/polochon/lib/configuration"
@@ -16,6 +17,8 @@ var (
 	ErrMissingMovieImageURL       = errors.New("library: missing movie images URL")
 	ErrMissingShowImageURL        = errors.New("library: missing URL to download show images")
 	ErrMissingShowEpisodeFilePath = errors.New("library: missing file path")
+	ErrMovieDirConflict           = errors.New("library: movie directory used by another movie")
+	ErrShowDirConflict            = errors.New("library: show directory used by another show")
 )
 
 // Library represents a collection of videos
@@ -28,6 +31,14 @@ type Library struct {
 	fileConfig        polochon.FileConfig
 	downloaderConfig  configuration.DownloaderConfig
 	SubtitleLanguages []polochon.Language
+
+	// indexMu serializes the index refreshes
+	indexMu  sync.Mutex
+	snapshot *indexSnapshot
+
+	// calendarMu protects the show calendars cache
+	calendarMu sync.Mutex
+	calendars  map[string]*cachedCalendar
 }
 
 // New returns a list of videos
@@ -56,13 +67,39 @@ func (l *Library) HasVideo(video polochon.Video) (bool, error) {
 	}
 }
 
+// IndexedQuality returns the quality of the video stored in the library
+func (l *Library) IndexedQuality(video polochon.Video) (polochon.Quality, error) {
+	switch v := video.(type) {
+	case *polochon.Movie:
+		m, err := l.GetIndexedMovie(v.ImdbID)
+		if err != nil {
+			return "", err
+		}
+		return m.Quality, nil
+	case *polochon.ShowEpisode:
+		e, err := l.GetIndexedEpisode(v.ShowImdbID, v.Season, v.Episode)
+		if err != nil {
+			return "", err
+		}
+		return e.Quality, nil
+	default:
+		return "", ErrInvalidIndexVideoType
+	}
+}
+
 // Add adds a video in the library
 func (l *Library) Add(video polochon.Video, log *logrus.Entry) error {
+	return l.Import(video, configuration.ImportMove, log)
+}
+
+// Import adds a video to the library, the video file is moved, copied or
+// linked according to the import mode
+func (l *Library) Import(video polochon.Video, mode configuration.ImportMode, log *logrus.Entry) error {
 	switch v := video.(type) {
 	case *polochon.Movie:
-		return l.AddMovie(v, log)
+		return l.importMovie(v, mode, log)
 	case *polochon.ShowEpisode:
-		return l.AddShowEpisode(v, log)
+		return l.importShowEpisode(v, mode, log)
 	default:
 		return ErrInvalidIndexVideoType
 	}
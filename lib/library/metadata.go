@@ -0,0 +1,186 @@
+package library
+
+import (
+	"maps"
+	"path/filepath"
+	"slices"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/sirupsen/logrus"
+)
+
+// RefreshMovieMetadata fetches the details of a movie again, its NFO is
+// rewritten and its missing images are downloaded. Only the given fields are
+// updated if any. The events describing the changes made to the index are
+// returned.
+func (l *Library) RefreshMovieMetadata(id string, fields []string, log *logrus.Entry) ([]*polochon.Event, error) {
+	if err := polochon.ValidateDetailFields(fields); err != nil {
+		return nil, err
+	}
+
+	log = log.WithFields(logrus.Fields{"type": "movie", "imdb_id": id})
+
+	movie, err := l.GetMovie(id)
+	if err != nil {
+		return nil, err
+	}
+
+	fresh := polochon.NewMovie(l.movieConfig)
+	fresh.ImdbID = movie.ImdbID
+	fresh.TmdbID = movie.TmdbID
+	if err := polochon.GetDetails(fresh, log); err != nil {
+		return nil, err
+	}
+
+	if err := polochon.UpdateDetails(movie, fresh, fields); err != nil {
+		return nil, err
+	}
+
+	log.Debug("writing movie NFO")
+	if err := writeNFOFile(movieNFOPath(movie.Path), movie); err != nil {
+		return nil, err
+	}
+
+	downloadMissingImages(map[string]string{
+		movie.MovieFanartPath(): fresh.Fanart,
+		movie.MovieThumbPath():  fresh.Thumb,
+	}, log)
+
+	return l.RefreshPaths([]string{movie.Path}, log), nil
+}
+
+// RefreshShowMetadata fetches the details of a show and its episodes again,
+// their NFOs are rewritten and the missing show images are downloaded. Only
+// the given fields are updated if any. The events describing the changes made
+// to the index are returned.
+func (l *Library) RefreshShowMetadata(id string, fields []string, log *logrus.Entry) ([]*polochon.Event, error) {
+	if err := polochon.ValidateDetailFields(fields); err != nil {
+		return nil, err
+	}
+
+	log = log.WithFields(logrus.Fields{"type": "show", "imdb_id": id})
+
+	indexed, err := l.showIndex.IndexedShow(id)
+	if err != nil {
+		return nil, err
+	}
+
+	show, err := l.GetShow(id)
+	if err != nil {
+		return nil, err
+	}
+
+	fresh := polochon.NewShow(l.showConfig)
+	fresh.ImdbID = id
+	fresh.TvdbID = show.TvdbID
+	if err := polochon.GetDetails(fresh, log); err != nil {
+		return nil, err
+	}
+
+	if err := polochon.UpdateDetails(show, fresh, fields); err != nil {
+		return nil, err
+	}
+
+	nfoPath := l.showNFOPath(indexed.Path)
+	log.Debug("writing show NFO")
+	if err := writeNFOFile(nfoPath, show); err != nil {
+		return nil, err
+	}
+
+	downloadMissingImages(map[string]string{
+		filepath.Join(indexed.Path, "fanart.jpg"): fresh.Fanart,
+		filepath.Join(indexed.Path, "poster.jpg"): fresh.Poster,
+		filepath.Join(indexed.Path, "banner.jpg"): fresh.Banner,
+	}, log)
+
+	// The episodes details usually come with the show details
+	type key struct{ season, episode int }
+	freshEpisodes := map[key]*polochon.ShowEpisode{}
+	for _, e := range fresh.Episodes {
+		freshEpisodes[key{e.Season, e.Episode}] = e
+	}
+
+	paths := []string{nfoPath}
+	for sNum, season := range indexed.Seasons {
+		for eNum, e := range season.Episodes {
+			elog := log.WithFields(logrus.Fields{"season": sNum, "episode": eNum})
+
+			ep, err := l.newEpisodeFromPath(e.Path)
+			if err != nil {
+				elog.Warnf("failed to read episode: %s", err)
+				continue
+			}
+
+			freshEp, ok := freshEpisodes[key{sNum, eNum}]
+			if !ok {
+				freshEp = polochon.NewShowEpisode(l.showConfig)
+				freshEp.ShowImdbID = id
+				freshEp.ShowTvdbID = fresh.TvdbID
+				freshEp.Season = sNum
+				freshEp.Episode = eNum
+				freshEp.Show = fresh
+				if err := polochon.GetDetails(freshEp, elog); err != nil {
+					elog.Warnf("failed to get episode details: %s", err)
+					continue
+				}
+			}
+
+			if err := polochon.UpdateDetails(ep, freshEp, fields); err != nil {
+				return nil, err
+			}
+
+			if err := writeNFOFile(ep.NfoPath(), ep); err != nil {
+				elog.Errorf("failed to write episode NFO: %s", err)
+				continue
+			}
+
+			paths = append(paths, ep.Path)
+		}
+	}
+
+	return l.RefreshPaths(paths, log), nil
+}
+
+// RefreshMetadata refreshes the metadata of every movie and show of the
+// library, the events describing the changes made to the index are returned
+func (l *Library) RefreshMetadata(fields []string, log *logrus.Entry) ([]*polochon.Event, error) {
+	if err := polochon.ValidateDetailFields(fields); err != nil {
+		return nil, err
+	}
+
+	events := []*polochon.Event{}
+	for _, id := range l.MovieIDs() {
+		e, err := l.RefreshMovieMetadata(id, fields, log)
+		if err != nil {
+			log.WithField("imdb_id", id).Errorf("failed to refresh movie metadata: %s", err)
+			continue
+		}
+		events = append(events, e...)
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(l.ShowIDs())) {
+		e, err := l.RefreshShowMetadata(id, fields, log)
+		if err != nil {
+			log.WithField("imdb_id", id).Errorf("failed to refresh show metadata: %s", err)
+			continue
+		}
+		events = append(events, e...)
+	}
+
+	return events, nil
+}
+
+// downloadMissingImages downloads the images not found on disk, the images are
+// indexed by path
+func downloadMissingImages(images map[string]string, log *logrus.Entry) {
+	for path, url := range images {
+		if url == "" || exists(path) {
+			continue
+		}
+
+		log.Debug("downloading " + filepath.Base(path))
+		if err := download(url, path); err != nil {
+			log.Warnf("failed to download %s: %s", filepath.Base(path), err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package library
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/sirupsen/logrus"
+)
+
+// imageDetailer is a detailer serving its images from a test server
+type imageDetailer struct {
+	polochon.Detailer
+	url string
+}
+
+func (d *imageDetailer) GetDetails(i any, log *logrus.Entry) error {
+	if err := d.Detailer.GetDetails(i, log); err != nil {
+		return err
+	}
+
+	if m, ok := i.(*polochon.Movie); ok {
+		m.Fanart = d.url
+		m.Thumb = d.url
+	}
+
+	return nil
+}
+
+func TestRefreshMovieMetadata(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	m, err := lib.mockMovie("movieTest.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := lib.Add(m, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the movie: %q", err)
+	}
+
+	if err := lib.RebuildIndex(mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	lib.movieConfig.Detailers = []polochon.Detailer{
+		&imageDetailer{Detailer: lib.movieConfig.Detailers[0], url: lib.httpServer.URL},
+	}
+
+	// Edit the movie by hand and remove its fanart
+	m.Title = "Hand edited title"
+	m.Plot = "Old plot"
+	m.Rating = 1
+	if err := writeNFOFile(m.NfoPath(), m); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := os.Remove(m.MovieFanartPath()); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if _, err := lib.RefreshMovieMetadata(m.ImdbID, []string{"yo"}, mockLogEntry); !errors.Is(err, polochon.ErrUnknownDetailField) {
+		t.Fatalf("expected error %q, got %q", polochon.ErrUnknownDetailField, err)
+	}
+
+	events, err := lib.RefreshMovieMetadata(m.ImdbID, []string{"plot", "rating"}, mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if len(events) != 1 || events[0].Type != polochon.EventMovieUpdated {
+		t.Errorf("expected a movie updated event, got %+v", events)
+	}
+
+	got, err := lib.GetMovie(m.ImdbID)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if got.Title != "Hand edited title" {
+		t.Errorf("expected the title to be kept, got %q", got.Title)
+	}
+
+	expectedPlot := "This is the plot of the movie Movie tt12345"
+	if got.Plot != expectedPlot || got.Rating != 5 {
+		t.Errorf("expected the plot and rating to be updated, got %q and %v", got.Plot, got.Rating)
+	}
+
+	if !exists(m.MovieFanartPath()) {
+		t.Error("the fanart should be downloaded again")
+	}
+
+	indexed, err := lib.GetIndexedMovie(m.ImdbID)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if indexed.Fanart == nil {
+		t.Error("the fanart should be indexed")
+	}
+}
+
+func TestRefreshShowMetadata(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	show, err := lib.mockShow()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	ep, err := lib.mockEpisode(show, "episodeTest.mp4")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := lib.Add(ep, mockLogEntry); err != nil {
+		t.Fatalf("failed to add the episode: %q", err)
+	}
+
+	// Edit the episode by hand
+	ep.Title = "Hand edited title"
+	ep.Plot = "Old plot"
+	if err := writeNFOFile(ep.NfoPath(), ep); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if _, err := lib.RefreshShowMetadata(ep.ShowImdbID, []string{"plot"}, mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	got, err := lib.GetEpisode(ep.ShowImdbID, ep.Season, ep.Episode)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if got.Title != "Hand edited title" {
+		t.Errorf("expected the title to be kept, got %q", got.Title)
+	}
+
+	if got.Plot == "Old plot" {
+		t.Error("expected the plot to be updated")
+	}
+}
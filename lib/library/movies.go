@@ -5,9 +5,9 @@ import (
 	"os"
 	"path"
 	"path/filepath"
-	"strings"
 
 	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/configuration"
 	index "github.com/odwrtw/polochon/lib/media_index"
 	"github.com/sirupsen/logrus"
 )
@@ -27,18 +27,13 @@ func (l *Library) HasMovie(imdbID string) (bool, error) {
 	return l.movieIndex.Has(imdbID)
 }
 
-func (l *Library) getMovieDir(movie *polochon.Movie) string {
-	title := movie.Title
-	if movie.Year != 0 {
-		title += fmt.Sprintf(" (%d)", movie.Year)
-	}
-	title = strings.ReplaceAll(title, "/", "-")
-
-	return filepath.Join(l.MovieDir, title)
-}
-
 // AddMovie adds a movie to the store
 func (l *Library) AddMovie(movie *polochon.Movie, logEntry *logrus.Entry) error {
+	return l.importMovie(movie, configuration.ImportMove, logEntry)
+}
+
+// importMovie adds a movie to the store using the import mode
+func (l *Library) importMovie(movie *polochon.Movie, mode configuration.ImportMode, logEntry *logrus.Entry) error {
 	log := logEntry.WithFields(logrus.Fields{
 		"type":    "movie",
 		"title":   movie.Title,
@@ -57,20 +52,27 @@ func (l *Library) AddMovie(movie *polochon.Movie, logEntry *logrus.Entry) error
 		return err
 	}
 
+	// The old movie is only removed once the new one is in place
+	var oldMovie *polochon.Movie
 	if ok {
 		// Get the old movie path from the index
-		oldMovie, err := l.GetMovie(movie.ImdbID)
+		oldMovie, err = l.GetMovie(movie.ImdbID)
 		if err != nil {
 			return err
 		}
-
-		// Delete it
-		if err := l.DeleteMovie(oldMovie, log); err != nil {
-			return err
-		}
 	}
 
+	// Keep the directory of the movie being replaced
 	storePath := l.getMovieDir(movie)
+	if oldMovie != nil {
+		storePath = filepath.Dir(oldMovie.Path)
+	}
+
+	// Another movie may be named the same way, e.g. a remake with a naming
+	// template without the year
+	if id, ok := l.movieIndex.InDir(storePath); ok && id != movie.ImdbID {
+		return fmt.Errorf("%w: %s", ErrMovieDirConflict, id)
+	}
 
 	// If the movie already in the right dir there is nothing to do
 	if path.Dir(movie.Path) == storePath {
@@ -78,8 +80,8 @@ func (l *Library) AddMovie(movie *polochon.Movie, logEntry *logrus.Entry) error
 		return nil
 	}
 
-	// Remove movie dir if it exisits
-	if ok := exists(storePath); ok {
+	// Remove movie dir if it exisits and does not belong to the old movie
+	if ok := exists(storePath); ok && oldMovie == nil {
 		log.Debug("movie folder exists, remove it")
 		if err := os.RemoveAll(storePath); err != nil {
 			return err
@@ -87,30 +89,40 @@ func (l *Library) AddMovie(movie *polochon.Movie, logEntry *logrus.Entry) error
 	}
 
 	// Create the folder
-	if err := os.Mkdir(storePath, os.ModePerm); err != nil {
-		return err
+	if !exists(storePath) {
+		if err := os.Mkdir(storePath, os.ModePerm); err != nil {
+			return err
+		}
 	}
 
 	// Move the movie into the folder
-	newPath := filepath.Join(storePath, path.Base(movie.Path))
+	newPath := filepath.Join(storePath, l.getMovieFileName(movie))
 
 	// Save the old path
 	oldPath := movie.Path
 
 	log.WithFields(logrus.Fields{
-		"old_path": movie.Path,
-		"new_path": newPath,
-	}).Debugf("moving movie file")
-	if err := MoveFile(movie.Path, newPath); err != nil {
+		"old_path":    movie.Path,
+		"new_path":    newPath,
+		"import_mode": mode,
+	}).Debugf("importing movie file")
+	if err := importFile(movie.Path, newPath, mode); err != nil {
 		return err
 	}
 
 	// Set the new movie path
 	movie.Path = newPath
 
+	// The new movie is in place, the old one can be removed
+	if oldMovie != nil {
+		if err := l.removeReplacedMovie(oldMovie, movie, log); err != nil {
+			return err
+		}
+	}
+
 	// Create a symlink between the new and the old location
-	// Only if the downloader is enabled
-	if l.downloaderConfig.Enabled {
+	// Only if the downloader is enabled and the file has been moved
+	if l.downloaderConfig.Enabled && !mode.KeepsSource() {
 		log.Debugf("creating symlink with the old path")
 		if err := os.Symlink(movie.Path, oldPath); err != nil {
 			log.Warnf("error while making symlink between %s and %s : %+v", oldPath, movie.Path, err)
@@ -162,6 +174,22 @@ func (l *Library) AddMovie(movie *polochon.Movie, logEntry *logrus.Entry) error
 	return nil
 }
 
+// removeReplacedMovie removes the files of a movie replaced by a new one
+func (l *Library) removeReplacedMovie(old, movie *polochon.Movie, log *logrus.Entry) error {
+	oldDir := filepath.Dir(old.Path)
+	if oldDir != filepath.Dir(movie.Path) {
+		log.WithField("path", oldDir).Info("removing replaced movie folder")
+		return os.RemoveAll(oldDir)
+	}
+
+	if old.Path == movie.Path {
+		return nil
+	}
+
+	log.WithField("path", old.Path).Info("removing replaced movie file")
+	return removeVideoFiles(old)
+}
+
 // GetMovie returns the video by its imdb ID
 func (l *Library) GetMovie(imdbID string) (*polochon.Movie, error) {
 	movieIndex, err := l.movieIndex.Movie(imdbID)
@@ -189,7 +217,7 @@ func (l *Library) newMovieFromPath(path string) (*polochon.Movie, error) {
 	file := polochon.NewFile(path)
 	m := polochon.NewMovieFromFile(l.movieConfig, *file)
 
-	if err := readNFOFile(file.NfoPath(), m); err != nil {
+	if err := readNFOFile(movieNFOPath(path), m); err != nil {
 		return nil, err
 	}
 
@@ -197,6 +225,21 @@ func (l *Library) newMovieFromPath(path string) (*polochon.Movie, error) {
 	return m, nil
 }
 
+// movieNFOPath returns the path of the NFO of a movie, the NFO named after the
+// video comes first and the directory wide movie.nfo is used otherwise
+func movieNFOPath(path string) string {
+	nfoPath := polochon.NewFile(path).NfoPath()
+	if exists(nfoPath) {
+		return nfoPath
+	}
+
+	if dirNFO := filepath.Join(filepath.Dir(path), movieDirNFOName); exists(dirNFO) {
+		return dirNFO
+	}
+
+	return nfoPath
+}
+
 // GetIndexedMovie returns a Movie index from its id
 func (l *Library) GetIndexedMovie(id string) (*index.Movie, error) {
 	m, err := l.movieIndex.Movie(id)
@@ -0,0 +1,123 @@
+package library
+
+import (
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/configuration"
+)
+
+// defaultNaming holds the templates used when none are configured
+var defaultNaming = configuration.NamingConfig{}.WithDefaults()
+
+func movieNamingData(m *polochon.Movie) configuration.NamingData {
+	return configuration.NamingData{
+		Title:        m.Title,
+		Year:         m.Year,
+		ImdbID:       m.ImdbID,
+		TmdbID:       m.TmdbID,
+		Quality:      string(m.Quality),
+		ReleaseGroup: m.ReleaseGroup,
+		VideoCodec:   m.VideoCodec,
+		AudioCodec:   m.AudioCodec,
+	}
+}
+
+func episodeNamingData(ep *polochon.ShowEpisode, show *polochon.Show) configuration.NamingData {
+	data := configuration.NamingData{
+		Title:          ep.Title,
+		ImdbID:         ep.ShowImdbID,
+		TvdbID:         ep.ShowTvdbID,
+		ShowTitle:      ep.ShowTitle,
+		Season:         ep.Season,
+		Episode:        ep.Episode,
+		AbsoluteNumber: ep.AbsoluteNumber,
+		EpisodeImdbID:  ep.EpisodeImdbID,
+		Quality:        string(ep.Quality),
+		ReleaseGroup:   ep.ReleaseGroup,
+		VideoCodec:     ep.VideoCodec,
+		AudioCodec:     ep.AudioCodec,
+	}
+
+	if ep.IsMultiEpisode() {
+		data.LastEpisode = ep.LastEpisode
+	}
+
+	if show != nil {
+		data.Year = show.Year
+		if data.ShowTitle == "" {
+			data.ShowTitle = show.Title
+		}
+	}
+
+	return data
+}
+
+// renderName executes the template to get a file or directory name, the
+// default template is used if the template is not set or fails to give a
+// name
+func renderName(tmpl, def *template.Template, data configuration.NamingData) string {
+	for _, t := range []*template.Template{tmpl, def} {
+		if t == nil {
+			continue
+		}
+
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			continue
+		}
+
+		name := strings.TrimSpace(strings.ReplaceAll(b.String(), "/", "-"))
+		if name != "" && name != "." && name != ".." {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// renderFileName returns the name of a video file, the original name is kept
+// if no template is set
+func renderFileName(tmpl *template.Template, data configuration.NamingData, path string) string {
+	name := filepath.Base(path)
+	if tmpl == nil {
+		return name
+	}
+
+	if n := renderName(tmpl, nil, data); n != "" {
+		return n + filepath.Ext(path)
+	}
+
+	return name
+}
+
+func (l *Library) getMovieDir(movie *polochon.Movie) string {
+	name := renderName(l.Naming.MovieDir, defaultNaming.MovieDir, movieNamingData(movie))
+	return filepath.Join(l.MovieDir, name)
+}
+
+func (l *Library) getMovieFileName(movie *polochon.Movie) string {
+	return renderFileName(l.Naming.MovieFile, movieNamingData(movie), movie.Path)
+}
+
+func (l *Library) getShowDir(ep *polochon.ShowEpisode) string {
+	name := renderName(l.Naming.ShowDir, defaultNaming.ShowDir, episodeNamingData(ep, ep.Show))
+	return filepath.Join(l.ShowDir, name)
+}
+
+// getSeasonDir returns the season directory of an episode, the directory of
+// the season already in the library is used if any
+func (l *Library) getSeasonDir(ep *polochon.ShowEpisode, showDir string) string {
+	if p, err := l.showIndex.SeasonPath(ep.ShowImdbID, ep.Season); err == nil {
+		return p
+	}
+
+	name := renderName(l.Naming.SeasonDir, defaultNaming.SeasonDir, episodeNamingData(ep, ep.Show))
+	return filepath.Join(showDir, name)
+}
+
+func (l *Library) getEpisodeFileName(ep *polochon.ShowEpisode) string {
+	return renderFileName(l.Naming.EpisodeFile, episodeNamingData(ep, ep.Show), ep.Path)
+}
@@ -0,0 +1,140 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/configuration"
+)
+
+func TestRenderFileName(t *testing.T) {
+	data := configuration.NamingData{
+		ShowTitle: "Show",
+		Title:     "Pilot",
+		Season:    1,
+		Episode:   2,
+		Quality:   "720p",
+	}
+
+	tt := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "no template",
+			expected: "show.s01e02.mkv",
+		},
+		{
+			name:     "template",
+			template: `{{.ShowTitle}} - S{{printf "%02d" .Season}}E{{printf "%02d" .Episode}} - {{.Title}} [{{.Quality}}]`,
+			expected: "Show - S01E02 - Pilot [720p].mkv",
+		},
+		{
+			name:     "path separators",
+			template: `{{.ShowTitle}}/{{.Title}}`,
+			expected: "Show-Pilot.mkv",
+		},
+		{
+			name:     "empty name",
+			template: `{{.ReleaseGroup}}`,
+			expected: "show.s01e02.mkv",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var tmpl *template.Template
+			if tc.template != "" {
+				tmpl = template.Must(template.New(tc.name).Parse(tc.template))
+			}
+
+			got := renderFileName(tmpl, data, "/downloads/show.s01e02.mkv")
+			if got != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestIndexAdoptedLibrary(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	// A movie described by a directory wide NFO
+	movieDir := filepath.Join(lib.MovieDir, "The Movie [1080p]")
+	movie := &polochon.Movie{ImdbID: "tt00001", Title: "The Movie"}
+	// An episode in a season directory named freely
+	seasonDir := filepath.Join(lib.ShowDir, "The Show (2010)", "S01")
+	show := &polochon.Show{ImdbID: "tt00002", Title: "The Show"}
+	episode := &polochon.ShowEpisode{ShowImdbID: "tt00002", Season: 1, Episode: 3}
+
+	for _, dir := range []string{movieDir, seasonDir} {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+	}
+
+	for path, content := range map[string]any{
+		filepath.Join(movieDir, "movie.nfo"):                        movie,
+		filepath.Join(lib.ShowDir, "The Show (2010)", "tvshow.nfo"): show,
+		filepath.Join(seasonDir, "the.show.103.nfo"):                episode,
+	} {
+		if err := writeNFOFile(path, content); err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+	}
+
+	for _, path := range []string{
+		filepath.Join(movieDir, "trailer.mp4"),
+		filepath.Join(seasonDir, "the.show.103.mp4"),
+	} {
+		if err := os.WriteFile(path, []byte("video"), 0o644); err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+	}
+
+	// The largest video is the movie
+	moviePath := filepath.Join(movieDir, "the.movie.mp4")
+	if err := os.WriteFile(moviePath, []byte("the movie video"), 0o644); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := lib.RebuildIndex(mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	m, err := lib.GetIndexedMovie(movie.ImdbID)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if m.Path != moviePath {
+		t.Errorf("expected movie path %q, got %q", moviePath, m.Path)
+	}
+
+	if _, err := lib.GetMovie(movie.ImdbID); err != nil {
+		t.Errorf("expected no error, got %q", err)
+	}
+
+	has, err := lib.HasShowEpisode(episode.ShowImdbID, episode.Season, episode.Episode)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if !has {
+		t.Fatal("the episode should be in the index")
+	}
+
+	// New episodes of the show go to the existing directories
+	ep := &polochon.ShowEpisode{ShowImdbID: "tt00002", ShowTitle: "The Show", Season: 1, Episode: 4}
+	if got := lib.getSeasonDir(ep, lib.getShowDir(ep)); got != seasonDir {
+		t.Errorf("expected season dir %q, got %q", seasonDir, got)
+	}
+}
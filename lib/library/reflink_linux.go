@@ -0,0 +1,30 @@
+package library
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// reflinkFile creates a copy on write clone of a file, it fails if the
+// filesystem does not support it
+func reflinkFile(from, to string) error {
+	source, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+
+	destination, err := os.Create(to)
+	if err != nil {
+		return err
+	}
+
+	if err := unix.IoctlFileClone(int(destination.Fd()), int(source.Fd())); err != nil {
+		_ = destination.Close()
+		_ = os.Remove(to)
+		return err
+	}
+
+	return destination.Close()
+}
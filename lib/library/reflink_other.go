@@ -0,0 +1,10 @@
+//go:build !linux
+
+package library
+
+import "errors"
+
+// reflinkFile is only supported on linux
+func reflinkFile(from, to string) error {
+	return errors.New("library: reflinks are not supported")
+}
@@ -0,0 +1,197 @@
+package library
+
+import (
+	"path/filepath"
+	"strings"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	index "github.com/odwrtw/polochon/lib/media_index"
+	"github.com/sirupsen/logrus"
+)
+
+// RefreshPaths refreshes the index entries of the library directories
+// containing the paths, the events describing the changes made to the index
+// are returned
+func (l *Library) RefreshPaths(paths []string, log *logrus.Entry) []*polochon.Event {
+	l.indexMu.Lock()
+	defer l.indexMu.Unlock()
+
+	// The index is not built yet, it will include the changes
+	if l.snapshot == nil {
+		return nil
+	}
+
+	movieDirs := map[string]struct{}{}
+	showDirs := map[string]struct{}{}
+	for _, p := range paths {
+		if d, ok := libraryDir(l.MovieDir, p); ok {
+			movieDirs[d] = struct{}{}
+		}
+
+		if d, ok := libraryDir(l.ShowDir, p); ok {
+			showDirs[d] = struct{}{}
+		}
+	}
+
+	if len(movieDirs) == 0 && len(showDirs) == 0 {
+		return nil
+	}
+
+	events := l.refreshMovieDirs(movieDirs, log)
+	events = append(events, l.refreshShowDirs(showDirs, log)...)
+
+	if err := l.saveIndex(l.snapshot); err != nil {
+		log.WithField("function", "save_index").Error(err)
+	}
+
+	return events
+}
+
+// libraryDir returns the name of the directory of root containing the path
+func libraryDir(root, path string) (string, bool) {
+	if root == "" {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+
+	d, _, _ := strings.Cut(rel, string(filepath.Separator))
+	return d, true
+}
+
+func (l *Library) refreshMovieDirs(dirs map[string]struct{}, log *logrus.Entry) []*polochon.Event {
+	ids := map[string]struct{}{}
+	for d := range dirs {
+		prev := l.snapshot.Movies[d]
+		if prev != nil {
+			ids[prev.ImdbID] = struct{}{}
+		}
+
+		m, err := l.scanMovieDir(d, prev)
+		switch {
+		case err == nil:
+			l.snapshot.Movies[d] = m
+			ids[m.ImdbID] = struct{}{}
+		case err == errNoVideoFile || !exists(filepath.Join(l.MovieDir, d)):
+			delete(l.snapshot.Movies, d)
+		default:
+			// The directory may be being written, keep its previous version
+			log.WithField("dir", d).Warn(err)
+		}
+	}
+
+	events := []*polochon.Event{}
+	for id := range ids {
+		var next *index.Movie
+		for _, m := range l.snapshot.Movies {
+			if m.ImdbID == id {
+				next = m.Movie
+				break
+			}
+		}
+
+		prev, _ := l.movieIndex.Movie(id)
+
+		var t polochon.EventType
+		switch diffMovie(prev, next) {
+		case changeAdded:
+			t = polochon.EventMovieAdded
+			l.movieIndex.Set(id, next)
+		case changeChanged:
+			t = polochon.EventMovieUpdated
+			l.movieIndex.Set(id, next)
+		case changeRemoved:
+			t = polochon.EventMovieDeleted
+			next = prev
+			_ = l.movieIndex.Remove(&polochon.Movie{ImdbID: id}, log)
+		default:
+			continue
+		}
+
+		e := polochon.NewEvent(t)
+		e.ImdbID = id
+		e.Files = []string{next.Filename}
+		events = append(events, e)
+	}
+
+	return events
+}
+
+func (l *Library) refreshShowDirs(dirs map[string]struct{}, log *logrus.Entry) []*polochon.Event {
+	ids := map[string]struct{}{}
+	for d := range dirs {
+		prev := l.snapshot.Shows[d]
+		if prev != nil {
+			ids[prev.ImdbID] = struct{}{}
+		}
+
+		s, err := l.scanShow(d, prev, log)
+		switch {
+		case err == nil:
+			l.snapshot.Shows[d] = s
+			ids[s.ImdbID] = struct{}{}
+		case !exists(filepath.Join(l.ShowDir, d)):
+			delete(l.snapshot.Shows, d)
+		default:
+			// The directory may be being written, keep its previous version
+			log.WithField("dir", d).Warn(err)
+		}
+	}
+
+	events := []*polochon.Event{}
+	for id := range ids {
+		var next *index.Show
+		for _, s := range l.snapshot.Shows {
+			if s.ImdbID == id {
+				next = s.indexedShow()
+				break
+			}
+		}
+
+		prev, _ := l.showIndex.IndexedShow(id)
+
+		episodeEvent := func(season, episode int, c changeType) {
+			t := polochon.EventEpisodeUpdated
+			show := next
+			switch c {
+			case changeAdded:
+				t = polochon.EventEpisodeAdded
+			case changeRemoved:
+				t = polochon.EventEpisodeDeleted
+				show = prev
+			}
+
+			e := polochon.NewEvent(t)
+			e.ImdbID = id
+			e.Season = season
+			e.Episode = episode
+			e.Files = []string{show.Seasons[season].Episodes[episode].Filename}
+			events = append(events, e)
+		}
+
+		switch diffShow(prev, next, episodeEvent) {
+		case changeAdded:
+			l.showIndex.Set(id, next)
+		case changeChanged:
+			l.showIndex.Set(id, next)
+			e := polochon.NewEvent(polochon.EventShowUpdated)
+			e.ImdbID = id
+			events = append(events, e)
+		case changeRemoved:
+			_ = l.showIndex.RemoveShow(&polochon.Show{ImdbID: id}, log)
+			e := polochon.NewEvent(polochon.EventShowDeleted)
+			e.ImdbID = id
+			events = append(events, e)
+		default:
+			// The show itself is unchanged but its episodes may have
+			if next != nil {
+				l.showIndex.Set(id, next)
+			}
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,348 @@
+package library
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrReorganizeConflict is returned when the destination of a move is
+// already used
+var ErrReorganizeConflict = errors.New("library: destination already exists")
+
+// Move represents the move of a video of the library to the path given by
+// the naming templates
+type Move struct {
+	Type    string `json:"type"`
+	ImdbID  string `json:"imdb_id"`
+	Season  int    `json:"season,omitempty"`
+	Episode int    `json:"episode,omitempty"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ReorganizeResult represents the result of a reorganization
+type ReorganizeResult struct {
+	DryRun  bool          `json:"dry_run"`
+	Moves   []*Move       `json:"moves"`
+	Changes *IndexChanges `json:"changes,omitempty"`
+}
+
+// Reorganize moves the videos of the library to the paths given by the naming
+// templates and their current metadata, the files of the videos are moved
+// along with them. Nothing is moved in dry run mode, the planned moves are
+// only returned.
+func (l *Library) Reorganize(dryRun bool, log *logrus.Entry) (*ReorganizeResult, error) {
+	l.indexMu.Lock()
+	defer l.indexMu.Unlock()
+
+	result := &ReorganizeResult{DryRun: dryRun, Moves: []*Move{}}
+
+	movies := l.planMovieMoves(log)
+	shows := l.planShowMoves(log)
+
+	result.Moves = append(result.Moves, movies...)
+	for _, show := range shows {
+		result.Moves = append(result.Moves, show.moves...)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	for _, m := range movies {
+		if m.Error != "" {
+			continue
+		}
+
+		if err := l.moveMovie(m); err != nil {
+			m.Error = err.Error()
+			log.WithField("imdb_id", m.ImdbID).Errorf("failed to move movie: %s", err)
+		}
+	}
+
+	for _, show := range shows {
+		l.moveShow(show, log)
+	}
+
+	changes, err := l.refreshIndexLocked(false, log)
+	if err != nil {
+		return result, err
+	}
+
+	result.Changes = changes
+	return result, nil
+}
+
+// planMovieMoves returns the moves needed to reorganize the movies
+func (l *Library) planMovieMoves(log *logrus.Entry) []*Move {
+	moves := []*Move{}
+	for _, id := range l.movieIndex.IDs() {
+		indexed, err := l.movieIndex.Movie(id)
+		if err != nil {
+			continue
+		}
+
+		m := &Move{Type: "movie", ImdbID: id, From: indexed.Path}
+
+		movie, err := l.newMovieFromPath(indexed.Path)
+		if err != nil {
+			log.WithField("imdb_id", id).Warnf("failed to read movie: %s", err)
+			continue
+		}
+
+		m.To = filepath.Join(l.getMovieDir(movie), l.getMovieFileName(movie))
+		if m.To == m.From {
+			continue
+		}
+
+		if filepath.Dir(m.To) != filepath.Dir(m.From) && exists(filepath.Dir(m.To)) {
+			m.Error = ErrReorganizeConflict.Error()
+		}
+
+		moves = append(moves, m)
+	}
+
+	return moves
+}
+
+// moveMovie moves the movie directory and renames the movie files
+func (l *Library) moveMovie(m *Move) error {
+	from := m.From
+	oldDir, newDir := filepath.Dir(m.From), filepath.Dir(m.To)
+	if oldDir != newDir {
+		if exists(newDir) {
+			return ErrReorganizeConflict
+		}
+
+		if err := os.Rename(oldDir, newDir); err != nil {
+			return err
+		}
+
+		from = filepath.Join(newDir, filepath.Base(m.From))
+	}
+
+	return l.moveVideoFiles(from, m.To)
+}
+
+// showMoves holds the moves of the episodes of a show
+type showMoves struct {
+	imdbID  string
+	from    string
+	to      string
+	seasons map[string]string
+	moves   []*Move
+}
+
+// planShowMoves returns the moves needed to reorganize the shows
+func (l *Library) planShowMoves(log *logrus.Entry) []*showMoves {
+	shows := []*showMoves{}
+	index := l.showIndex.Index()
+
+	ids := make([]string, 0, len(index))
+	for id := range index {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		indexed, err := l.showIndex.IndexedShow(id)
+		if err != nil {
+			continue
+		}
+
+		slog := log.WithField("imdb_id", id)
+		show, err := l.newShowFromPath(l.showNFOPath(indexed.Path))
+		if err != nil {
+			slog.Warnf("failed to read show: %s", err)
+			continue
+		}
+
+		sm := &showMoves{
+			imdbID:  id,
+			from:    indexed.Path,
+			seasons: map[string]string{},
+		}
+
+		for _, sNum := range indexed.SeasonList() {
+			season := indexed.Seasons[sNum]
+			eNums := make([]int, 0, len(season.Episodes))
+			for eNum := range season.Episodes {
+				eNums = append(eNums, eNum)
+			}
+			slices.Sort(eNums)
+
+			for _, eNum := range eNums {
+				path := season.Episodes[eNum].Path
+				ep, err := l.newEpisodeFromPath(path)
+				if err != nil {
+					slog.Warnf("failed to read episode: %s", err)
+					continue
+				}
+				ep.ShowImdbID = id
+
+				data := episodeNamingData(ep, show)
+				if sm.to == "" {
+					sm.to = filepath.Join(l.ShowDir, renderName(l.Naming.ShowDir, defaultNaming.ShowDir, data))
+				}
+
+				seasonDir, ok := sm.seasons[season.Path]
+				if !ok {
+					seasonDir = filepath.Join(sm.to, renderName(l.Naming.SeasonDir, defaultNaming.SeasonDir, data))
+					sm.seasons[season.Path] = seasonDir
+				}
+
+				m := &Move{
+					Type:    "episode",
+					ImdbID:  id,
+					Season:  sNum,
+					Episode: eNum,
+					From:    path,
+					To:      filepath.Join(seasonDir, l.getEpisodeFileName(ep)),
+				}
+
+				if m.To == m.From {
+					continue
+				}
+
+				sm.moves = append(sm.moves, m)
+			}
+		}
+
+		if sm.to != sm.from && exists(sm.to) {
+			for _, m := range sm.moves {
+				m.Error = ErrReorganizeConflict.Error()
+			}
+		}
+
+		if len(sm.moves) > 0 {
+			shows = append(shows, sm)
+		}
+	}
+
+	return shows
+}
+
+// moveShow moves the show directory, its season directories and renames the
+// episode files
+func (l *Library) moveShow(sm *showMoves, log *logrus.Entry) {
+	log = log.WithField("imdb_id", sm.imdbID)
+
+	fail := func(err error, moves ...*Move) {
+		for _, m := range moves {
+			m.Error = err.Error()
+		}
+		log.Errorf("failed to move show: %s", err)
+	}
+
+	if sm.from != sm.to {
+		if exists(sm.to) {
+			fail(ErrReorganizeConflict, sm.moves...)
+			return
+		}
+
+		if err := os.Rename(sm.from, sm.to); err != nil {
+			fail(err, sm.moves...)
+			return
+		}
+	}
+
+	// inShowDir returns the path inside the new show directory
+	inShowDir := func(path string) string {
+		rel, err := filepath.Rel(sm.from, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return path
+		}
+		return filepath.Join(sm.to, rel)
+	}
+
+	// Move the season directories that don't exist yet, the episodes are
+	// moved one by one to the others
+	renamed := map[string]bool{}
+	for oldSeason, newSeason := range sm.seasons {
+		oldSeason = inShowDir(oldSeason)
+		if oldSeason == newSeason || exists(newSeason) {
+			continue
+		}
+
+		if err := os.Rename(oldSeason, newSeason); err != nil {
+			log.Errorf("failed to move season: %s", err)
+			continue
+		}
+		renamed[oldSeason] = true
+	}
+
+	emptied := map[string]bool{}
+	for _, m := range sm.moves {
+		from := inShowDir(m.From)
+		if dir := filepath.Dir(from); renamed[dir] {
+			from = filepath.Join(filepath.Dir(m.To), filepath.Base(from))
+		} else if dir != filepath.Dir(m.To) {
+			if err := os.MkdirAll(filepath.Dir(m.To), os.ModePerm); err != nil {
+				fail(err, m)
+				continue
+			}
+			emptied[dir] = true
+		}
+
+		if err := l.moveVideoFiles(from, m.To); err != nil {
+			fail(err, m)
+		}
+	}
+
+	// Remove the season directories left empty
+	for dir := range emptied {
+		if entries, err := os.ReadDir(dir); err == nil && len(entries) == 0 {
+			_ = os.Remove(dir)
+		}
+	}
+}
+
+// moveVideoFiles moves a video file along with the files named after it:
+// its NFO, subtitles and images
+func (l *Library) moveVideoFiles(from, to string) error {
+	if from == to {
+		return nil
+	}
+
+	if exists(to) {
+		return fmt.Errorf("%w: %s", ErrReorganizeConflict, to)
+	}
+
+	oldBase := strings.TrimSuffix(filepath.Base(from), filepath.Ext(from))
+	newBase := strings.TrimSuffix(filepath.Base(to), filepath.Ext(to))
+
+	entries, err := os.ReadDir(filepath.Dir(from))
+	if err != nil {
+		return err
+	}
+
+	moves := map[string]string{from: to}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || name == filepath.Base(from) || l.fileConfig.IsVideo(name) {
+			continue
+		}
+
+		suffix, ok := strings.CutPrefix(name, oldBase)
+		if !ok || (!strings.HasPrefix(suffix, ".") && !strings.HasPrefix(suffix, "-")) {
+			continue
+		}
+
+		moves[filepath.Join(filepath.Dir(from), name)] = filepath.Join(filepath.Dir(to), newBase+suffix)
+	}
+
+	for src, dst := range moves {
+		if err := MoveFile(src, dst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"text/template"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+func TestReorganize(t *testing.T) {
+	lib, err := newMockLibrary()
+	defer lib.cleanup()
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	movieDir := filepath.Join(lib.MovieDir, "the.movie.1080p")
+	showDir := filepath.Join(lib.ShowDir, "the.show")
+	seasonDir := filepath.Join(showDir, "S01")
+	for _, dir := range []string{movieDir, seasonDir} {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+	}
+
+	movie := &polochon.Movie{ImdbID: "tt00001", Title: "The Movie", Year: 2000}
+	show := &polochon.Show{ImdbID: "tt00002", Title: "The Show"}
+	episode := &polochon.ShowEpisode{ShowImdbID: "tt00002", Title: "Pilot", Season: 1, Episode: 1}
+
+	for path, content := range map[string]any{
+		filepath.Join(movieDir, "the.movie.nfo"):  movie,
+		filepath.Join(showDir, "tvshow.nfo"):      show,
+		filepath.Join(seasonDir, "s01e01.nfo"):    episode,
+		filepath.Join(movieDir, "fanart.jpg"):     nil,
+		filepath.Join(movieDir, "the.movie.mp4"):  nil,
+		filepath.Join(movieDir, "the.movie.srt"):  nil,
+		filepath.Join(seasonDir, "s01e01.mp4"):    nil,
+		filepath.Join(seasonDir, "s01e01.fr.srt"): nil,
+	} {
+		if content == nil {
+			err = os.WriteFile(path, []byte("data"), 0o644)
+		} else {
+			err = writeNFOFile(path, content)
+		}
+		if err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+	}
+
+	if err := lib.RebuildIndex(mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	lib.Naming.MovieFile = template.Must(template.New("movie_file").Parse(`{{.Title}}`))
+
+	newMovieDir := filepath.Join(lib.MovieDir, "The Movie (2000)")
+	newSeasonDir := filepath.Join(lib.ShowDir, "The Show", "Season 1")
+	expectedMoves := []*Move{
+		{
+			Type:   "movie",
+			ImdbID: "tt00001",
+			From:   filepath.Join(movieDir, "the.movie.mp4"),
+			To:     filepath.Join(newMovieDir, "The Movie.mp4"),
+		},
+		{
+			Type:    "episode",
+			ImdbID:  "tt00002",
+			Season:  1,
+			Episode: 1,
+			From:    filepath.Join(seasonDir, "s01e01.mp4"),
+			To:      filepath.Join(newSeasonDir, "s01e01.mp4"),
+		},
+	}
+
+	// Nothing is moved in dry run mode
+	result, err := lib.Reorganize(true, mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if !reflect.DeepEqual(result.Moves, expectedMoves) {
+		t.Fatalf("unexpected moves: %+v", result.Moves)
+	}
+
+	if !exists(filepath.Join(movieDir, "the.movie.mp4")) {
+		t.Fatal("the movie should not be moved in dry run mode")
+	}
+
+	result, err = lib.Reorganize(false, mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if !reflect.DeepEqual(result.Moves, expectedMoves) {
+		t.Fatalf("unexpected moves: %+v", result.Moves)
+	}
+
+	for _, path := range []string{
+		filepath.Join(newMovieDir, "The Movie.mp4"),
+		filepath.Join(newMovieDir, "The Movie.nfo"),
+		filepath.Join(newMovieDir, "The Movie.srt"),
+		filepath.Join(newMovieDir, "fanart.jpg"),
+		filepath.Join(lib.ShowDir, "The Show", "tvshow.nfo"),
+		filepath.Join(newSeasonDir, "s01e01.mp4"),
+		filepath.Join(newSeasonDir, "s01e01.nfo"),
+		filepath.Join(newSeasonDir, "s01e01.fr.srt"),
+	} {
+		if !exists(path) {
+			t.Errorf("%s should exist", path)
+		}
+	}
+
+	for _, path := range []string{movieDir, showDir} {
+		if exists(path) {
+			t.Errorf("%s should not exist anymore", path)
+		}
+	}
+
+	m, err := lib.GetIndexedMovie(movie.ImdbID)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if m.Path != expectedMoves[0].To {
+		t.Errorf("expected movie path %q, got %q", expectedMoves[0].To, m.Path)
+	}
+
+	e, err := lib.GetIndexedEpisode(episode.ShowImdbID, episode.Season, episode.Episode)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if e.Path != expectedMoves[1].To {
+		t.Errorf("expected episode path %q, got %q", expectedMoves[1].To, e.Path)
+	}
+
+	// The library is now organized
+	result, err = lib.Reorganize(true, mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if len(result.Moves) != 0 {
+		t.Errorf("expected no move, got %+v", result.Moves)
+	}
+}
@@ -1,9 +1,7 @@
 package library
 
 import (
-	"fmt"
 	"os"
-	"path/filepath"
 
 	polochon "github.com/odwrtw/polochon/lib"
 	index "github.com/odwrtw/polochon/lib/media_index"
@@ -66,7 +64,3 @@ func (l *Library) DeleteSeason(id string, season int, log *logrus.Entry) error {
 
 	return nil
 }
-
-func (l *Library) getSeasonDir(ep *polochon.ShowEpisode) string {
-	return filepath.Join(l.ShowDir, ep.ShowTitle, fmt.Sprintf("Season %d", ep.Season))
-}
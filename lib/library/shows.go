@@ -1,6 +1,7 @@
 package library
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -14,6 +15,16 @@ func (l *Library) ShowIDs() map[string]*index.Show {
 	return l.showIndex.Index()
 }
 
+// EpisodeIDs returns the ids of the episodes of the library
+func (l *Library) EpisodeIDs() []index.EpisodeID {
+	return l.showIndex.EpisodeIDs()
+}
+
+// HasShow returns true if the show is in the store
+func (l *Library) HasShow(imdbID string) (bool, error) {
+	return l.showIndex.HasShow(imdbID)
+}
+
 // GetShow returns a Show from its id
 func (l *Library) GetShow(id string) (*polochon.Show, error) {
 	path, err := l.showIndex.ShowPath(id)
@@ -56,36 +67,54 @@ func (l *Library) GetIndexedShow(id string) (*index.Show, error) {
 	return s, nil
 }
 
-func (l *Library) addShow(ep *polochon.ShowEpisode, log *logrus.Entry) error {
-	dir := l.getShowDir(ep)
-	nfoPath := l.showNFOPath(dir)
-	if exists(nfoPath) {
-		return nil
+// addShow adds the show of the episode to the library if needed and returns
+// its directory
+func (l *Library) addShow(ep *polochon.ShowEpisode, log *logrus.Entry) (string, error) {
+	// Keep the directory of the show already in the library
+	if dir, err := l.showIndex.ShowPath(ep.ShowImdbID); err == nil {
+		return dir, nil
 	}
 
 	s := ep.Show
 	if s == nil {
 		s = polochon.NewShowFromEpisode(ep)
 		if err := polochon.GetDetails(s, log); err != nil {
-			return err
+			return "", err
 		}
 	}
 
+	dir := filepath.Join(l.ShowDir, renderName(l.Naming.ShowDir, defaultNaming.ShowDir, episodeNamingData(ep, s)))
+	nfoPath := l.showNFOPath(dir)
+	if exists(nfoPath) {
+		// Another show may be named the same way, e.g. a remake with the
+		// default naming template
+		existing, err := l.newShowFromPath(nfoPath)
+		if err != nil {
+			return "", err
+		}
+
+		if existing.ImdbID != ep.ShowImdbID {
+			return "", fmt.Errorf("%w: %s", ErrShowDirConflict, existing.ImdbID)
+		}
+
+		return dir, nil
+	}
+
 	// Create show dir if necessary
 	if !exists(dir) {
 		if err := os.Mkdir(dir, os.ModePerm); err != nil {
-			return err
+			return "", err
 		}
 	}
 
 	// Write NFO into the file
 	if err := writeNFOFile(nfoPath, s); err != nil {
-		return err
+		return "", err
 	}
 
 	// Download show images
 	if s.Fanart == "" || s.Banner == "" || s.Poster == "" {
-		return ErrMissingShowImageURL
+		return "", ErrMissingShowImageURL
 	}
 
 	// Download images
@@ -109,11 +138,11 @@ func (l *Library) addShow(ep *polochon.ShowEpisode, log *logrus.Entry) error {
 		log.Debug("downloading " + img.name)
 		savePath := filepath.Join(dir, img.name)
 		if err := download(img.url, savePath); err != nil {
-			return err
+			return "", err
 		}
 	}
 
-	return nil
+	return dir, nil
 }
 
 // newShowFromPath returns a new Show from its path
@@ -126,10 +155,6 @@ func (l *Library) newShowFromPath(path string) (*polochon.Show, error) {
 	return s, nil
 }
 
-func (l *Library) getShowDir(ep *polochon.ShowEpisode) string {
-	return filepath.Join(l.ShowDir, ep.ShowTitle)
-}
-
 func (l *Library) showNFOPath(showDir string) string {
 	return filepath.Join(showDir, "tvshow.nfo")
 }
@@ -0,0 +1,206 @@
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	index "github.com/odwrtw/polochon/lib/media_index"
+)
+
+// indexVersion is the version of the saved index format, the saved indexes
+// with another version are ignored
+const indexVersion = 1
+
+// Custom errors
+var (
+	ErrIndexPathNotSet = errors.New("library: index path not set")
+	ErrOutdatedIndex   = errors.New("library: outdated saved index")
+)
+
+// indexSnapshot represents the library index along with the fingerprints of
+// the directories it was built from, the directories are keyed by name
+type indexSnapshot struct {
+	Version  int                       `json:"version"`
+	MovieDir string                    `json:"movie_dir"`
+	ShowDir  string                    `json:"show_dir"`
+	Movies   map[string]*movieSnapshot `json:"movies"`
+	Shows    map[string]*showSnapshot  `json:"shows"`
+}
+
+type movieSnapshot struct {
+	Fingerprint string       `json:"fingerprint"`
+	ImdbID      string       `json:"imdb_id"`
+	Path        string       `json:"path"`
+	Movie       *index.Movie `json:"movie"`
+}
+
+type showSnapshot struct {
+	Fingerprint string                     `json:"fingerprint"`
+	ImdbID      string                     `json:"imdb_id"`
+	Path        string                     `json:"path"`
+	Show        *index.Show                `json:"show"`
+	Seasons     map[string]*seasonSnapshot `json:"seasons"`
+}
+
+type seasonSnapshot struct {
+	Fingerprint string             `json:"fingerprint"`
+	Path        string             `json:"path"`
+	Episodes    []*episodeSnapshot `json:"episodes"`
+}
+
+type episodeSnapshot struct {
+	Path    string         `json:"path"`
+	Season  int            `json:"season"`
+	Episode int            `json:"episode"`
+	Entry   *index.Episode `json:"entry"`
+}
+
+func (l *Library) newIndexSnapshot() *indexSnapshot {
+	return &indexSnapshot{
+		Version:  indexVersion,
+		MovieDir: l.MovieDir,
+		ShowDir:  l.ShowDir,
+		Movies:   map[string]*movieSnapshot{},
+		Shows:    map[string]*showSnapshot{},
+	}
+}
+
+// indexedShow returns the indexed show built from the snapshot, nil is
+// returned if the show has no episode
+func (s *showSnapshot) indexedShow() *index.Show {
+	show := *s.Show
+	show.Seasons = map[int]*index.Season{}
+	for _, season := range s.Seasons {
+		for _, e := range season.Episodes {
+			is, ok := show.Seasons[e.Season]
+			if !ok {
+				is = &index.Season{
+					Path:     season.Path,
+					Episodes: map[int]*index.Episode{},
+				}
+				show.Seasons[e.Season] = is
+			}
+
+			is.Episodes[e.Episode] = e.Entry
+		}
+	}
+
+	if len(show.Seasons) == 0 {
+		return nil
+	}
+
+	return &show
+}
+
+// restorePaths sets the paths of the index entries, they're not part of their
+// JSON representation
+func (s *indexSnapshot) restorePaths() {
+	for _, m := range s.Movies {
+		m.Movie.Path = m.Path
+	}
+
+	for _, show := range s.Shows {
+		show.Show.Path = show.Path
+		for _, season := range show.Seasons {
+			for _, e := range season.Episodes {
+				e.Entry.Path = e.Path
+			}
+		}
+	}
+}
+
+// loadIndex reads the index saved on disk
+func (l *Library) loadIndex() (*indexSnapshot, error) {
+	if l.IndexPath == "" {
+		return nil, ErrIndexPathNotSet
+	}
+
+	data, err := os.ReadFile(l.IndexPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &indexSnapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("library: failed to read the saved index: %w", err)
+	}
+
+	if s.Version != indexVersion || s.MovieDir != l.MovieDir || s.ShowDir != l.ShowDir {
+		return nil, ErrOutdatedIndex
+	}
+
+	if s.Movies == nil {
+		s.Movies = map[string]*movieSnapshot{}
+	}
+
+	if s.Shows == nil {
+		s.Shows = map[string]*showSnapshot{}
+	}
+
+	s.restorePaths()
+	return s, nil
+}
+
+// saveIndex writes the index on disk, nothing is done if no index path is
+// configured
+func (l *Library) saveIndex(s *indexSnapshot) error {
+	if l.IndexPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	// Write the index in a temporary file to never leave a partial index
+	tmp, err := os.CreateTemp(filepath.Dir(l.IndexPath), filepath.Base(l.IndexPath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), l.IndexPath)
+}
+
+// fingerprint returns a fingerprint of the files of a directory based on their
+// names, sizes and modification times, the sub directories are ignored
+func fingerprint(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	h := fnv.New64a()
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+
+		_, _ = fmt.Fprintf(h, "%s\x00%d\x00%d\n", e.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+
+	return strconv.FormatUint(h.Sum64(), 16), nil
+}
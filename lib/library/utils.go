@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"os"
 
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/odwrtw/polochon/lib/configuration"
 	"github.com/odwrtw/polochon/lib/nfo"
 )
 
@@ -63,6 +65,92 @@ func exists(path string) bool {
 	return false
 }
 
+// importFile puts a file next to its destination according to the import
+// mode before renaming it, this way an existing destination file is replaced
+// atomically
+func importFile(from, to string, mode configuration.ImportMode) error {
+	tmp := to + ".part"
+	if err := transferFile(from, tmp, mode); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, to); err != nil {
+		// Try to put the file back where it was
+		if mode.KeepsSource() {
+			_ = os.Remove(tmp)
+		} else {
+			_ = MoveFile(tmp, from)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// transferFile moves, copies or links a file according to the import mode
+func transferFile(from, to string, mode configuration.ImportMode) error {
+	switch mode {
+	case configuration.ImportCopy:
+		return copyFile(from, to)
+	case configuration.ImportHardlink:
+		return os.Link(from, to)
+	case configuration.ImportReflink:
+		if err := reflinkFile(from, to); err == nil {
+			return nil
+		}
+
+		// The filesystem does not support reflinks
+		return copyFile(from, to)
+	default:
+		return MoveFile(from, to)
+	}
+}
+
+// copyFile copies the content of a file to a new file
+func copyFile(from, to string) error {
+	source, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+
+	destination, err := os.Create(to)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(destination, source); err != nil {
+		_ = destination.Close()
+		_ = os.Remove(to)
+		return err
+	}
+
+	return destination.Close()
+}
+
+// removeVideoFiles removes a video file and the files named after it
+func removeVideoFiles(v polochon.Video) error {
+	file := v.GetFile()
+	paths := []string{file.Path, file.NfoPath()}
+	for _, s := range v.GetSubtitles() {
+		if !s.Embedded {
+			paths = append(paths, s.Path)
+		}
+	}
+
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // MoveFile is a small function that tries to rename a file, and if it fails,
 // tries to manually move a file by copying + deleting it
 func MoveFile(from string, to string) error {
@@ -73,20 +161,7 @@ func MoveFile(from string, to string) error {
 		return nil
 	case *os.LinkError:
 		// Rename failed, and it's a LinkError, try to copy and delete the file
-		source, err := os.Open(from)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = source.Close() }()
-
-		destination, err := os.Create(to)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = destination.Close() }()
-
-		_, err = io.Copy(destination, source)
-		if err != nil {
+		if err := copyFile(from, to); err != nil {
 			return err
 		}
 		return os.Remove(from)
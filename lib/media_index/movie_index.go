@@ -1,6 +1,7 @@
 package index
 
 import (
+	"path/filepath"
 	"sync"
 
 	polochon "github.com/odwrtw/polochon/lib"
@@ -22,6 +23,7 @@ type Movie struct {
 	Filename  string      `json:"filename"`
 	Title     string      `json:"title"`
 	Year      int         `json:"year"`
+	Genres    []string    `json:"genres,omitempty"`
 	Size      int64       `json:"size"`
 	Subtitles []*Subtitle `json:"subtitles"`
 	Fanart    *File       `json:"fanart_file"`
@@ -58,13 +60,14 @@ func (mi *MovieIndex) Movie(imdbID string) (*Movie, error) {
 	return movie, nil
 }
 
-// Add adds a movie to an index
-func (mi *MovieIndex) Add(movie *polochon.Movie) error {
+// NewMovie returns a new indexed movie
+func NewMovie(movie *polochon.Movie) *Movie {
 	m := &Movie{
 		Path:          movie.Path,
 		Filename:      movie.Filename(),
 		Title:         movie.Title,
 		Year:          movie.Year,
+		Genres:        movie.Genres,
 		Size:          movie.Size,
 		VideoMetadata: movie.VideoMetadata,
 		Subtitles:     []*Subtitle{},
@@ -85,6 +88,13 @@ func (mi *MovieIndex) Add(movie *polochon.Movie) error {
 		*e.file = newFile(e.path)
 	}
 
+	return m
+}
+
+// Add adds a movie to an index
+func (mi *MovieIndex) Add(movie *polochon.Movie) error {
+	m := NewMovie(movie)
+
 	mi.Lock()
 	mi.ids[movie.ImdbID] = m
 	mi.Unlock()
@@ -92,6 +102,22 @@ func (mi *MovieIndex) Add(movie *polochon.Movie) error {
 	return nil
 }
 
+// Set sets the indexed movie of an ImdbID
+func (mi *MovieIndex) Set(imdbID string, m *Movie) {
+	mi.Lock()
+	defer mi.Unlock()
+
+	mi.ids[imdbID] = m
+}
+
+// Replace replaces the whole content of the index
+func (mi *MovieIndex) Replace(movies map[string]*Movie) {
+	mi.Lock()
+	defer mi.Unlock()
+
+	mi.ids = movies
+}
+
 // UpsertSubtitle updates or insert a subtitle
 func (mi *MovieIndex) UpsertSubtitle(m *polochon.Movie, s *polochon.Subtitle) error {
 	movie, err := mi.Movie(m.ImdbID)
@@ -127,6 +153,20 @@ func (mi *MovieIndex) IDs() []string {
 	return extractAndSortStringMapKeys(mi.ids)
 }
 
+// InDir returns the ImdbID of the movie stored in a directory
+func (mi *MovieIndex) InDir(dir string) (string, bool) {
+	mi.RLock()
+	defer mi.RUnlock()
+
+	for id, m := range mi.ids {
+		if filepath.Dir(m.Path) == dir {
+			return id, true
+		}
+	}
+
+	return "", false
+}
+
 // Index returns the movie index to be rendered
 func (mi *MovieIndex) Index() map[string]*Movie {
 	mi.RLock()
@@ -1,7 +1,9 @@
 package index
 
 import (
+	"cmp"
 	"path/filepath"
+	"slices"
 	"sync"
 
 	polochon "github.com/odwrtw/polochon/lib"
@@ -60,14 +62,33 @@ type Season struct {
 // Episode represents an indexed episode
 type Episode struct {
 	polochon.VideoMetadata
-	Path      string      `json:"-"`
-	Filename  string      `json:"filename"`
-	Size      int64       `json:"size"`
-	Subtitles []*Subtitle `json:"subtitles"`
+	Path        string      `json:"-"`
+	Filename    string      `json:"filename"`
+	Size        int64       `json:"size"`
+	LastEpisode int         `json:"last_episode,omitempty"`
+	Subtitles   []*Subtitle `json:"subtitles"`
 
 	NFO *File `json:"nfo_file"`
 }
 
+// NewEpisode returns a new indexed episode
+func NewEpisode(episode *polochon.ShowEpisode) *Episode {
+	e := &Episode{
+		Path:          episode.Path,
+		Filename:      episode.Filename(),
+		Size:          episode.Size,
+		LastEpisode:   episode.LastEpisode,
+		VideoMetadata: episode.VideoMetadata,
+		NFO:           newFile(episode.NfoPath()),
+	}
+
+	for _, s := range episode.Subtitles {
+		e.Subtitles = append(e.Subtitles, NewSubtitle(s))
+	}
+
+	return e
+}
+
 // SeasonList returns the season numbers of the indexed show
 func (si *Show) SeasonList() []int {
 	return extractAndSortIndexedSeasonsMapKeys(si.Seasons)
@@ -87,6 +108,20 @@ func (si *ShowIndex) Clear() {
 	si.shows = map[string]*Show{}
 }
 
+// Set sets the indexed show of an ImdbID
+func (si *ShowIndex) Set(imdbID string, s *Show) {
+	si.Lock()
+	defer si.Unlock()
+	si.shows[imdbID] = s
+}
+
+// Replace replaces the whole content of the index
+func (si *ShowIndex) Replace(shows map[string]*Show) {
+	si.Lock()
+	defer si.Unlock()
+	si.shows = shows
+}
+
 // Index returns the showIndex
 func (si *ShowIndex) Index() map[string]*Show {
 	si.RLock()
@@ -94,6 +129,44 @@ func (si *ShowIndex) Index() map[string]*Show {
 	return si.shows
 }
 
+// EpisodeID identifies an indexed episode
+type EpisodeID struct {
+	ImdbID  string
+	Season  int
+	Episode int
+}
+
+// EpisodeIDs returns the ids of the indexed episodes sorted by show, season
+// and episode, the ids are copied so that the index can be updated while they
+// are used
+func (si *ShowIndex) EpisodeIDs() []EpisodeID {
+	si.RLock()
+	defer si.RUnlock()
+
+	ids := []EpisodeID{}
+	for imdbID, show := range si.shows {
+		for seasonNum, season := range show.Seasons {
+			if season == nil {
+				continue
+			}
+
+			for episodeNum := range season.Episodes {
+				ids = append(ids, EpisodeID{ImdbID: imdbID, Season: seasonNum, Episode: episodeNum})
+			}
+		}
+	}
+
+	slices.SortFunc(ids, func(a, b EpisodeID) int {
+		return cmp.Or(
+			cmp.Compare(a.ImdbID, b.ImdbID),
+			cmp.Compare(a.Season, b.Season),
+			cmp.Compare(a.Episode, b.Episode),
+		)
+	})
+
+	return ids
+}
+
 // HasShow returns true if the show is already in the index
 func (si *ShowIndex) HasShow(imdbID string) (bool, error) {
 	_, err := si.ShowPath(imdbID)
@@ -164,14 +237,30 @@ func (si *ShowIndex) Episode(imdbID string, sNum, eNum int) (*Episode, error) {
 		return nil, ErrNotFound
 	}
 
-	episode, ok := season.Episodes[eNum]
-	if !ok {
+	_, episode := season.episode(eNum)
+	if episode == nil {
 		return nil, ErrNotFound
 	}
 
 	return episode, nil
 }
 
+// episode returns an episode of the season along with the number it's indexed
+// with, a multi-episode file is indexed once with its first episode number
+func (s *Season) episode(eNum int) (int, *Episode) {
+	if e, ok := s.Episodes[eNum]; ok {
+		return eNum, e
+	}
+
+	for n, e := range s.Episodes {
+		if n < eNum && eNum <= e.LastEpisode {
+			return n, e
+		}
+	}
+
+	return 0, nil
+}
+
 // IndexedSeason returns the indexed season from the index
 func (si *ShowIndex) IndexedSeason(imdbID string, sNum int) (*Season, error) {
 	si.RLock()
@@ -257,20 +346,16 @@ func (si *ShowIndex) Add(episode *polochon.ShowEpisode) error {
 	}
 
 	// Add the episode
-	e := &Episode{
-		Path:          episode.Path,
-		Filename:      episode.Filename(),
-		Size:          episode.Size,
-		VideoMetadata: episode.VideoMetadata,
-		NFO:           newFile(episode.NfoPath()),
-	}
-
-	for _, s := range episode.Subtitles {
-		e.Subtitles = append(e.Subtitles, NewSubtitle(s))
-	}
+	e := NewEpisode(episode)
 
 	si.Lock()
-	si.shows[episode.ShowImdbID].Seasons[episode.Season].Episodes[episode.Episode] = e
+	episodes := si.shows[episode.ShowImdbID].Seasons[episode.Season].Episodes
+	// The episodes contained in a multi-episode file are not indexed on
+	// their own
+	for _, n := range episode.EpisodeNumbers()[1:] {
+		delete(episodes, n)
+	}
+	episodes[episode.Episode] = e
 	si.Unlock()
 
 	return nil
@@ -363,7 +448,9 @@ func (si *ShowIndex) RemoveEpisode(episode *polochon.ShowEpisode, log *logrus.En
 	// Delete the episode from the index
 	si.Lock()
 	defer si.Unlock()
-	delete(si.shows[id].Seasons[sNum].Episodes, eNum)
+	season := si.shows[id].Seasons[sNum]
+	n, _ := season.episode(eNum)
+	delete(season.Episodes, n)
 
 	return nil
 }
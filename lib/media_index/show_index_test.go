@@ -163,6 +163,22 @@ func TestShowIndexHasEpisode(t *testing.T) {
 	}
 }
 
+func TestShowIndexEpisodeIDs(t *testing.T) {
+	idx := mockShowIndex()
+
+	expected := []EpisodeID{
+		{ImdbID: "tt0944947", Season: 1, Episode: 1},
+		{ImdbID: "tt0944947", Season: 1, Episode: 2},
+		{ImdbID: "tt0944947", Season: 2, Episode: 2},
+		{ImdbID: "tt1520211", Season: 2, Episode: 1},
+		{ImdbID: "tt2306299", Season: 9, Episode: 18},
+	}
+
+	if got := idx.EpisodeIDs(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
 func TestShowIndexIsShowEmpty(t *testing.T) {
 	idx := mockShowIndex()
 	for id, expected := range map[string]bool{
@@ -368,6 +384,61 @@ func TestShowIndexAdd(t *testing.T) {
 	}
 }
 
+func TestShowIndexMultiEpisode(t *testing.T) {
+	idx := mockShowIndex()
+
+	// The file contains the episodes 2 to 4 of the season 1
+	episode := &polochon.ShowEpisode{
+		ShowImdbID:  "tt0944947",
+		Season:      1,
+		Episode:     2,
+		LastEpisode: 4,
+	}
+	episode.Path = "/home/shows/Game Of Thrones/Season 1/s01e02e03e04.mp4"
+
+	if err := idx.Add(episode); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	season, err := idx.IndexedSeason("tt0944947", 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if len(season.Episodes) != 2 {
+		t.Errorf("expected the multi-episode file to be indexed once, got %d episodes", len(season.Episodes))
+	}
+
+	for eNum, expected := range map[int]bool{1: true, 2: true, 3: true, 4: true, 5: false} {
+		got, err := idx.HasEpisode("tt0944947", 1, eNum)
+		if err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+
+		if got != expected {
+			t.Errorf("expected %t, got %t for episode %d", expected, got, eNum)
+		}
+	}
+
+	e, err := idx.Episode("tt0944947", 1, 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if e.Path != episode.Path {
+		t.Errorf("expected path %q, got %q", episode.Path, e.Path)
+	}
+
+	// Removing any of the episodes removes the file from the index
+	if err := idx.RemoveEpisode(&polochon.ShowEpisode{ShowImdbID: "tt0944947", Season: 1, Episode: 4}, mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if ok, _ := idx.HasEpisode("tt0944947", 1, 2); ok {
+		t.Error("the multi-episode file should be removed from the index")
+	}
+}
+
 func TestEmptyShowIndex(t *testing.T) {
 	idx := NewShowIndex()
 	expected := map[string]*Show{}
@@ -20,20 +20,22 @@ func NewEpisode(se *polochon.ShowEpisode) *Episode {
 type episodeFields struct {
 	Metadata Metadata `xml:"polochon"`
 
-	Title         string  `xml:"title"`
-	ShowTitle     string  `xml:"showtitle"`
-	Season        int     `xml:"season"`
-	Episode       int     `xml:"episode"`
-	TvdbID        int     `xml:"uniqueid"`
-	Aired         string  `xml:"aired"`
-	Premiered     string  `xml:"premiered"`
-	Plot          string  `xml:"plot"`
-	Runtime       int     `xml:"runtime"`
-	Thumb         string  `xml:"thumb"`
-	Rating        float32 `xml:"rating"`
-	ShowImdbID    string  `xml:"showimdbid"`
-	ShowTvdbID    int     `xml:"showtvdbid"`
-	EpisodeImdbID string  `xml:"episodeimdbid"`
+	Title          string  `xml:"title"`
+	ShowTitle      string  `xml:"showtitle"`
+	Season         int     `xml:"season"`
+	Episode        int     `xml:"episode"`
+	LastEpisode    int     `xml:"lastepisode,omitempty"`
+	AbsoluteNumber int     `xml:"absolutenumber,omitempty"`
+	TvdbID         int     `xml:"uniqueid"`
+	Aired          string  `xml:"aired"`
+	Premiered      string  `xml:"premiered"`
+	Plot           string  `xml:"plot"`
+	Runtime        int     `xml:"runtime"`
+	Thumb          string  `xml:"thumb"`
+	Rating         float32 `xml:"rating"`
+	ShowImdbID     string  `xml:"showimdbid"`
+	ShowTvdbID     int     `xml:"showtvdbid"`
+	EpisodeImdbID  string  `xml:"episodeimdbid"`
 }
 
 // MarshalXML implements the XML Marshaler interface
@@ -44,20 +46,22 @@ func (e *Episode) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
 		Metadata: Metadata{
 			VideoMetadata: &e.VideoMetadata,
 		},
-		Title:         e.Title,
-		ShowTitle:     e.ShowTitle,
-		Season:        e.Season,
-		Episode:       e.Episode,
-		TvdbID:        e.TvdbID,
-		Aired:         e.Aired,
-		Premiered:     e.Aired,
-		Plot:          e.Plot,
-		Runtime:       e.Runtime,
-		Thumb:         e.Thumb,
-		Rating:        e.Rating,
-		ShowImdbID:    e.ShowImdbID,
-		ShowTvdbID:    e.ShowTvdbID,
-		EpisodeImdbID: e.EpisodeImdbID,
+		Title:          e.Title,
+		ShowTitle:      e.ShowTitle,
+		Season:         e.Season,
+		Episode:        e.Episode,
+		LastEpisode:    e.LastEpisode,
+		AbsoluteNumber: e.AbsoluteNumber,
+		TvdbID:         e.TvdbID,
+		Aired:          e.Aired,
+		Premiered:      e.Aired,
+		Plot:           e.Plot,
+		Runtime:        e.Runtime,
+		Thumb:          e.Thumb,
+		Rating:         e.Rating,
+		ShowImdbID:     e.ShowImdbID,
+		ShowTvdbID:     e.ShowTvdbID,
+		EpisodeImdbID:  e.EpisodeImdbID,
 	}
 
 	return enc.EncodeElement(nfo, start)
@@ -79,6 +83,8 @@ func (e *Episode) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	e.ShowTitle = nfo.ShowTitle
 	e.Season = nfo.Season
 	e.Episode = nfo.Episode
+	e.LastEpisode = nfo.LastEpisode
+	e.AbsoluteNumber = nfo.AbsoluteNumber
 	e.TvdbID = nfo.TvdbID
 	e.Aired = nfo.Aired
 	e.Plot = nfo.Plot
@@ -103,3 +103,23 @@ func TestEmptyEpisodeReadNFO(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestMultiEpisodeNFO(t *testing.T) {
+	expected := mockEpisode()
+	expected.LastEpisode = 19
+	expected.AbsoluteNumber = 160
+
+	var b bytes.Buffer
+	if err := Write(&b, expected); err != nil {
+		t.Fatal(err)
+	}
+
+	got := &polochon.ShowEpisode{}
+	if err := Read(&b, got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.LastEpisode != 19 || got.AbsoluteNumber != 160 {
+		t.Errorf("expected last episode 19 and absolute number 160, got %d and %d", got.LastEpisode, got.AbsoluteNumber)
+	}
+}
@@ -20,7 +20,8 @@ func (e *Episode) uri() (string, error) {
 		return "", ErrMissingShowEpisodeInformations
 	}
 
-	if e.Season == 0 || e.Episode == 0 {
+	// The season 0 holds the specials
+	if e.Episode == 0 {
 		return "", ErrMissingShowEpisodeInformations
 	}
 
@@ -25,6 +25,14 @@ func TestShowEpisode(t *testing.T) {
 			ExpectedDownloadURL: "shows/tt2357547/seasons/1/episodes/6/download",
 			ExpectedError:       nil,
 		},
+		{
+			ShowEpisode: &Episode{ShowEpisode: &polochon.ShowEpisode{
+				ShowImdbID: "tt2357547", Season: 0, Episode: 2,
+			}},
+			ExpectedURI:         "shows/tt2357547/seasons/0/episodes/2",
+			ExpectedDownloadURL: "shows/tt2357547/seasons/0/episodes/2/download",
+			ExpectedError:       nil,
+		},
 		{
 			ShowEpisode: &Episode{ShowEpisode: &polochon.ShowEpisode{
 				Season: 1, Episode: 6,
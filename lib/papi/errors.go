@@ -19,4 +19,6 @@ var (
 	ErrMissingSubtitleVideo           = errors.New("papi: missing subtitle video")
 	ErrMissingFile                    = errors.New("papi: missing file")
 	ErrMissingFileResource            = errors.New("papi: missing file resource")
+	ErrMissingSearchQuery             = errors.New("papi: missing search query")
+	ErrMissingExploreOption           = errors.New("papi: missing explore option")
 )
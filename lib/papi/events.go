@@ -3,27 +3,34 @@ package papi
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
 )
 
-// Events returns a channel that receives on each library-changed SSE event.
-// The channel is closed when the context is cancelled. On disconnect, the
-// client reconnects with exponential backoff (1s to 30s cap).
-func (c *Client) Events(ctx context.Context) <-chan struct{} {
-	ch := make(chan struct{}, 1)
+// Events returns a channel that receives the typed events sent by polochon
+// on its SSE stream. The channel is closed when the context is cancelled. On
+// disconnect, the client reconnects with exponential backoff (1s to 30s cap)
+// and asks for the events it missed using the last received event ID.
+func (c *Client) Events(ctx context.Context) <-chan *polochon.Event {
+	ch := make(chan *polochon.Event, 16)
 	go c.eventLoop(ctx, ch)
 	return ch
 }
 
-func (c *Client) eventLoop(ctx context.Context, ch chan struct{}) {
+func (c *Client) eventLoop(ctx context.Context, ch chan *polochon.Event) {
 	defer close(ch)
 
 	backoff := time.Second
 	const maxBackoff = 30 * time.Second
 
+	var lastID uint64
 	for {
-		_ = c.streamEvents(ctx, ch)
+		_ = c.streamEvents(ctx, ch, &lastID)
 		if ctx.Err() != nil {
 			return
 		}
@@ -41,7 +48,7 @@ func (c *Client) eventLoop(ctx context.Context, ch chan struct{}) {
 	}
 }
 
-func (c *Client) streamEvents(ctx context.Context, ch chan<- struct{}) error {
+func (c *Client) streamEvents(ctx context.Context, ch chan<- *polochon.Event, lastID *uint64) error {
 	url := c.endpoint + "/events"
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -55,6 +62,9 @@ func (c *Client) streamEvents(ctx context.Context, ch chan<- struct{}) error {
 	if c.basicAuth != nil {
 		req.SetBasicAuth(c.basicAuth.username, c.basicAuth.password)
 	}
+	if *lastID != 0 {
+		req.Header.Set("Last-Event-ID", strconv.FormatUint(*lastID, 10))
+	}
 
 	// Use a client without timeout for the long-lived SSE connection.
 	resp, err := (&http.Client{}).Do(req)
@@ -67,14 +77,42 @@ func (c *Client) streamEvents(ctx context.Context, ch chan<- struct{}) error {
 		return ErrResourceNotFound
 	}
 
+	var data string
 	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	for scanner.Scan() {
-		if scanner.Text() == "data:" {
-			// Non-blocking send to coalesce duplicate events.
-			select {
-			case ch <- struct{}{}:
-			default:
-			}
+		line := scanner.Text()
+
+		// Lines starting with a colon are comments
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		if field, ok := strings.CutPrefix(line, "data:"); ok {
+			data += strings.TrimPrefix(field, " ")
+			continue
+		}
+
+		// An empty line dispatches the event
+		if line != "" || data == "" {
+			continue
+		}
+
+		e := &polochon.Event{}
+		err := json.Unmarshal([]byte(data), e)
+		data = ""
+		if err != nil {
+			continue
+		}
+
+		if e.ID != 0 {
+			*lastID = e.ID
+		}
+
+		select {
+		case ch <- e:
+		case <-ctx.Done():
+			return ctx.Err()
 		}
 	}
 
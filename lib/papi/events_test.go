@@ -8,6 +8,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
 )
 
 func TestEventsReceive(t *testing.T) {
@@ -25,7 +27,7 @@ func TestEventsReceive(t *testing.T) {
 
 		mu.Lock()
 		flush = func() {
-			_, _ = io.WriteString(w, "data:\n\n")
+			_, _ = io.WriteString(w, "id: 1\nevent: movie.added\ndata: {\"id\":1,\"type\":\"movie.added\",\"imdb_id\":\"tt001\"}\n\n")
 			flusher.Flush()
 		}
 		mu.Unlock()
@@ -68,10 +70,13 @@ func TestEventsReceive(t *testing.T) {
 	mu.Unlock()
 
 	select {
-	case _, ok := <-ch:
+	case e, ok := <-ch:
 		if !ok {
 			t.Fatal("channel closed unexpectedly")
 		}
+		if e.ID != 1 || e.Type != polochon.EventMovieAdded || e.ImdbID != "tt001" {
+			t.Fatalf("unexpected event: %+v", e)
+		}
 	case <-time.After(2 * time.Second):
 		t.Fatal("did not receive event")
 	}
@@ -106,11 +111,15 @@ func TestEventsContextCancel(t *testing.T) {
 func TestEventsReconnect(t *testing.T) {
 	var mu sync.Mutex
 	var connCount int
+	var lastEventID string
 
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		mu.Lock()
 		connCount++
 		n := connCount
+		if n == 2 {
+			lastEventID = r.Header.Get("Last-Event-ID")
+		}
 		mu.Unlock()
 
 		flusher, ok := w.(http.Flusher)
@@ -122,13 +131,13 @@ func TestEventsReconnect(t *testing.T) {
 
 		if n == 1 {
 			// First connection: send one event then disconnect.
-			_, _ = io.WriteString(w, "data:\n\n")
+			_, _ = io.WriteString(w, "id: 41\ndata: {\"id\":41,\"type\":\"movie.added\"}\n\n")
 			flusher.Flush()
 			return
 		}
 
 		// Second connection: send an event and keep alive.
-		_, _ = io.WriteString(w, "data:\n\n")
+		_, _ = io.WriteString(w, "id: 42\ndata: {\"id\":42,\"type\":\"movie.deleted\"}\n\n")
 		flusher.Flush()
 		<-r.Context().Done()
 	}))
@@ -164,5 +173,8 @@ func TestEventsReconnect(t *testing.T) {
 	if connCount < 2 {
 		t.Fatalf("expected at least 2 connections, got %d", connCount)
 	}
+	if lastEventID != "41" {
+		t.Fatalf("expected Last-Event-ID 41, got %q", lastEventID)
+	}
 	mu.Unlock()
 }
@@ -96,8 +96,26 @@ func (c *Client) getMovieDetails(movie *Movie) error {
 		return err
 	}
 
+	input := &struct {
+		*Movie
+		Filename string `json:"filename"`
+	}{Movie: movie}
+
 	url := fmt.Sprintf("%s/%s", c.endpoint, uri)
-	return c.get(url, movie)
+	if err := c.get(url, input); err != nil {
+		return err
+	}
+
+	if input.Filename != "" {
+		movie.Path = input.Filename
+	}
+
+	movie.linkFiles()
+	for _, s := range movie.Subtitles {
+		s.Video = movie.Movie
+	}
+
+	return nil
 }
 
 // GetMovie returns a movie with de detailed infos from polochon
@@ -177,3 +177,46 @@ func TestGetMovie(t *testing.T) {
 		t.Fatalf("expected URL %q, got %q", expectedRequestURI, requestURI)
 	}
 }
+
+func TestGetIndexedMovie(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `
+			{
+				"title": "title_1",
+				"filename": "title_1.mkv",
+				"size": 1000,
+				"subtitles": [{"lang": "fr_FR", "size": 10}],
+				"nfo_file": {"name": "title_1.nfo", "size": 100}
+			}
+		`)
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL)
+	if err != nil {
+		t.Fatalf("invalid endpoint: %q", err)
+	}
+
+	got, err := c.GetMovie("tt001")
+	if err != nil {
+		t.Fatalf("failed to get movie: %q", err)
+	}
+
+	if got.Path != "title_1.mkv" {
+		t.Errorf("expected path %q, got %q", "title_1.mkv", got.Path)
+	}
+
+	if len(got.Subtitles) != 1 || got.Subtitles[0].Video != got.Movie {
+		t.Errorf("expected the subtitles to be linked to the movie, got %+v", got.Subtitles)
+	}
+
+	url, err := c.DownloadURL(got.NFO)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expectedURL := ts.URL + "/movies/tt001/files/title_1.nfo"
+	if url != expectedURL {
+		t.Errorf("expected NFO URL %q, got %q", expectedURL, url)
+	}
+}
@@ -0,0 +1,106 @@
+package papi
+
+import (
+	"fmt"
+	"net/url"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+// MovieResult represents a movie returned by a search or an exploration
+type MovieResult struct {
+	*polochon.Movie
+	InLibrary bool `json:"in_library"`
+}
+
+// ShowResult represents a show returned by a search or an exploration
+type ShowResult struct {
+	*polochon.Show
+	InLibrary bool `json:"in_library"`
+}
+
+// SearchMovies searches movies using the searchers configured in polochon
+func (c *Client) SearchMovies(query string) ([]*MovieResult, error) {
+	if query == "" {
+		return nil, ErrMissingSearchQuery
+	}
+
+	url := fmt.Sprintf("%s/search/movies?q=%s", c.endpoint, url.QueryEscape(query))
+
+	movies := []*MovieResult{}
+	if err := c.get(url, &movies); err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+// SearchShows searches shows using the searchers configured in polochon
+func (c *Client) SearchShows(query string) ([]*ShowResult, error) {
+	if query == "" {
+		return nil, ErrMissingSearchQuery
+	}
+
+	url := fmt.Sprintf("%s/search/shows?q=%s", c.endpoint, url.QueryEscape(query))
+
+	shows := []*ShowResult{}
+	if err := c.get(url, &shows); err != nil {
+		return nil, err
+	}
+
+	return shows, nil
+}
+
+// GetMovieExploreOptions returns the explore options available for each movie
+// explorer
+func (c *Client) GetMovieExploreOptions() (map[string][]string, error) {
+	options := map[string][]string{}
+	if err := c.get(c.endpoint+"/explore/movies", &options); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// GetShowExploreOptions returns the explore options available for each show
+// explorer
+func (c *Client) GetShowExploreOptions() (map[string][]string, error) {
+	options := map[string][]string{}
+	if err := c.get(c.endpoint+"/explore/shows", &options); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
+
+// ExploreMovies returns the movies of the explorers supporting the option
+func (c *Client) ExploreMovies(option string) ([]*MovieResult, error) {
+	if option == "" {
+		return nil, ErrMissingExploreOption
+	}
+
+	url := fmt.Sprintf("%s/explore/movies/%s", c.endpoint, url.PathEscape(option))
+
+	movies := []*MovieResult{}
+	if err := c.get(url, &movies); err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+// ExploreShows returns the shows of the explorers supporting the option
+func (c *Client) ExploreShows(option string) ([]*ShowResult, error) {
+	if option == "" {
+		return nil, ErrMissingExploreOption
+	}
+
+	url := fmt.Sprintf("%s/explore/shows/%s", c.endpoint, url.PathEscape(option))
+
+	shows := []*ShowResult{}
+	if err := c.get(url, &shows); err != nil {
+		return nil, err
+	}
+
+	return shows, nil
+}
@@ -0,0 +1,81 @@
+package papi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+func TestSearchMovies(t *testing.T) {
+	var requestURI string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.RequestURI
+		_, _ = fmt.Fprint(w, `[
+			{"imdb_id": "tt001", "title": "Movie 1", "year": 2001, "in_library": true},
+			{"imdb_id": "tt002", "title": "Movie 2", "year": 2002, "in_library": false}
+		]`)
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL)
+	if err != nil {
+		t.Fatalf("invalid endpoint: %q", err)
+	}
+
+	got, err := c.SearchMovies("the movie")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expectedURI := "/search/movies?q=the+movie"
+	if requestURI != expectedURI {
+		t.Errorf("expected request URI %q, got %q", expectedURI, requestURI)
+	}
+
+	expected := []*MovieResult{
+		{Movie: &polochon.Movie{ImdbID: "tt001", Title: "Movie 1", Year: 2001}, InLibrary: true},
+		{Movie: &polochon.Movie{ImdbID: "tt002", Title: "Movie 2", Year: 2002}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	if _, err := c.SearchMovies(""); err != ErrMissingSearchQuery {
+		t.Errorf("expected %q, got %q", ErrMissingSearchQuery, err)
+	}
+}
+
+func TestExploreShows(t *testing.T) {
+	var requestURI string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.RequestURI
+		_, _ = fmt.Fprint(w, `[{"imdb_id": "tt001", "title": "Show 1", "in_library": true}]`)
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL)
+	if err != nil {
+		t.Fatalf("invalid endpoint: %q", err)
+	}
+
+	got, err := c.ExploreShows("popular")
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expectedURI := "/explore/shows/popular"
+	if requestURI != expectedURI {
+		t.Errorf("expected request URI %q, got %q", expectedURI, requestURI)
+	}
+
+	expected := []*ShowResult{
+		{Show: &polochon.Show{ImdbID: "tt001", Title: "Show 1"}, InLibrary: true},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
@@ -2,6 +2,7 @@ package papi
 
 import (
 	"fmt"
+	"io"
 	"path/filepath"
 
 	polochon "github.com/odwrtw/polochon/lib"
@@ -105,3 +106,19 @@ func (c *Client) UpdateSubtitle(video polochon.Video, lang polochon.Language) (*
 	url := fmt.Sprintf("%s/%s", c.endpoint, uri)
 	return s, c.post(url, nil, &s)
 }
+
+// UploadSubtitle uploads the subtitle of a video
+func (c *Client) UploadSubtitle(video polochon.Video, lang polochon.Language, data io.Reader) (*Subtitle, error) {
+	s := &Subtitle{Subtitle: &polochon.Subtitle{
+		Video: video,
+		Lang:  lang,
+	}}
+
+	uri, err := s.uri()
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/%s", c.endpoint, uri)
+	return s, c.request("PUT", url, data, &s)
+}
@@ -1,9 +1,11 @@
 package papi
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 
 	polochon "github.com/odwrtw/polochon/lib"
@@ -83,3 +85,38 @@ func TestUpdateSubtitles(t *testing.T) {
 		t.Fatalf("expected: %+v, got %+v", expectedSubs, sub)
 	}
 }
+
+func TestUploadSubtitle(t *testing.T) {
+	var gotMethod, gotPath, gotBody string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethod, gotPath, gotBody = r.Method, r.URL.Path, string(body)
+		_, _ = w.Write([]byte(`{"lang":"fr_FR", "size": 4}`))
+	}))
+	defer ts.Close()
+
+	video := &Movie{Movie: &polochon.Movie{ImdbID: "fake_id"}}
+	expectedSubs := &Subtitle{Subtitle: &polochon.Subtitle{
+		File:  polochon.File{Size: 4},
+		Lang:  polochon.FR,
+		Video: video,
+	}}
+
+	client, err := New(ts.URL)
+	if err != nil {
+		t.Fatalf("expected no error doing new client, got %q", err)
+	}
+
+	sub, err := client.UploadSubtitle(video, polochon.FR, strings.NewReader("subs"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %+v", err)
+	}
+
+	if gotMethod != "PUT" || gotPath != "/movies/fake_id/subtitles/fr_FR" || gotBody != "subs" {
+		t.Fatalf("unexpected request %s %s with body %q", gotMethod, gotPath, gotBody)
+	}
+
+	if !reflect.DeepEqual(sub, expectedSubs) {
+		t.Fatalf("expected: %+v, got %+v", expectedSubs, sub)
+	}
+}
@@ -0,0 +1,62 @@
+package papi
+
+import (
+	"fmt"
+	"net/url"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+// GetWishlist returns the wishlist of polochon
+func (c *Client) GetWishlist() (*polochon.Wishlist, error) {
+	wl := &polochon.Wishlist{}
+	if err := c.get(c.endpoint+"/wishlist", wl); err != nil {
+		return nil, err
+	}
+
+	return wl, nil
+}
+
+// AddWishedMovie adds a movie to the editable wishlist of polochon
+func (c *Client) AddWishedMovie(movie *polochon.WishedMovie) error {
+	if movie == nil {
+		return ErrMissingMovie
+	}
+
+	if movie.ImdbID == "" {
+		return ErrMissingMovieID
+	}
+
+	return c.post(c.endpoint+"/wishlist/movies", movie, &polochon.WishedMovie{})
+}
+
+// AddWishedShow adds a show to the editable wishlist of polochon
+func (c *Client) AddWishedShow(show *polochon.WishedShow) error {
+	if show == nil {
+		return ErrMissingShow
+	}
+
+	if show.ImdbID == "" {
+		return ErrMissingShowID
+	}
+
+	return c.post(c.endpoint+"/wishlist/shows", show, &polochon.WishedShow{})
+}
+
+// DeleteWishedMovie removes a movie from the editable wishlist of polochon
+func (c *Client) DeleteWishedMovie(imdbID string) error {
+	if imdbID == "" {
+		return ErrMissingMovieID
+	}
+
+	return c.delete(fmt.Sprintf("%s/wishlist/movies/%s", c.endpoint, url.PathEscape(imdbID)))
+}
+
+// DeleteWishedShow removes a show from the editable wishlist of polochon
+func (c *Client) DeleteWishedShow(imdbID string) error {
+	if imdbID == "" {
+		return ErrMissingShowID
+	}
+
+	return c.delete(fmt.Sprintf("%s/wishlist/shows/%s", c.endpoint, url.PathEscape(imdbID)))
+}
@@ -0,0 +1,86 @@
+package papi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+func TestAddWishedShow(t *testing.T) {
+	var requestURI, method string
+	got := &polochon.WishedShow{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.RequestURI
+		method = r.Method
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+		_, _ = fmt.Fprint(w, `{}`)
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL)
+	if err != nil {
+		t.Fatalf("invalid endpoint: %q", err)
+	}
+
+	show := &polochon.WishedShow{
+		ImdbID:    "tt001",
+		Season:    2,
+		Episode:   3,
+		Qualities: []polochon.Quality{polochon.Quality1080p},
+	}
+	if err := c.AddWishedShow(show); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if method != "POST" || requestURI != "/wishlist/shows" {
+		t.Errorf("expected POST /wishlist/shows, got %s %s", method, requestURI)
+	}
+
+	if !reflect.DeepEqual(got, show) {
+		t.Errorf("expected %+v, got %+v", show, got)
+	}
+
+	if err := c.AddWishedShow(&polochon.WishedShow{}); err != ErrMissingShowID {
+		t.Errorf("expected %q, got %q", ErrMissingShowID, err)
+	}
+}
+
+func TestDeleteWishedMovie(t *testing.T) {
+	var requestURI, method string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.RequestURI
+		method = r.Method
+		if r.RequestURI == "/wishlist/movies/tt404" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := New(ts.URL)
+	if err != nil {
+		t.Fatalf("invalid endpoint: %q", err)
+	}
+
+	if err := c.DeleteWishedMovie("tt001"); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if method != "DELETE" || requestURI != "/wishlist/movies/tt001" {
+		t.Errorf("expected DELETE /wishlist/movies/tt001, got %s %s", method, requestURI)
+	}
+
+	if err := c.DeleteWishedMovie("tt404"); err != ErrResourceNotFound {
+		t.Errorf("expected %q, got %q", ErrResourceNotFound, err)
+	}
+
+	if err := c.DeleteWishedMovie(""); err != ErrMissingMovieID {
+		t.Errorf("expected %q, got %q", ErrMissingMovieID, err)
+	}
+}
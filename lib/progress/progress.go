@@ -0,0 +1,217 @@
+package progress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+// Custom errors
+var (
+	ErrInvalidPosition = errors.New("progress: invalid position")
+	ErrInvalidDuration = errors.New("progress: invalid duration")
+	ErrMissingPath     = errors.New("progress: missing progress file path")
+)
+
+// DefaultUser is the user of the requests made without authentication
+const DefaultUser = "default"
+
+// Config represents the configuration of the progress store
+type Config struct {
+	Path string `yaml:"path"`
+}
+
+// Progress represents the viewing state of a video for a user, the positions
+// are in seconds
+type Progress struct {
+	Watched   bool       `json:"watched"`
+	Position  int        `json:"position"`
+	Duration  int        `json:"duration,omitempty"`
+	WatchedAt *time.Time `json:"watched_at,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Validate checks the positions of the progress
+func (p *Progress) Validate() error {
+	if p.Duration < 0 {
+		return ErrInvalidDuration
+	}
+
+	if p.Position < 0 || (p.Duration > 0 && p.Position > p.Duration) {
+		return ErrInvalidPosition
+	}
+
+	return nil
+}
+
+// data represents the content of the progress file
+type data struct {
+	// Users holds the progress of each user by video key
+	Users map[string]map[string]*Progress `json:"users"`
+}
+
+// Store keeps the progress of the users
+type Store struct {
+	Config
+	// now is overwritten during the tests
+	now func() time.Time
+
+	// Mutex to protect the data, the store is shared between the apps
+	mu   sync.Mutex
+	data *data
+}
+
+// Open opens the progress file, a missing file is an empty store
+func Open(config Config) (*Store, error) {
+	if config.Path == "" {
+		return nil, ErrMissingPath
+	}
+
+	s := &Store{
+		Config: config,
+		now:    time.Now,
+		data:   &data{Users: map[string]map[string]*Progress{}},
+	}
+
+	file, err := os.Open(config.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := json.NewDecoder(file).Decode(s.data); err != nil {
+		return nil, fmt.Errorf("progress: invalid progress file: %w", err)
+	}
+
+	if s.data.Users == nil {
+		s.data.Users = map[string]map[string]*Progress{}
+	}
+
+	return s, nil
+}
+
+// Get returns the progress of a user on a video, nil is returned if the user
+// never watched the video
+func (s *Store) Get(user, key string) *Progress {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.data.Users[user][key]
+	if !ok {
+		return nil
+	}
+
+	cp := *p
+	return &cp
+}
+
+// User returns the progress of a user indexed by video key
+func (s *Store) User(user string) map[string]*Progress {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ret := make(map[string]*Progress, len(s.data.Users[user]))
+	for key, p := range s.data.Users[user] {
+		cp := *p
+		ret[key] = &cp
+	}
+
+	return ret
+}
+
+// Set sets the progress of a user on a video, the watch date is kept until
+// the video is marked as not watched
+func (s *Store) Set(user, key string, p Progress) (*Progress, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	previous := s.data.Users[user][key]
+
+	p.UpdatedAt = now
+	p.WatchedAt = nil
+	if p.Watched {
+		p.WatchedAt = &now
+		if previous != nil && previous.WatchedAt != nil {
+			p.WatchedAt = previous.WatchedAt
+		}
+	}
+
+	progress, ok := s.data.Users[user]
+	if !ok {
+		progress = map[string]*Progress{}
+		s.data.Users[user] = progress
+	}
+	progress[key] = &p
+
+	// Restore the previous progress if it could not be saved
+	if err := s.write(); err != nil {
+		switch {
+		case previous != nil:
+			progress[key] = previous
+		case len(progress) == 1:
+			delete(s.data.Users, user)
+		default:
+			delete(progress, key)
+		}
+		return nil, err
+	}
+
+	cp := p
+	return &cp, nil
+}
+
+// WatchedAt returns the last time a video has been watched, false is
+// returned unless every user with a progress on the video watched it. The
+// users who never started the video are not taken into account.
+func (s *Store) WatchedAt(key string) (time.Time, bool) {
+	if s == nil {
+		return time.Time{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var watchedAt time.Time
+	for _, progress := range s.data.Users {
+		p, ok := progress[key]
+		if !ok {
+			continue
+		}
+
+		if !p.Watched {
+			return time.Time{}, false
+		}
+
+		if p.WatchedAt != nil && p.WatchedAt.After(watchedAt) {
+			watchedAt = *p.WatchedAt
+		}
+	}
+
+	return watchedAt, !watchedAt.IsZero()
+}
+
+// write saves the progress
+func (s *Store) write() error {
+	return polochon.WriteJSONFile(s.Path, s.data)
+}
@@ -0,0 +1,172 @@
+package progress
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+var testTime = time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestStore(t *testing.T, path string) *Store {
+	s, err := Open(Config{Path: path})
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	s.now = func() time.Time { return testTime }
+	return s
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+	s := newTestStore(t, path)
+
+	key := polochon.VideoKey("tt001", 1, 2)
+	if _, err := s.Set("alice", key, Progress{Position: 120, Duration: 1200}); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if _, err := s.Set("bob", polochon.VideoKey("tt002", 0, 0), Progress{Watched: true}); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if got := s.Get("bob", key); got != nil {
+		t.Errorf("expected no progress for another user, got %+v", got)
+	}
+
+	// The progress is kept between restarts
+	s = newTestStore(t, path)
+
+	expected := map[string]*Progress{
+		"tt001-S01E02": {Position: 120, Duration: 1200, UpdatedAt: testTime},
+	}
+	if got := s.User("alice"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	// The watch date is kept until the video is marked as not watched
+	watchedAt := testTime.Add(time.Hour)
+	s.now = func() time.Time { return watchedAt }
+	if _, err := s.Set("alice", key, Progress{Watched: true}); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	s.now = func() time.Time { return watchedAt.Add(time.Hour) }
+	got, err := s.Set("alice", key, Progress{Watched: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if got.WatchedAt == nil || !got.WatchedAt.Equal(watchedAt) {
+		t.Errorf("expected watch date %s, got %v", watchedAt, got.WatchedAt)
+	}
+
+	got, err = s.Set("alice", key, Progress{})
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if got.WatchedAt != nil {
+		t.Errorf("expected no watch date, got %v", got.WatchedAt)
+	}
+}
+
+func TestOpenWithoutPath(t *testing.T) {
+	if _, err := Open(Config{}); err != ErrMissingPath {
+		t.Fatalf("expected %q, got %v", ErrMissingPath, err)
+	}
+}
+
+func TestStoreSetWriteFailure(t *testing.T) {
+	s := newTestStore(t, filepath.Join(t.TempDir(), "progress.json"))
+
+	key := polochon.VideoKey("tt001", 0, 0)
+	if _, err := s.Set("alice", key, Progress{Position: 120}); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	// The progress file cannot be written anymore
+	s.Path = filepath.Join(t.TempDir(), "missing", "progress.json")
+
+	if _, err := s.Set("alice", key, Progress{Position: 240}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if _, err := s.Set("bob", key, Progress{Position: 240}); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if got := s.Get("alice", key); got == nil || got.Position != 120 {
+		t.Errorf("expected the previous progress to be kept, got %+v", got)
+	}
+
+	if got := s.Get("bob", key); got != nil {
+		t.Errorf("expected no progress, got %+v", got)
+	}
+}
+
+func TestStoreWatchedAt(t *testing.T) {
+	s := newTestStore(t, filepath.Join(t.TempDir(), "progress.json"))
+	key := polochon.VideoKey("tt001", 1, 2)
+
+	if _, ok := s.WatchedAt(key); ok {
+		t.Error("expected a video never watched")
+	}
+
+	if _, err := s.Set("alice", key, Progress{Watched: true}); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	got, ok := s.WatchedAt(key)
+	if !ok || !got.Equal(testTime) {
+		t.Errorf("expected the video to be watched at %s, got %s", testTime, got)
+	}
+
+	// A user in the middle of the video prevents it from being watched
+	if _, err := s.Set("bob", key, Progress{Position: 10}); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if _, ok := s.WatchedAt(key); ok {
+		t.Error("expected a video being watched")
+	}
+
+	// A user who marked the video as not watched prevents it too
+	if _, err := s.Set("bob", key, Progress{}); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if _, ok := s.WatchedAt(key); ok {
+		t.Error("expected a video not watched by every user")
+	}
+
+	var nilStore *Store
+	if _, ok := nilStore.WatchedAt(key); ok {
+		t.Error("expected a nil store to have no watched video")
+	}
+}
+
+func TestProgressValidate(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		progress Progress
+		expected error
+	}{
+		{name: "valid", progress: Progress{Position: 10, Duration: 20}},
+		{name: "no duration", progress: Progress{Position: 10}},
+		{name: "negative position", progress: Progress{Position: -1}, expected: ErrInvalidPosition},
+		{name: "after the end", progress: Progress{Position: 30, Duration: 20}, expected: ErrInvalidPosition},
+		{name: "negative duration", progress: Progress{Duration: -1}, expected: ErrInvalidDuration},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.progress.Validate(); !errors.Is(err, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, err)
+			}
+		})
+	}
+}
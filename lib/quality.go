@@ -32,6 +32,44 @@ func StringToQuality(q string) (*Quality, error) {
 	return nil, fmt.Errorf("invalid quality")
 }
 
+// definitionOrder holds the qualities sorted by definition, 3D is left out as
+// it cannot be compared to the others
+var definitionOrder = []Quality{
+	Quality480p,
+	Quality720p,
+	Quality1080p,
+	Quality2160p,
+}
+
+// UpgradeQualities returns the wished qualities that would be an upgrade of
+// the current quality. The wished qualities are sorted by preference, the
+// first one being the cutoff: a video with this quality is never upgraded.
+// Only the qualities with a better definition are upgrades, a preferred
+// quality with a lower definition never replaces a video. Nothing is returned
+// if the current quality is unknown to avoid downloading the same video over
+// and over.
+func UpgradeQualities(current Quality, wished []Quality) []Quality {
+	if current == "" || len(wished) == 0 {
+		return nil
+	}
+
+	// The current quality is wished, only the preferred ones are upgrades
+	if i := slices.Index(wished, current); i != -1 {
+		wished = wished[:i]
+	}
+
+	return slices.DeleteFunc(slices.Clone(wished), func(q Quality) bool {
+		return !IsUpgrade(current, q)
+	})
+}
+
+// IsUpgrade returns true if the quality has a better definition than the
+// current one, the unknown qualities and 3D are never upgrades
+func IsUpgrade(current, quality Quality) bool {
+	rank := slices.Index(definitionOrder, current)
+	return rank != -1 && slices.Index(definitionOrder, quality) > rank
+}
+
 // IsAllowed checks if the quality is allowed
 func (q *Quality) IsAllowed() bool {
 	return slices.Contains([]Quality{
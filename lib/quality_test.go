@@ -1,6 +1,9 @@
 package polochon
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestIsAllowedQuality(t *testing.T) {
 	for _, allowedQuality := range []Quality{
@@ -24,3 +27,52 @@ func TestIsAllowedQuality(t *testing.T) {
 		}
 	}
 }
+
+func TestUpgradeQualities(t *testing.T) {
+	wished := []Quality{Quality1080p, Quality720p, Quality480p}
+
+	tt := []struct {
+		name     string
+		current  Quality
+		wished   []Quality
+		expected []Quality
+	}{
+		{name: "unknown quality", current: "", wished: wished},
+		{name: "nothing wished", current: Quality480p},
+		{name: "cutoff reached", current: Quality1080p, wished: wished, expected: []Quality{}},
+		{name: "wished quality", current: Quality480p, wished: wished, expected: []Quality{Quality1080p, Quality720p}},
+		{name: "better quality not wished", current: Quality2160p, wished: wished, expected: []Quality{}},
+		{name: "worse quality not wished", current: Quality720p, wished: []Quality{Quality3D, Quality1080p}, expected: []Quality{Quality1080p}},
+		{name: "3D not wished", current: Quality3D, wished: wished},
+		{name: "preferred lower definition", current: Quality1080p, wished: []Quality{Quality720p, Quality1080p}, expected: []Quality{}},
+		{name: "preferred lower and higher definitions", current: Quality1080p, wished: []Quality{Quality720p, Quality2160p, Quality1080p}, expected: []Quality{Quality2160p}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := UpgradeQualities(tc.current, tc.wished)
+			if !slices.Equal(got, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsUpgrade(t *testing.T) {
+	for _, tc := range []struct {
+		current  Quality
+		quality  Quality
+		expected bool
+	}{
+		{current: Quality720p, quality: Quality1080p, expected: true},
+		{current: Quality1080p, quality: Quality1080p},
+		{current: Quality1080p, quality: Quality720p},
+		{current: "", quality: Quality1080p},
+		{current: Quality720p, quality: Quality3D},
+		{current: Quality3D, quality: Quality1080p},
+	} {
+		if got := IsUpgrade(tc.current, tc.quality); got != tc.expected {
+			t.Errorf("%q to %q: expected %t, got %t", tc.current, tc.quality, tc.expected, got)
+		}
+	}
+}
@@ -1,12 +1,49 @@
 package polochon
 
 import (
+	"errors"
+
 	"github.com/sirupsen/logrus"
 )
 
+// Searcher errors
+var (
+	ErrNoSearcher = errors.New("polochon: no searcher available")
+)
+
 // Searcher is the interface to search shows or movies from different sources
 type Searcher interface {
 	Module
 	SearchMovie(key string, log *logrus.Entry) ([]*Movie, error)
 	SearchShow(key string, log *logrus.Entry) ([]*Show, error)
 }
+
+// SearchMovies searches for movies in all the searchers, the results are
+// merged and deduplicated by imdb id
+func SearchMovies(searchers []Searcher, key string, log *logrus.Entry) ([]*Movie, error) {
+	if len(searchers) == 0 {
+		return nil, ErrNoSearcher
+	}
+
+	results := fanOut(len(searchers), func(i int) ([]*Movie, error) {
+		s := searchers[i]
+		return s.SearchMovie(key, log.WithField("searcher", s.Name()))
+	}, log)
+
+	return uniqueByImdbID(results, func(m *Movie) string { return m.ImdbID }), nil
+}
+
+// SearchShows searches for shows in all the searchers, the results are merged
+// and deduplicated by imdb id
+func SearchShows(searchers []Searcher, key string, log *logrus.Entry) ([]*Show, error) {
+	if len(searchers) == 0 {
+		return nil, ErrNoSearcher
+	}
+
+	results := fanOut(len(searchers), func(i int) ([]*Show, error) {
+		s := searchers[i]
+		return s.SearchShow(key, log.WithField("searcher", s.Name()))
+	}, log)
+
+	return uniqueByImdbID(results, func(s *Show) string { return s.ImdbID }), nil
+}
@@ -0,0 +1,74 @@
+package polochon
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+var mockLogEntry = logrus.NewEntry(&logrus.Logger{Out: io.Discard})
+
+type testSearcher struct {
+	testModule
+	movies []*Movie
+	shows  []*Show
+	err    error
+}
+
+func (s *testSearcher) SearchMovie(string, *logrus.Entry) ([]*Movie, error) {
+	return s.movies, s.err
+}
+
+func (s *testSearcher) SearchShow(string, *logrus.Entry) ([]*Show, error) {
+	return s.shows, s.err
+}
+
+func TestSearchMovies(t *testing.T) {
+	m1 := &Movie{ImdbID: "tt001", Title: "first"}
+	m2 := &Movie{ImdbID: "tt002"}
+	m3 := &Movie{ImdbID: "tt001", Title: "duplicate"}
+	m4 := &Movie{Title: "no imdb id"}
+
+	searchers := []Searcher{
+		&testSearcher{testModule: testModule{name: "s1"}, movies: []*Movie{m1, m4}},
+		&testSearcher{testModule: testModule{name: "s2"}, err: errors.New("failed")},
+		&testSearcher{testModule: testModule{name: "s3"}, movies: []*Movie{m3, m2}},
+	}
+
+	got, err := SearchMovies(searchers, "key", mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expected := []*Movie{m1, m2}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	if _, err := SearchMovies(nil, "key", mockLogEntry); err != ErrNoSearcher {
+		t.Errorf("expected %q, got %q", ErrNoSearcher, err)
+	}
+}
+
+func TestSearchShows(t *testing.T) {
+	s1 := &Show{ImdbID: "tt001"}
+	s2 := &Show{ImdbID: "tt002"}
+
+	searchers := []Searcher{
+		&testSearcher{testModule: testModule{name: "s1"}, shows: []*Show{s1}},
+		&testSearcher{testModule: testModule{name: "s2"}, shows: []*Show{s2, s1}},
+	}
+
+	got, err := SearchShows(searchers, "key", mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expected := []*Show{s1, s2}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
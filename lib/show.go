@@ -21,6 +21,8 @@ type Show struct {
 	Fanart     string         `json:"-"`
 	Poster     string         `json:"-"`
 	Episodes   []*ShowEpisode `json:"-"`
+	// Daily tells if the episodes are released by air date
+	Daily bool `json:"daily,omitempty"`
 }
 
 // NewShow returns a new show
@@ -1,5 +1,10 @@
 package polochon
 
+import "time"
+
+// AiredDateLayout is the layout of the aired dates of the episodes
+const AiredDateLayout = "2006-01-02"
+
 // ShowConfig represents the configuration for a show and its show episodes
 type ShowConfig struct {
 	Calendar   Calendar
@@ -15,20 +20,22 @@ type ShowEpisode struct {
 	ShowConfig `json:"-"`
 	BaseVideo
 
-	Title         string  `json:"title"`
-	ShowTitle     string  `json:"show_title"`
-	Season        int     `json:"season"`
-	Episode       int     `json:"episode"`
-	TvdbID        int     `json:"tvdb_id"`
-	Aired         string  `json:"aired"`
-	Plot          string  `json:"plot"`
-	Runtime       int     `json:"runtime"`
-	Thumb         string  `json:"thumb"`
-	Rating        float32 `json:"rating"`
-	ShowImdbID    string  `json:"show_imdb_id"`
-	ShowTvdbID    int     `json:"show_tvdb_id"`
-	EpisodeImdbID string  `json:"imdb_id"`
-	Show          *Show   `json:"-"`
+	Title          string  `json:"title"`
+	ShowTitle      string  `json:"show_title"`
+	Season         int     `json:"season"`
+	Episode        int     `json:"episode"`
+	LastEpisode    int     `json:"last_episode,omitempty"`
+	AbsoluteNumber int     `json:"absolute_number,omitempty"`
+	TvdbID         int     `json:"tvdb_id"`
+	Aired          string  `json:"aired"`
+	Plot           string  `json:"plot"`
+	Runtime        int     `json:"runtime"`
+	Thumb          string  `json:"thumb"`
+	Rating         float32 `json:"rating"`
+	ShowImdbID     string  `json:"show_imdb_id"`
+	ShowTvdbID     int     `json:"show_tvdb_id"`
+	EpisodeImdbID  string  `json:"imdb_id"`
+	Show           *Show   `json:"-"`
 }
 
 // NewShowEpisode returns a new show episode
@@ -48,6 +55,44 @@ func NewShowEpisodeFromFile(showConf ShowConfig, file File) *ShowEpisode {
 	return se
 }
 
+// IsSpecial returns true if the episode is a special, specials are in the
+// season 0
+func (s *ShowEpisode) IsSpecial() bool {
+	return s.Season == 0 && s.Episode != 0
+}
+
+// IsDaily returns true if the episode belongs to a daily show, the episodes
+// of the daily shows are identified by their aired date
+func (s *ShowEpisode) IsDaily() bool {
+	return s.Show != nil && s.Show.Daily
+}
+
+// AiredDate returns the aired date of the episode
+func (s *ShowEpisode) AiredDate() (time.Time, error) {
+	return time.Parse(AiredDateLayout, s.Aired)
+}
+
+// IsMultiEpisode returns true if the file of the episode contains several
+// episodes
+func (s *ShowEpisode) IsMultiEpisode() bool {
+	return s.LastEpisode > s.Episode
+}
+
+// EpisodeNumbers returns the numbers of the episodes contained in the file of
+// the episode
+func (s *ShowEpisode) EpisodeNumbers() []int {
+	if !s.IsMultiEpisode() {
+		return []int{s.Episode}
+	}
+
+	numbers := make([]int, 0, s.LastEpisode-s.Episode+1)
+	for n := s.Episode; n <= s.LastEpisode; n++ {
+		numbers = append(numbers, n)
+	}
+
+	return numbers
+}
+
 // GetTorrenters implements the Torrentable interface
 func (s *ShowConfig) GetTorrenters() []Torrenter {
 	return s.Torrenters
@@ -3,11 +3,13 @@ package polochon
 // ShowSeason represents a show season
 type ShowSeason struct {
 	ShowConfig `json:"-"`
-	ShowImdbID string `json:"show_imdb_id"`
-	Season     int    `json:"season"`
-	Banner     string `json:"-"`
-	Fanart     string `json:"-"`
-	Poster     string `json:"-"`
+	ShowImdbID string     `json:"show_imdb_id"`
+	ShowTitle  string     `json:"-"`
+	Season     int        `json:"season"`
+	Banner     string     `json:"-"`
+	Fanart     string     `json:"-"`
+	Poster     string     `json:"-"`
+	Torrents   []*Torrent `json:"-"`
 }
 
 // NewShowSeason returns a new show season
@@ -2,7 +2,6 @@ package polochon
 
 import (
 	"errors"
-	"sort"
 )
 
 var (
@@ -81,6 +80,24 @@ func (t *Torrent) HasVideo() bool {
 	return (t.Season != 0 && t.Episode != 0)
 }
 
+// IsSeasonPack returns true if the torrent holds a full show season, a
+// season pack is an episode torrent without episode number
+func (t *Torrent) IsSeasonPack() bool {
+	return t.ImdbID != "" && t.Type == TypeEpisode && t.Season != 0 && t.Episode == 0
+}
+
+// EpisodeVideo returns a new video of an episode of the torrent season, this
+// is used for the torrents holding more than one episode
+func (t *Torrent) EpisodeVideo(episode int) Video {
+	if t.ImdbID == "" || t.Type != TypeEpisode || t.Season == 0 || episode == 0 {
+		return nil
+	}
+
+	et := *t
+	et.Episode = episode
+	return et.Video()
+}
+
 // Video returns a new video based on the torrent informations
 func (t *Torrent) Video() Video {
 	if !t.HasVideo() {
@@ -124,50 +141,3 @@ func (t *Torrent) RatioReached(ratio float32) bool {
 
 	return t.Status.Ratio >= ratio
 }
-
-// FilterTorrents filters the torrents to keep only the best ones
-func FilterTorrents(torrents []*Torrent) []*Torrent {
-	torrentByQuality := map[Quality]*Torrent{}
-
-	// Keep the qualities in an array to produce a predictable output
-	qualities := []string{}
-
-	for _, t := range torrents {
-		if t.Result == nil {
-			continue
-		}
-
-		bestByQuality, ok := torrentByQuality[t.Quality]
-		if !ok {
-			torrentByQuality[t.Quality] = t
-			qualities = append(qualities, string(t.Quality))
-			continue
-		}
-
-		if t.Result.Seeders > bestByQuality.Result.Seeders {
-			torrentByQuality[t.Quality] = t
-		}
-	}
-
-	sort.Strings(qualities)
-	filtered := make([]*Torrent, len(qualities))
-	for i, q := range qualities {
-		filtered[i] = torrentByQuality[Quality(q)]
-	}
-
-	return filtered
-}
-
-// ChooseTorrentFromQualities chooses the best torrent matching the given
-// qualities
-func ChooseTorrentFromQualities(torrents []*Torrent, qualities []Quality) *Torrent {
-	for _, quality := range qualities {
-		for _, torrent := range torrents {
-			if torrent.Quality == quality {
-				return torrent
-			}
-		}
-	}
-
-	return nil
-}
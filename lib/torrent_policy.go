@@ -0,0 +1,257 @@
+package polochon
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Torrent score weights, the torrents are ranked by wanted quality first so
+// the other weights only break the ties between the torrents of a quality
+const (
+	scoreQualityWeight     = 1000
+	scoreMaxSeeders        = 100
+	scorePreferredGroup    = 200
+	scorePreferredKeyword  = 100
+	scorePreferredSource   = 50
+	scorePreferredUploader = 50
+)
+
+// SizeLimit represents the size bounds of a torrent in MB, a zero value means
+// no limit
+type SizeLimit struct {
+	Min int `yaml:"min"`
+	Max int `yaml:"max"`
+}
+
+// TorrentPolicy represents the rules used to choose a torrent among the
+// available ones
+type TorrentPolicy struct {
+	MinSeeders         int                   `yaml:"min_seeders"`
+	SizeLimits         map[Quality]SizeLimit `yaml:"size_limits"`
+	PreferredGroups    []string              `yaml:"preferred_groups"`
+	BannedGroups       []string              `yaml:"banned_groups"`
+	PreferredKeywords  []string              `yaml:"preferred_keywords"`
+	BannedKeywords     []string              `yaml:"banned_keywords"`
+	PreferredSources   []string              `yaml:"preferred_sources"`
+	PreferredUploaders []string              `yaml:"preferred_uploaders"`
+
+	// Blocklist holds the releases that should never be grabbed
+	Blocklist Blocklist `yaml:"-"`
+}
+
+// Blocklist is the interface of the release blocklists
+type Blocklist interface {
+	IsBlocked(*Torrent) bool
+}
+
+// TorrentScore represents the score of a torrent and the reasons behind it
+type TorrentScore struct {
+	Torrent  *Torrent `json:"torrent"`
+	Score    int      `json:"score"`
+	Rejected bool     `json:"rejected"`
+	Reasons  []string `json:"reasons"`
+}
+
+func (s *TorrentScore) add(points int, format string, args ...any) {
+	s.Score += points
+	s.Reasons = append(s.Reasons, fmt.Sprintf("%+d: ", points)+fmt.Sprintf(format, args...))
+}
+
+func (s *TorrentScore) reject(format string, args ...any) {
+	s.Rejected = true
+	s.Reasons = append(s.Reasons, "rejected: "+fmt.Sprintf(format, args...))
+}
+
+// Score scores a torrent according to the policy and the wanted qualities,
+// the first qualities being the preferred ones, if no qualities are given all
+// the qualities are accepted
+func (p *TorrentPolicy) Score(t *Torrent, qualities []Quality) *TorrentScore {
+	score := &TorrentScore{Torrent: t, Reasons: []string{}}
+
+	if t == nil || t.Result == nil {
+		score.reject("no torrent result")
+		return score
+	}
+
+	if len(qualities) > 0 {
+		i := slices.Index(qualities, t.Quality)
+		if i == -1 {
+			score.reject("quality %q not wanted", t.Quality)
+		} else {
+			score.add((len(qualities)-i)*scoreQualityWeight, "quality %q", t.Quality)
+		}
+	}
+
+	seeders := t.Result.Seeders
+	if seeders < p.MinSeeders {
+		score.reject("%d seeders, at least %d required", seeders, p.MinSeeders)
+	}
+	score.add(min(seeders, scoreMaxSeeders), "%d seeders", seeders)
+
+	if limit, ok := p.SizeLimits[t.Quality]; ok {
+		size := t.Result.Size / (1024 * 1024)
+		switch {
+		case t.Result.Size == 0:
+			score.Reasons = append(score.Reasons, "unknown size")
+		case limit.Min > 0 && size < limit.Min:
+			score.reject("size of %dMB below %dMB", size, limit.Min)
+		case limit.Max > 0 && size > limit.Max:
+			score.reject("size of %dMB above %dMB", size, limit.Max)
+		}
+	}
+
+	if p.Blocklist != nil && p.Blocklist.IsBlocked(t) {
+		score.reject("blocked release")
+	}
+
+	tokens := nameTokens(t.Result.Name)
+	group := ReleaseGroup(t.Result.Name)
+	if group != "" {
+		if containsFold(p.BannedGroups, group) {
+			score.reject("banned release group %q", group)
+		}
+
+		if containsFold(p.PreferredGroups, group) {
+			score.add(scorePreferredGroup, "preferred release group %q", group)
+		}
+	}
+
+	for _, k := range p.BannedKeywords {
+		if hasKeyword(tokens, k) {
+			score.reject("banned keyword %q", k)
+		}
+	}
+
+	for _, k := range p.PreferredKeywords {
+		if hasKeyword(tokens, k) {
+			score.add(scorePreferredKeyword, "preferred keyword %q", k)
+		}
+	}
+
+	if t.Result.Source != "" && containsFold(p.PreferredSources, t.Result.Source) {
+		score.add(scorePreferredSource, "preferred source %q", t.Result.Source)
+	}
+
+	if t.Result.UploadUser != "" && containsFold(p.PreferredUploaders, t.Result.UploadUser) {
+		score.add(scorePreferredUploader, "preferred uploader %q", t.Result.UploadUser)
+	}
+
+	return score
+}
+
+// Rank scores the torrents and sorts them from the best to the worst, the
+// rejected torrents are at the end of the list. The wanted qualities order is
+// always respected, the scores only rank the torrents of the same quality.
+func (p *TorrentPolicy) Rank(torrents []*Torrent, qualities []Quality) []*TorrentScore {
+	scores := make([]*TorrentScore, len(torrents))
+	for i, t := range torrents {
+		scores[i] = p.Score(t, qualities)
+	}
+
+	qualityRank := func(s *TorrentScore) int {
+		if s.Torrent == nil {
+			return -1
+		}
+		return slices.Index(qualities, s.Torrent.Quality)
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Rejected != scores[j].Rejected {
+			return !scores[i].Rejected
+		}
+
+		if ri, rj := qualityRank(scores[i]), qualityRank(scores[j]); ri != rj {
+			return ri < rj
+		}
+
+		return scores[i].Score > scores[j].Score
+	})
+
+	return scores
+}
+
+// Choose returns the best torrent matching the policy and the wanted
+// qualities, nil is returned if all the torrents are rejected
+func (p *TorrentPolicy) Choose(torrents []*Torrent, qualities []Quality) *Torrent {
+	scores := p.Rank(torrents, qualities)
+	if len(scores) == 0 || scores[0].Rejected {
+		return nil
+	}
+
+	return scores[0].Torrent
+}
+
+// ReleaseGroup returns the release group of a torrent name, it's the dash
+// suffix of the last field of the name e.g. "Movie.2019.1080p.BluRay.x264-GROUP"
+func ReleaseGroup(name string) string {
+	name = strings.TrimSpace(name)
+
+	// Remove the trailing tags e.g. "[rarbg]" and the video extension
+	for strings.HasSuffix(name, "]") {
+		i := strings.LastIndex(name, "[")
+		if i == -1 {
+			break
+		}
+		name = strings.TrimSpace(name[:i])
+	}
+	for _, ext := range []string{".mkv", ".mp4", ".avi"} {
+		if strings.HasSuffix(strings.ToLower(name), ext) {
+			name = name[:len(name)-len(ext)]
+		}
+	}
+
+	fields := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '.' || r == ' '
+	})
+	if len(fields) < 2 {
+		return ""
+	}
+
+	last := fields[len(fields)-1]
+
+	// The group is separated by spaces e.g. "Movie 2019 1080p - GROUP"
+	if fields[len(fields)-2] == "-" {
+		return last
+	}
+
+	i := strings.LastIndex(last, "-")
+	if i <= 0 {
+		return ""
+	}
+
+	return last[i+1:]
+}
+
+// nameTokens splits a torrent name into lower case alphanumeric tokens
+func nameTokens(name string) []string {
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// hasKeyword returns true if the tokens of the keyword are found in a row in
+// the tokens, this allows keywords such as "WEB-DL"
+func hasKeyword(tokens []string, keyword string) bool {
+	kt := nameTokens(keyword)
+	if len(kt) == 0 {
+		return false
+	}
+
+	for i := 0; i+len(kt) <= len(tokens); i++ {
+		if slices.Equal(tokens[i:i+len(kt)], kt) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsFold returns true if the value is in the list, case insensitive
+func containsFold(list []string, value string) bool {
+	return slices.ContainsFunc(list, func(s string) bool {
+		return strings.EqualFold(s, value)
+	})
+}
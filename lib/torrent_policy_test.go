@@ -0,0 +1,183 @@
+package polochon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReleaseGroup(t *testing.T) {
+	for name, expected := range map[string]string{
+		"Movie.2019.1080p.BluRay.x264-SPARKS":        "SPARKS",
+		"Movie.2019.1080p.BluRay.x264-SPARKS[rarbg]": "SPARKS",
+		"Movie 2019 1080p - GROUP":                   "GROUP",
+		"Movie (2019) [1080p] [YTS.MX]":              "",
+		"Show.S01E02.720p.HDTV.x264-KILLERS.mkv":     "KILLERS",
+		"Spider-Man.2002.1080p.BluRay.x264":          "",
+		"Movie 2019 1080p WEB-DL x264":               "",
+		"Movie.2019.1080p.BluRay.x264-":              "",
+	} {
+		if got := ReleaseGroup(name); got != expected {
+			t.Errorf("%q: expected %q, got %q", name, expected, got)
+		}
+	}
+}
+
+func TestTorrentPolicyScore(t *testing.T) {
+	policy := &TorrentPolicy{
+		MinSeeders: 5,
+		SizeLimits: map[Quality]SizeLimit{
+			Quality1080p: {Min: 1000, Max: 4000},
+		},
+		PreferredGroups:    []string{"sparks"},
+		BannedGroups:       []string{"BAD"},
+		PreferredKeywords:  []string{"web-dl"},
+		BannedKeywords:     []string{"CAM", "TS"},
+		PreferredSources:   []string{"yts"},
+		PreferredUploaders: []string{"bob"},
+	}
+	qualities := []Quality{Quality1080p, Quality720p}
+	gb := 1024 * 1024 * 1024
+
+	tt := []struct {
+		name     string
+		torrent  *Torrent
+		score    int
+		rejected bool
+		reasons  []string
+	}{
+		{
+			name:     "no result",
+			torrent:  &Torrent{Quality: Quality1080p},
+			rejected: true,
+			reasons:  []string{"rejected: no torrent result"},
+		},
+		{
+			name: "preferred everything",
+			torrent: &Torrent{
+				Quality: Quality1080p,
+				Result: &TorrentResult{
+					Name:       "Movie.2019.1080p.WEB-DL.x264-SPARKS",
+					Seeders:    300,
+					Size:       2 * gb,
+					Source:     "YTS",
+					UploadUser: "bob",
+				},
+			},
+			score: 2000 + 100 + 200 + 100 + 50 + 50,
+			reasons: []string{
+				`+2000: quality "1080p"`,
+				"+100: 300 seeders",
+				`+200: preferred release group "SPARKS"`,
+				`+100: preferred keyword "web-dl"`,
+				`+50: preferred source "YTS"`,
+				`+50: preferred uploader "bob"`,
+			},
+		},
+		{
+			name: "unwanted quality and too few seeders",
+			torrent: &Torrent{
+				Quality: Quality480p,
+				Result:  &TorrentResult{Name: "Movie.2019.480p", Seeders: 2},
+			},
+			score:    2,
+			rejected: true,
+			reasons: []string{
+				`rejected: quality "480p" not wanted`,
+				"rejected: 2 seeders, at least 5 required",
+				"+2: 2 seeders",
+			},
+		},
+		{
+			name: "banned keyword, group and size",
+			torrent: &Torrent{
+				Quality: Quality1080p,
+				Result:  &TorrentResult{Name: "Movie.2019.1080p.TS-BAD", Seeders: 10, Size: 5 * gb},
+			},
+			score:    2010,
+			rejected: true,
+			reasons: []string{
+				`+2000: quality "1080p"`,
+				"+10: 10 seeders",
+				"rejected: size of 5120MB above 4000MB",
+				`rejected: banned release group "BAD"`,
+				`rejected: banned keyword "TS"`,
+			},
+		},
+		{
+			name: "keywords are matched on whole words",
+			torrent: &Torrent{
+				Quality: Quality720p,
+				Result:  &TorrentResult{Name: "Movie.2019.720p.TSX.CAMERA", Seeders: 10},
+			},
+			score:   1010,
+			reasons: []string{`+1000: quality "720p"`, "+10: 10 seeders"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := policy.Score(tc.torrent, qualities)
+			if got.Score != tc.score || got.Rejected != tc.rejected {
+				t.Errorf("expected score %d (rejected %t), got %d (rejected %t)", tc.score, tc.rejected, got.Score, got.Rejected)
+			}
+
+			if !reflect.DeepEqual(got.Reasons, tc.reasons) {
+				t.Errorf("expected reasons %q, got %q", tc.reasons, got.Reasons)
+			}
+		})
+	}
+}
+
+func TestTorrentPolicyChoose(t *testing.T) {
+	t1 := &Torrent{Quality: Quality720p, Result: &TorrentResult{Name: "Movie.720p-GRP", Seeders: 500}}
+	t2 := &Torrent{Quality: Quality1080p, Result: &TorrentResult{Name: "Movie.1080p-GRP", Seeders: 20}}
+	t3 := &Torrent{Quality: Quality1080p, Result: &TorrentResult{Name: "Movie.1080p.CAM-GRP", Seeders: 80}}
+	t4 := &Torrent{Quality: Quality1080p, Result: &TorrentResult{Name: "Movie.1080p-GRP", Seeders: 40}}
+
+	policy := &TorrentPolicy{BannedKeywords: []string{"cam"}}
+
+	got := policy.Choose([]*Torrent{t1, t2, t3, t4}, []Quality{Quality1080p, Quality720p})
+	if got != t4 {
+		t.Errorf("expected %+v, got %+v", t4, got)
+	}
+
+	ranked := policy.Rank([]*Torrent{t1, t2, t3, t4}, []Quality{Quality1080p, Quality720p})
+	order := []*Torrent{}
+	for _, s := range ranked {
+		order = append(order, s.Torrent)
+	}
+	if expected := []*Torrent{t4, t2, t1, t3}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %+v, got %+v", expected, order)
+	}
+
+	if got := policy.Choose([]*Torrent{t3}, nil); got != nil {
+		t.Errorf("expected no torrent, got %+v", got)
+	}
+}
+
+func TestTorrentPolicyChooseQualityFirst(t *testing.T) {
+	policy := &TorrentPolicy{
+		PreferredGroups:    []string{"grp"},
+		PreferredKeywords:  []string{"web-dl", "hdr", "atmos", "remux", "proper", "repack", "10bit"},
+		PreferredSources:   []string{"yts"},
+		PreferredUploaders: []string{"bob"},
+	}
+
+	// The bonuses of the 720p torrent are higher than the quality weight
+	t1 := &Torrent{Quality: Quality720p, Result: &TorrentResult{
+		Name:       "Movie.720p.WEB-DL.HDR.Atmos.REMUX.PROPER.REPACK.10bit-GRP",
+		Seeders:    500,
+		Source:     "yts",
+		UploadUser: "bob",
+	}}
+	t2 := &Torrent{Quality: Quality1080p, Result: &TorrentResult{Name: "Movie.1080p-OTHER", Seeders: 1}}
+
+	qualities := []Quality{Quality1080p, Quality720p}
+	if s1, s2 := policy.Score(t1, qualities), policy.Score(t2, qualities); s1.Score <= s2.Score {
+		t.Fatalf("expected the 720p score %d to be above the 1080p score %d", s1.Score, s2.Score)
+	}
+
+	if got := policy.Choose([]*Torrent{t1, t2}, qualities); got != t2 {
+		t.Errorf("expected %+v, got %+v", t2, got)
+	}
+}
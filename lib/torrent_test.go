@@ -58,50 +58,6 @@ func TestTorrentRatio(t *testing.T) {
 	}
 }
 
-func TestFilterTorrents(t *testing.T) {
-	t1 := &Torrent{Quality: Quality720p, Result: &TorrentResult{Seeders: 2}}
-	t2 := &Torrent{Quality: Quality1080p, Result: &TorrentResult{Seeders: 200}}
-	t3 := &Torrent{Quality: Quality1080p, Result: &TorrentResult{Seeders: 100}}
-	t4 := &Torrent{Quality: Quality720p, Result: &TorrentResult{Seeders: 50}}
-	t5 := &Torrent{Quality: Quality720p}
-	input := []*Torrent{t1, t2, t3, t4, t5}
-
-	expected := []*Torrent{t2, t4}
-
-	got := FilterTorrents(input)
-	if !reflect.DeepEqual(got, expected) {
-		t.Fatalf("expected %#v, got %#v", expected, got)
-	}
-}
-
-func TestChooseTorrentFromQualities(t *testing.T) {
-	t1 := &Torrent{Quality: Quality3D}
-	t2 := &Torrent{Quality: Quality480p}
-	t3 := &Torrent{Quality: Quality720p}
-	t4 := &Torrent{Quality: Quality1080p}
-	t5 := &Torrent{Quality: Quality1080p}
-	input := []*Torrent{t1, t2, t3, t4, t5}
-
-	expected := t4
-
-	got := ChooseTorrentFromQualities(input, []Quality{Quality1080p, Quality720p})
-	if !reflect.DeepEqual(got, expected) {
-		t.Fatalf("expected %#v, got %#v", expected, got)
-	}
-}
-
-func TestChooseTorrentFromQualitiesNotFound(t *testing.T) {
-	input := []*Torrent{
-		{Quality: Quality3D},
-		{Quality: Quality480p},
-	}
-
-	got := ChooseTorrentFromQualities(input, []Quality{Quality1080p, Quality720p})
-	if got != nil {
-		t.Fatalf("expected no result, got %#v", got)
-	}
-}
-
 func TestHasVideo(t *testing.T) {
 	// Valid movie
 	validMovieTorrent := &Torrent{
@@ -194,3 +150,47 @@ func TestHasVideo(t *testing.T) {
 		})
 	}
 }
+
+func TestSeasonPack(t *testing.T) {
+	pack := &Torrent{
+		ImdbID:  "tt000000",
+		Type:    "episode",
+		Quality: Quality720p,
+		Season:  2,
+	}
+
+	for _, tc := range []struct {
+		name     string
+		torrent  *Torrent
+		expected bool
+	}{
+		{name: "season pack", torrent: pack, expected: true},
+		{name: "movie", torrent: &Torrent{ImdbID: "tt000000", Type: "movie"}},
+		{name: "episode", torrent: &Torrent{ImdbID: "tt000000", Type: "episode", Season: 2, Episode: 1}},
+		{name: "missing season", torrent: &Torrent{ImdbID: "tt000000", Type: "episode"}},
+		{name: "missing imdb id", torrent: &Torrent{Type: "episode", Season: 2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.torrent.IsSeasonPack(); got != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+
+	if got := pack.EpisodeVideo(0); got != nil {
+		t.Fatalf("expected no video, got %+v", got)
+	}
+
+	got, ok := pack.EpisodeVideo(5).(*ShowEpisode)
+	if !ok {
+		t.Fatalf("expected an episode, got %+v", got)
+	}
+
+	if got.ShowImdbID != pack.ImdbID || got.Season != 2 || got.Episode != 5 || got.Quality != Quality720p {
+		t.Fatalf("invalid episode %+v", got)
+	}
+
+	if pack.Episode != 0 {
+		t.Fatal("the season pack should not be modified")
+	}
+}
@@ -23,16 +23,28 @@ type Torrentable interface {
 	GetTorrenters() []Torrenter
 }
 
-// GetTorrents helps getting the torrent files for a movie
+// GetTorrents helps getting the torrent files for a movie, ErrTorrentNotFound
+// is returned if a torrenter found nothing and the error of the last torrenter
+// is returned if they all failed
 func GetTorrents(v Torrentable, log *logrus.Entry) error {
+	var lastErr error
 	for _, t := range v.GetTorrenters() {
 		torrenterLog := log.WithField("torrenter", t.Name())
 		err := t.GetTorrents(v, torrenterLog)
-		if err == nil {
+		switch {
+		case err == nil:
 			// Torrents found
 			return nil
+		case errors.Is(err, ErrTorrentNotFound):
+			lastErr = ErrTorrentNotFound
+		case lastErr != ErrTorrentNotFound:
+			lastErr = err
 		}
 	}
 
-	return ErrTorrentNotFound
+	if lastErr == nil {
+		return ErrTorrentNotFound
+	}
+
+	return lastErr
 }
@@ -0,0 +1,55 @@
+package polochon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fakeTorrenter is a torrenter returning the same error for every video
+type fakeTorrenter struct {
+	testModule
+	err error
+}
+
+func (f *fakeTorrenter) GetTorrents(any, *logrus.Entry) error {
+	return f.err
+}
+
+func (f *fakeTorrenter) SearchTorrents(string) ([]*Torrent, error) {
+	return nil, f.err
+}
+
+// fakeTorrentable is a torrentable using the given torrenters
+type fakeTorrentable []Torrenter
+
+func (f fakeTorrentable) GetTorrenters() []Torrenter {
+	return f
+}
+
+func TestGetTorrents(t *testing.T) {
+	errDown := errors.New("torrenter is down")
+	found := &fakeTorrenter{}
+	notFound := &fakeTorrenter{err: ErrTorrentNotFound}
+	down := &fakeTorrenter{err: errDown}
+
+	for _, tc := range []struct {
+		name       string
+		torrenters fakeTorrentable
+		expected   error
+	}{
+		{name: "no torrenter", expected: ErrTorrentNotFound},
+		{name: "found", torrenters: fakeTorrentable{down, found}, expected: nil},
+		{name: "not found", torrenters: fakeTorrentable{notFound}, expected: ErrTorrentNotFound},
+		{name: "not found and down", torrenters: fakeTorrentable{notFound, down}, expected: ErrTorrentNotFound},
+		{name: "down and not found", torrenters: fakeTorrentable{down, notFound}, expected: ErrTorrentNotFound},
+		{name: "down", torrenters: fakeTorrentable{down}, expected: errDown},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := GetTorrents(tc.torrenters, mockLogEntry); err != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, err)
+			}
+		})
+	}
+}
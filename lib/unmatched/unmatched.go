@@ -0,0 +1,249 @@
+package unmatched
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+// ErrNotFound is returned when an entry is not in the queue
+var ErrNotFound = errors.New("unmatched: not found")
+
+// Reason represents the reason why a file could not be organized
+type Reason string
+
+// Possible reasons
+const (
+	// ReasonGuessFailed is used when the guessers could not identify the file
+	ReasonGuessFailed Reason = "guess_failed"
+	// ReasonNoDetails is used when the detailers found no details for the
+	// guessed video
+	ReasonNoDetails Reason = "no_details"
+	// ReasonLibraryError is used when the video could not be added to the
+	// library
+	ReasonLibraryError Reason = "library_error"
+)
+
+// Config represents the configuration of the unmatched queue
+type Config struct {
+	Path string `yaml:"path"`
+}
+
+// Guess represents the output of the guessers for a file
+type Guess struct {
+	Type       polochon.VideoType      `json:"type"`
+	ImdbID     string                  `json:"imdb_id,omitempty"`
+	Title      string                  `json:"title,omitempty"`
+	Year       int                     `json:"year,omitempty"`
+	ShowImdbID string                  `json:"show_imdb_id,omitempty"`
+	ShowTitle  string                  `json:"show_title,omitempty"`
+	Season     int                     `json:"season,omitempty"`
+	Episode    int                     `json:"episode,omitempty"`
+	Metadata   *polochon.VideoMetadata `json:"metadata,omitempty"`
+}
+
+// NewGuess returns the guess of a video, nil is returned if there is no video
+func NewGuess(v polochon.Video) *Guess {
+	switch v := v.(type) {
+	case *polochon.Movie:
+		return &Guess{
+			Type:     polochon.TypeMovie,
+			ImdbID:   v.ImdbID,
+			Title:    v.Title,
+			Year:     v.Year,
+			Metadata: v.GetMetadata(),
+		}
+	case *polochon.ShowEpisode:
+		return &Guess{
+			Type:       polochon.TypeEpisode,
+			Title:      v.Title,
+			ShowImdbID: v.ShowImdbID,
+			ShowTitle:  v.ShowTitle,
+			Season:     v.Season,
+			Episode:    v.Episode,
+			Metadata:   v.GetMetadata(),
+		}
+	default:
+		return nil
+	}
+}
+
+// Entry represents a file that could not be organized
+type Entry struct {
+	ID        uint64    `json:"id"`
+	Path      string    `json:"path"`
+	Reason    Reason    `json:"reason"`
+	Error     string    `json:"error,omitempty"`
+	Guess     *Guess    `json:"guess,omitempty"`
+	Attempts  int       `json:"attempts"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// data represents the content of the queue file
+type data struct {
+	LastID  uint64   `json:"last_id"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Store keeps the queue of the unmatched files, the queue is only kept in
+// memory if no path is configured
+type Store struct {
+	Config
+	// now is overwritten during the tests
+	now func() time.Time
+
+	// Mutex to protect the data, the store is shared between the apps
+	mu   sync.Mutex
+	data *data
+}
+
+// Open opens the queue file, a missing file is an empty queue
+func Open(config Config) (*Store, error) {
+	s := &Store{
+		Config: config,
+		now:    time.Now,
+		data:   &data{Entries: []*Entry{}},
+	}
+
+	if config.Path == "" {
+		return s, nil
+	}
+
+	file, err := os.Open(config.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := json.NewDecoder(file).Decode(s.data); err != nil {
+		return nil, fmt.Errorf("unmatched: invalid queue file: %w", err)
+	}
+
+	return s, nil
+}
+
+// Add adds a file to the queue, the entry of the file is updated if it's
+// already in the queue
+func (s *Store) Add(path string, reason Reason, err error, guess *Guess) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	e := s.find(path)
+	if e == nil {
+		s.data.LastID++
+		e = &Entry{
+			ID:        s.data.LastID,
+			Path:      path,
+			CreatedAt: now,
+		}
+		s.data.Entries = append(s.data.Entries, e)
+	}
+
+	e.Reason = reason
+	e.Error = ""
+	if err != nil {
+		e.Error = err.Error()
+	}
+	e.Guess = guess
+	e.Attempts++
+	e.UpdatedAt = now
+
+	cp := *e
+	return &cp, s.write()
+}
+
+// Entries returns a copy of the entries of the queue, the oldest first
+func (s *Store) Entries() []*Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]*Entry, len(s.data.Entries))
+	for i, e := range s.data.Entries {
+		cp := *e
+		entries[i] = &cp
+	}
+
+	return entries
+}
+
+// Get returns a copy of an entry from its id
+func (s *Store) Get(id uint64) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.data.Entries {
+		if e.ID == id {
+			cp := *e
+			return &cp, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+// Has returns true if the file is in the queue
+func (s *Store) Has(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.find(path) != nil
+}
+
+// Remove removes an entry from the queue
+func (s *Store) Remove(id uint64) error {
+	return s.remove(func(e *Entry) bool { return e.ID == id })
+}
+
+// RemovePath removes the entry of a file from the queue, nothing is done if
+// the file is not in the queue
+func (s *Store) RemovePath(path string) error {
+	err := s.remove(func(e *Entry) bool { return e.Path == path })
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *Store) remove(match func(*Entry) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := len(s.data.Entries)
+	s.data.Entries = slices.DeleteFunc(s.data.Entries, match)
+	if len(s.data.Entries) == l {
+		return ErrNotFound
+	}
+
+	return s.write()
+}
+
+func (s *Store) find(path string) *Entry {
+	for _, e := range s.data.Entries {
+		if e.Path == path {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// write saves the queue, nothing is saved without a path
+func (s *Store) write() error {
+	if s.Path == "" {
+		return nil
+	}
+
+	return polochon.WriteJSONFile(s.Path, s.data)
+}
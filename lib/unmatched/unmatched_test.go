@@ -0,0 +1,110 @@
+package unmatched
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	polochon "github.com/odwrtw/polochon/lib"
+)
+
+var testTime = time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestStore(t *testing.T, path string) *Store {
+	s, err := Open(Config{Path: path})
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	s.now = func() time.Time { return testTime }
+	return s
+}
+
+func TestQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "unmatched.json")
+	s := newTestStore(t, path)
+
+	guess := NewGuess(&polochon.ShowEpisode{ShowTitle: "Show", Season: 1, Episode: 2})
+	e, err := s.Add("/downloads/show.s01e02.mp4", ReasonNoDetails, polochon.ErrGettingDetails, guess)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if _, err := s.Add("/downloads/movie.mp4", ReasonGuessFailed, nil, nil); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	// The same file updates its entry
+	e2, err := s.Add("/downloads/show.s01e02.mp4", ReasonLibraryError, errors.New("yo"), guess)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expected := &Entry{
+		ID:     1,
+		Path:   "/downloads/show.s01e02.mp4",
+		Reason: ReasonLibraryError,
+		Error:  "yo",
+		Guess: &Guess{
+			Type:      polochon.TypeEpisode,
+			ShowTitle: "Show",
+			Season:    1,
+			Episode:   2,
+			Metadata:  &polochon.VideoMetadata{},
+		},
+		Attempts:  2,
+		CreatedAt: testTime,
+		UpdatedAt: testTime,
+	}
+
+	if e.ID != e2.ID || !reflect.DeepEqual(e2, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, e2)
+	}
+
+	// The queue is persisted
+	s = newTestStore(t, path)
+	if got := s.Entries(); len(got) != 2 || !reflect.DeepEqual(got[0], expected) {
+		t.Fatalf("unexpected entries %+v", got)
+	}
+
+	if !s.Has("/downloads/movie.mp4") {
+		t.Fatal("the movie should be in the queue")
+	}
+
+	if err := s.RemovePath("/downloads/movie.mp4"); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := s.RemovePath("/downloads/movie.mp4"); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := s.Remove(2); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected error %q, got %v", ErrNotFound, err)
+	}
+
+	if _, err := s.Get(1); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := s.Remove(1); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if got := s.Entries(); len(got) != 0 {
+		t.Fatalf("expected an empty queue, got %+v", got)
+	}
+}
+
+func TestMemoryQueue(t *testing.T) {
+	s := newTestStore(t, "")
+	if _, err := s.Add("/downloads/movie.mp4", ReasonGuessFailed, nil, nil); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if len(s.Entries()) != 1 {
+		t.Fatal("the entry should be kept in memory")
+	}
+}
@@ -1,5 +1,7 @@
 package polochon
 
+import "fmt"
+
 // VideoType represents the type of a video
 type VideoType string
 
@@ -19,6 +21,7 @@ type Video interface {
 	GetFile() *File
 
 	SetMetadata(*VideoMetadata)
+	GetMetadata() *VideoMetadata
 
 	SetSubtitles([]*Subtitle)
 	GetSubtitles() []*Subtitle
@@ -50,6 +53,11 @@ func (bv *BaseVideo) SetMetadata(metadata *VideoMetadata) {
 	bv.Update(metadata)
 }
 
+// GetMetadata implements the Video interface
+func (bv *BaseVideo) GetMetadata() *VideoMetadata {
+	return &bv.VideoMetadata
+}
+
 // SetSubtitles implements the Video interface
 func (bv *BaseVideo) SetSubtitles(subtitles []*Subtitle) {
 	bv.Subtitles = subtitles
@@ -69,3 +77,13 @@ func (bv *BaseVideo) SetTorrents(torrents []*Torrent) {
 func (bv *BaseVideo) GetTorrents() []*Torrent {
 	return bv.Torrents
 }
+
+// VideoKey returns the key identifying a video in the stores, the IMDb ID of
+// the movie or the IMDb ID of the show followed by the episode number
+func VideoKey(imdbID string, season, episode int) string {
+	if season == 0 && episode == 0 {
+		return imdbID
+	}
+
+	return fmt.Sprintf("%s-S%02dE%02d", imdbID, season, episode)
+}
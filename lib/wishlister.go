@@ -1,9 +1,17 @@
 package polochon
 
 import (
+	"errors"
+	"slices"
+
 	"github.com/sirupsen/logrus"
 )
 
+// Wishlist errors
+var (
+	ErrNotInWishlist = errors.New("polochon: video not in the wishlist")
+)
+
 // Wishlister is an interface which defines the behavior of the wishlister
 // modules
 type Wishlister interface {
@@ -12,19 +20,50 @@ type Wishlister interface {
 	GetShowWishlist(*logrus.Entry) ([]*WishedShow, error)
 }
 
-// WishedMovie represents a wished movie and its expected qualities
+// WishlistEditor is the interface of the wishlisters whose wishlist can be
+// edited
+type WishlistEditor interface {
+	Wishlister
+	AddMovie(*WishedMovie, *logrus.Entry) error
+	AddShow(*WishedShow, *logrus.Entry) error
+	RemoveMovie(imdbID string, log *logrus.Entry) error
+	RemoveShow(imdbID string, log *logrus.Entry) error
+}
+
+// WishedMovie represents a wished movie, its expected qualities and the
+// wishlisters it comes from
 type WishedMovie struct {
-	ImdbID    string    `json:"imdb_id"`
-	Qualities []Quality `json:"qualities"`
+	ImdbID      string    `json:"imdb_id"`
+	Qualities   []Quality `json:"qualities"`
+	Wishlisters []string  `json:"wishlisters,omitempty"`
 }
 
-// WishedShow represents a wished show, its expected qualities and the season /
-// episode to start tracking
+// WishedShow represents a wished show, its expected qualities, the season /
+// episode to start tracking and the wishlisters it comes from
 type WishedShow struct {
-	ImdbID    string    `json:"imdb_id"`
-	Season    int       `json:"season_from"`
-	Episode   int       `json:"episode_from"`
-	Qualities []Quality `json:"qualities"`
+	ImdbID      string    `json:"imdb_id"`
+	Season      int       `json:"season_from"`
+	Episode     int       `json:"episode_from"`
+	Qualities   []Quality `json:"qualities"`
+	Wishlisters []string  `json:"wishlisters,omitempty"`
+	// Specials tells if the specials of the show (season 0) are wanted
+	Specials bool `json:"specials,omitempty"`
+	// Daily tells if the show is released by air date, only the episodes
+	// aired during the last DailyDays days are wanted
+	Daily     bool `json:"daily,omitempty"`
+	DailyDays int  `json:"daily_days,omitempty"`
+}
+
+// DefaultDailyDays is the number of days of a daily show wanted by default
+const DefaultDailyDays = 7
+
+// WantedDays returns the number of days of a daily show that are wanted
+func (ws *WishedShow) WantedDays() int {
+	if ws.DailyDays > 0 {
+		return ws.DailyDays
+	}
+
+	return DefaultDailyDays
 }
 
 // WishlistConfig represents the wishlist configurations
@@ -50,8 +89,9 @@ func NewWishlist(wishlistConfig WishlistConfig, log *logrus.Entry) *Wishlist {
 	}
 }
 
-// Fetch the informations from the wishlister an returns a merged wishlist
-// TODO: merge the wishlists from the different wishlisters
+// Fetch the informations from the wishlisters and merges them, the wishlisters
+// are merged in the configured order so that the qualities of the first
+// wishlisters have a higher priority
 func (w *Wishlist) Fetch() error {
 	// Movie wishlists
 	if err := w.fetchMovies(); err != nil {
@@ -71,7 +111,14 @@ func (w *Wishlist) fetchMovies() error {
 		}
 
 		for _, m := range movieWishlist {
-			if err := w.AddMovie(m); err != nil {
+			wished := *m
+			wished.Wishlisters = appendUnique(slices.Clone(m.Wishlisters), wl.Name())
+			// Use the default qualities before merging, an entry without
+			// qualities wants the default ones even if another entry has some
+			if len(wished.Qualities) == 0 {
+				wished.Qualities = w.MovieDefaultQualities
+			}
+			if err := w.AddMovie(&wished); err != nil {
 				return err
 			}
 		}
@@ -91,7 +138,14 @@ func (w *Wishlist) fetchShows() error {
 		}
 
 		for _, s := range showWishlist {
-			if err := w.AddShow(s); err != nil {
+			wished := *s
+			wished.Wishlisters = appendUnique(slices.Clone(s.Wishlisters), wl.Name())
+			// Use the default qualities before merging, an entry without
+			// qualities wants the default ones even if another entry has some
+			if len(wished.Qualities) == 0 {
+				wished.Qualities = w.ShowDefaultQualities
+			}
+			if err := w.AddShow(&wished); err != nil {
 				return err
 			}
 		}
@@ -132,7 +186,7 @@ func (w *Wishlist) setDefaultShowQualities() {
 }
 
 // AddMovie helps add a movie to the movie list, if the movie is already in the
-// list, nothing happens
+// list, the qualities and the wishlisters are merged
 func (w *Wishlist) AddMovie(movie *WishedMovie) error {
 	// Create an empty slice if there is no movies
 	if w.Movies == nil {
@@ -142,19 +196,24 @@ func (w *Wishlist) AddMovie(movie *WishedMovie) error {
 	// Check if the movie as already been added
 	for _, m := range w.Movies {
 		if movie.ImdbID == m.ImdbID {
+			m.Qualities = appendUnique(m.Qualities, movie.Qualities...)
+			m.Wishlisters = appendUnique(m.Wishlisters, movie.Wishlisters...)
 			return nil
 		}
 	}
 
 	w.Movies = append(w.Movies, &WishedMovie{
-		ImdbID: movie.ImdbID,
+		ImdbID:      movie.ImdbID,
+		Qualities:   slices.Clone(movie.Qualities),
+		Wishlisters: slices.Clone(movie.Wishlisters),
 	})
 
 	return nil
 }
 
 // AddShow adds a show to the show list, if the show is already in the list,
-// the oldest is kept or added
+// the earliest season / episode is kept and the qualities, the wishlisters and
+// the specials and daily tracking are merged
 func (w *Wishlist) AddShow(show *WishedShow) error {
 	// Create an empty slice if there is no shows
 	if w.Shows == nil {
@@ -167,21 +226,20 @@ func (w *Wishlist) AddShow(show *WishedShow) error {
 			continue
 		}
 
-		// Do not treat empty data as valid data
-		if show.Episode == 0 && show.Season == 0 {
-			return nil
-		}
+		s.Qualities = appendUnique(s.Qualities, show.Qualities...)
+		s.Wishlisters = appendUnique(s.Wishlisters, show.Wishlisters...)
 
-		// If the show added and the current show have the same season number
-		if show.Season == s.Season {
-			// Older show is better
-			if show.Episode < s.Episode {
-				s.Episode = show.Episode
-			}
-		}
+		// The specials are wanted if any wishlister wants them
+		s.Specials = s.Specials || show.Specials
+
+		// The show is daily if any wishlister says so, the most days
+		// are kept
+		s.Daily = s.Daily || show.Daily
+		s.DailyDays = max(s.DailyDays, show.DailyDays)
 
-		// Older season is better
-		if show.Season < s.Season {
+		// The earliest start is kept, no season and episode means the show
+		// is wanted from the start
+		if show.Season < s.Season || (show.Season == s.Season && show.Episode < s.Episode) {
 			s.Season = show.Season
 			s.Episode = show.Episode
 		}
@@ -192,10 +250,27 @@ func (w *Wishlist) AddShow(show *WishedShow) error {
 
 	// Nothing found let's add it
 	w.Shows = append(w.Shows, &WishedShow{
-		ImdbID:  show.ImdbID,
-		Season:  show.Season,
-		Episode: show.Episode,
+		ImdbID:      show.ImdbID,
+		Season:      show.Season,
+		Episode:     show.Episode,
+		Qualities:   slices.Clone(show.Qualities),
+		Wishlisters: slices.Clone(show.Wishlisters),
+		Specials:    show.Specials,
+		Daily:       show.Daily,
+		DailyDays:   show.DailyDays,
 	})
 
 	return nil
 }
+
+// appendUnique appends the values to the slice if they're not already in it,
+// the order of the values is kept
+func appendUnique[T comparable](s []T, values ...T) []T {
+	for _, v := range values {
+		if !slices.Contains(s, v) {
+			s = append(s, v)
+		}
+	}
+
+	return s
+}
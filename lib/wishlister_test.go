@@ -13,22 +13,22 @@ var fakeWishedShows = []*WishedShow{
 	{ImdbID: "show2", Season: 1, Episode: 4},
 	{ImdbID: "show2", Season: 1, Episode: 5},
 	{ImdbID: "show2", Season: 1, Episode: 2},
-	{ImdbID: "show2", Season: 3, Episode: 5},
-	{ImdbID: "show3", Season: 4, Episode: 5},
+	{ImdbID: "show2", Season: 3, Episode: 5, Specials: true},
+	{ImdbID: "show3", Season: 4, Episode: 5, Daily: true, DailyDays: 3},
 	{ImdbID: "show1", Season: 1, Episode: 1},
 	{ImdbID: "show1", Season: 0, Episode: 0},
 }
 
 var expectedWishedShows = []*WishedShow{
-	{ImdbID: "show1", Season: 1, Episode: 1},
-	{ImdbID: "show2", Season: 1, Episode: 2},
-	{ImdbID: "show3", Season: 4, Episode: 5},
+	{ImdbID: "show1", Season: 0, Episode: 0},
+	{ImdbID: "show2", Season: 1, Episode: 2, Specials: true},
+	{ImdbID: "show3", Season: 4, Episode: 5, Daily: true, DailyDays: 3},
 }
 
 var expectedWishedShowsWithQualities = []*WishedShow{
-	{ImdbID: "show1", Season: 1, Episode: 1, Qualities: []Quality{Quality480p, Quality1080p}},
-	{ImdbID: "show2", Season: 1, Episode: 2, Qualities: []Quality{Quality480p, Quality1080p}},
-	{ImdbID: "show3", Season: 4, Episode: 5, Qualities: []Quality{Quality480p, Quality1080p}},
+	{ImdbID: "show1", Season: 0, Episode: 0, Qualities: []Quality{Quality480p, Quality1080p}, Wishlisters: []string{"fake"}},
+	{ImdbID: "show2", Season: 1, Episode: 2, Qualities: []Quality{Quality480p, Quality1080p}, Wishlisters: []string{"fake"}, Specials: true},
+	{ImdbID: "show3", Season: 4, Episode: 5, Qualities: []Quality{Quality480p, Quality1080p}, Wishlisters: []string{"fake"}, Daily: true, DailyDays: 3},
 }
 
 // Fake shows
@@ -46,9 +46,9 @@ var expectedWishedMovies = []*WishedMovie{
 }
 
 var expectedWishedMoviesWithQualities = []*WishedMovie{
-	{ImdbID: "movie1", Qualities: []Quality{Quality1080p, Quality720p}},
-	{ImdbID: "movie2", Qualities: []Quality{Quality1080p, Quality720p}},
-	{ImdbID: "movie3", Qualities: []Quality{Quality1080p, Quality720p}},
+	{ImdbID: "movie1", Qualities: []Quality{Quality1080p, Quality720p}, Wishlisters: []string{"fake"}},
+	{ImdbID: "movie2", Qualities: []Quality{Quality1080p, Quality720p}, Wishlisters: []string{"fake"}},
+	{ImdbID: "movie3", Qualities: []Quality{Quality1080p, Quality720p}, Wishlisters: []string{"fake"}},
 }
 
 // Fake wishlister
@@ -74,6 +74,26 @@ func (fw *FakeWishlister) GetShowWishlist(log *logrus.Entry) ([]*WishedShow, err
 	return fakeWishedShows, nil
 }
 
+// Named wishlister with custom wishlists
+type namedWishlister struct {
+	FakeWishlister
+	name   string
+	movies []*WishedMovie
+	shows  []*WishedShow
+}
+
+func (nw *namedWishlister) Name() string {
+	return nw.name
+}
+
+func (nw *namedWishlister) GetMovieWishlist(log *logrus.Entry) ([]*WishedMovie, error) {
+	return nw.movies, nil
+}
+
+func (nw *namedWishlister) GetShowWishlist(log *logrus.Entry) ([]*WishedShow, error) {
+	return nw.shows, nil
+}
+
 func NewFakeWishlister(params []byte, log *logrus.Entry) (Wishlister, error) {
 	return &FakeWishlister{}, nil
 }
@@ -135,3 +155,69 @@ func TestFetchWishlist(t *testing.T) {
 		t.Errorf("Expected %#v, got %#v", expectedWishedShows, shows)
 	}
 }
+
+func TestFetchMergedWishlists(t *testing.T) {
+	log := logrus.NewEntry(logrus.New())
+
+	conf := WishlistConfig{
+		Wishlisters: []Wishlister{
+			&namedWishlister{
+				name: "first",
+				movies: []*WishedMovie{
+					{ImdbID: "movie1", Qualities: []Quality{Quality1080p}},
+					{ImdbID: "movie2"},
+				},
+				shows: []*WishedShow{
+					{ImdbID: "show1", Season: 3, Episode: 2, Qualities: []Quality{Quality720p}},
+				},
+			},
+			&namedWishlister{
+				name: "second",
+				movies: []*WishedMovie{
+					{ImdbID: "movie1", Qualities: []Quality{Quality720p, Quality1080p}},
+				},
+				shows: []*WishedShow{
+					{ImdbID: "show1", Season: 2, Episode: 8, Qualities: []Quality{Quality1080p, Quality720p}},
+					{ImdbID: "show2"},
+				},
+			},
+			&namedWishlister{
+				name: "third",
+				movies: []*WishedMovie{
+					{ImdbID: "movie1"},
+				},
+				shows: []*WishedShow{
+					{ImdbID: "show2", Season: 3, Episode: 1},
+				},
+			},
+		},
+		ShowDefaultQualities:  []Quality{Quality480p},
+		MovieDefaultQualities: []Quality{Quality2160p},
+	}
+
+	wl := NewWishlist(conf, log)
+	if err := wl.Fetch(); err != nil {
+		t.Fatalf("Expected no error, got %q", err)
+	}
+
+	expectedMovies := []*WishedMovie{
+		{ImdbID: "movie1", Qualities: []Quality{Quality1080p, Quality720p, Quality2160p}, Wishlisters: []string{"first", "second", "third"}},
+		{ImdbID: "movie2", Qualities: []Quality{Quality2160p}, Wishlisters: []string{"first"}},
+	}
+	if !reflect.DeepEqual(wl.Movies, expectedMovies) {
+		t.Errorf("Expected %#v, got %#v", expectedMovies, wl.Movies)
+	}
+
+	expectedShows := []*WishedShow{
+		{ImdbID: "show1", Season: 2, Episode: 8, Qualities: []Quality{Quality720p, Quality1080p}, Wishlisters: []string{"first", "second"}},
+		{ImdbID: "show2", Qualities: []Quality{Quality480p}, Wishlisters: []string{"second", "third"}},
+	}
+	if !reflect.DeepEqual(wl.Shows, expectedShows) {
+		t.Errorf("Expected %#v, got %#v", expectedShows, wl.Shows)
+	}
+
+	// The wishlists of the wishlisters should not be modified
+	if got := conf.Wishlisters[0].(*namedWishlister).movies[0]; len(got.Qualities) != 1 || got.Wishlisters != nil {
+		t.Errorf("Expected the wishlister data to be untouched, got %#v", got)
+	}
+}
@@ -47,17 +47,56 @@ var eztvGetEpisode = func(imdbID string, season, episode int) ([]*eztv.EpisodeTo
 	return eztv.GetEpisodeTorrents(imdbID, season, episode)
 }
 
+// Function to be overwritten during the tests
+var eztvGetShow = func(imdbID string) ([]*eztv.EpisodeTorrent, error) {
+	return eztv.GetShowTorrents(imdbID)
+}
+
+// getDailyEpisodeTorrents returns the torrents of an episode of a daily show,
+// the torrents are found from the aired date in their names
+func getDailyEpisodeTorrents(s *polochon.ShowEpisode) ([]*eztv.EpisodeTorrent, error) {
+	showTorrents, err := eztvGetShow(s.ShowImdbID)
+	if err != nil {
+		return nil, err
+	}
+
+	torrents := []*eztv.EpisodeTorrent{}
+	for _, t := range showTorrents {
+		n := polochon.ParseEpisodeNumbering(t.Filename)
+		if n == nil {
+			n = polochon.ParseEpisodeNumbering(t.Title)
+		}
+
+		if n == nil || n.AirDate != s.Aired {
+			continue
+		}
+
+		// The daily shows numbering differs between the sources
+		t.Season = s.Season
+		t.Episode = s.Episode
+		torrents = append(torrents, t)
+	}
+
+	return torrents, nil
+}
+
 // Get the show infos from eztv
 func (e *Eztv) getShowEpisodeDetails(s *polochon.ShowEpisode) error {
 	if s.ShowImdbID == "" {
 		return ErrMissingShowImdbID
 	}
 
-	if s.Episode == 0 {
+	var episodeTorrents []*eztv.EpisodeTorrent
+	var err error
+	switch {
+	case s.IsDaily() && s.Aired != "":
+		episodeTorrents, err = getDailyEpisodeTorrents(s)
+	case s.Episode == 0:
 		return ErrInvalidShowEpisode
+	default:
+		episodeTorrents, err = eztvGetEpisode(s.ShowImdbID, s.Season, s.Episode)
 	}
 
-	episodeTorrents, err := eztvGetEpisode(s.ShowImdbID, s.Season, s.Episode)
 	switch err {
 	case nil:
 		// continue
@@ -86,8 +125,11 @@ func (e *Eztv) getShowEpisodeDetails(s *polochon.ShowEpisode) error {
 			Episode: t.Episode,
 			Quality: quality,
 			Result: &polochon.TorrentResult{
-				URL:    t.MagnetURL,
-				Source: moduleName,
+				Name:    t.Filename,
+				URL:     t.MagnetURL,
+				Seeders: t.Seeds,
+				Size:    int(t.Size),
+				Source:  moduleName,
 			},
 		})
 	}
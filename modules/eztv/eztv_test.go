@@ -107,6 +107,8 @@ func TestEztvGetTorrents(t *testing.T) {
 				Hash:      "yoshi",
 				Filename:  "pwet.1080p.mkv",
 				MagnetURL: "magnet:?xt=urn:btih:yoshi3",
+				Seeds:     42,
+				Size:      1024,
 			},
 		}, nil
 	}
@@ -124,6 +126,7 @@ func TestEztvGetTorrents(t *testing.T) {
 			Episode: s.Episode,
 			Quality: polochon.Quality480p,
 			Result: &polochon.TorrentResult{
+				Name:   "pwet.mkv",
 				Source: "eztv",
 				URL:    "magnet:?xt=urn:btih:yoshi2",
 			},
@@ -135,6 +138,7 @@ func TestEztvGetTorrents(t *testing.T) {
 			Episode: s.Episode,
 			Quality: polochon.Quality720p,
 			Result: &polochon.TorrentResult{
+				Name:   "pwet.720p.mkv",
 				Source: "eztv",
 				URL:    "magnet:?xt=urn:btih:yoshi1",
 			},
@@ -146,8 +150,11 @@ func TestEztvGetTorrents(t *testing.T) {
 			Episode: s.Episode,
 			Quality: polochon.Quality1080p,
 			Result: &polochon.TorrentResult{
-				Source: "eztv",
-				URL:    "magnet:?xt=urn:btih:yoshi3",
+				Name:    "pwet.1080p.mkv",
+				Source:  "eztv",
+				URL:     "magnet:?xt=urn:btih:yoshi3",
+				Seeders: 42,
+				Size:    1024,
 			},
 		},
 	}
@@ -157,6 +164,56 @@ func TestEztvGetTorrents(t *testing.T) {
 	}
 }
 
+func TestEztvGetDailyTorrents(t *testing.T) {
+	e := &Eztv{}
+	s := polochon.NewShowEpisode(polochon.ShowConfig{})
+	s.ShowImdbID = "tt3697842"
+	s.Season = 2026
+	s.Episode = 290
+	s.Aired = "2026-10-17"
+	s.Show = &polochon.Show{Daily: true}
+
+	eztvGetShow = func(_ string) ([]*eztv.EpisodeTorrent, error) {
+		return []*eztv.EpisodeTorrent{
+			{
+				ImdbID:    s.ShowImdbID,
+				Filename:  "Show.2026.10.16.720p.mkv",
+				MagnetURL: "magnet:?xt=urn:btih:yoshi1",
+			},
+			{
+				ImdbID:    s.ShowImdbID,
+				Season:    11,
+				Episode:   10,
+				Filename:  "Show.2026.10.17.720p.mkv",
+				MagnetURL: "magnet:?xt=urn:btih:yoshi2",
+			},
+		}, nil
+	}
+
+	if err := e.GetTorrents(s, fakeLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expected := []*polochon.Torrent{
+		{
+			ImdbID:  s.ShowImdbID,
+			Type:    polochon.TypeEpisode,
+			Season:  s.Season,
+			Episode: s.Episode,
+			Quality: polochon.Quality720p,
+			Result: &polochon.TorrentResult{
+				Name:   "Show.2026.10.17.720p.mkv",
+				Source: "eztv",
+				URL:    "magnet:?xt=urn:btih:yoshi2",
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(expected, s.Torrents) {
+		t.Errorf("failed to get daily torrents from eztv\nexpected %+v\ngot %+v", expected, s.Torrents)
+	}
+}
+
 func TestEztvInit(t *testing.T) {
 	for _, test := range []struct {
 		name     string
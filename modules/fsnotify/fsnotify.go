@@ -1,7 +1,9 @@
 package fsnotify
 
 import (
+	"io/fs"
 	"os"
+	"path/filepath"
 	"time"
 
 	polochon "github.com/odwrtw/polochon/lib"
@@ -23,12 +25,14 @@ const (
 
 	// DELAY represents the time to wait before sending an event
 	DELAY time.Duration = 100 * time.Millisecond
+
+	// maxDelay is the longest time an event waits while other events keep
+	// arriving
+	maxDelay = 10 * DELAY
 )
 
 // FsNotify is a fsNotifier watching a directory
-type FsNotify struct {
-	watcher *fsnotify.Watcher
-}
+type FsNotify struct{}
 
 // Name implements the Module interface
 func (fs *FsNotify) Name() string {
@@ -47,42 +51,99 @@ func (fs *FsNotify) Init([]byte) error {
 
 // Watch implements the modules fsNotifier interface
 func (fs *FsNotify) Watch(watchPath string, ctx polochon.FsNotifierCtx, log *logrus.Entry) error {
-	// Create a new watcher
-	watcher, err := fsnotify.NewWatcher()
-	if err != nil {
+	// Ensure that the watch path exists
+	if _, err := os.Stat(watchPath); os.IsNotExist(err) {
 		return err
 	}
-	fs.watcher = watcher
 
-	// Ensure that the watch path exists
-	if _, err := os.Stat(watchPath); os.IsNotExist(err) {
+	// Create a new watcher, each call gets its own to be able to watch
+	// multiple paths
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
 		return err
 	}
 
 	log = log.WithField("module", moduleName)
 
+	// Watch the path
+	if err := addWatch(watcher, watchPath, ctx.Recursive); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
 	// Run the event handler
-	go fs.eventHandler(ctx, log)
+	go fs.eventHandler(watcher, ctx, log)
 
-	// Watch the path
-	return fs.watcher.Add(watchPath)
+	return nil
 }
 
-func (fs *FsNotify) eventHandler(ctx polochon.FsNotifierCtx, log *logrus.Entry) {
+// addWatch adds the path to the watcher, the sub directories are added as
+// well if recursive is true
+func addWatch(watcher *fsnotify.Watcher, watchPath string, recursive bool) error {
+	if !recursive {
+		return watcher.Add(watchPath)
+	}
+
+	return filepath.WalkDir(watchPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// The directory may have been removed in the meantime
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		return watcher.Add(path)
+	})
+}
+
+func (fs *FsNotify) eventHandler(watcher *fsnotify.Watcher, ctx polochon.FsNotifierCtx, log *logrus.Entry) {
 	// Notify the waitgroup
 	ctx.Wg.Add(1)
 	defer ctx.Wg.Done()
 
 	// Close the watcher when done
-	defer func() { _ = fs.watcher.Close() }()
+	defer func() { _ = watcher.Close() }()
+
+	// The events are coalesced and sent once no event happened during the
+	// delay, a file written many times is only sent once. The pending events
+	// are sent after maxDelay even if events keep arriving.
+	pending := map[string]struct{}{}
+	var firstPending time.Time
+	timer := time.NewTimer(DELAY)
+	timer.Stop()
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done:
 			log.Debug("fsnotify is done watching")
 			return
-		case ev := <-fs.watcher.Events:
-			if ev.Op != fsnotify.Create && ev.Op != fsnotify.Chmod {
+		case <-timer.C:
+			for name := range pending {
+				select {
+				case ctx.Event <- name:
+				case <-ctx.Done:
+					log.Debug("fsnotify is done watching")
+					return
+				}
+				delete(pending, name)
+			}
+		case ev := <-watcher.Events:
+			// Watch the new directories
+			if ctx.Recursive && ev.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					if err := addWatch(watcher, ev.Name, true); err != nil {
+						log.Warnf("failed to watch %s: %s", ev.Name, err.Error())
+					}
+				}
+			}
+
+			if !ctx.AllChanges && ev.Op != fsnotify.Create && ev.Op != fsnotify.Chmod {
 				continue
 			}
 
@@ -90,11 +151,12 @@ func (fs *FsNotify) eventHandler(ctx polochon.FsNotifierCtx, log *logrus.Entry)
 			// Transmission creates the folder and move the files afterwards.
 			// We need to wait for the file to be moved in before sending the
 			// event. Delay is the estimated time to wait.
-			go func() {
-				time.Sleep(DELAY)
-				ctx.Event <- ev.Name
-			}()
-		case err := <-fs.watcher.Errors:
+			if len(pending) == 0 {
+				firstPending = time.Now()
+			}
+			pending[ev.Name] = struct{}{}
+			timer.Reset(max(min(DELAY, maxDelay-time.Since(firstPending)), 0))
+		case err := <-watcher.Errors:
 			log.Error(err)
 		}
 	}
@@ -63,6 +63,15 @@ func (g *Guessit) Guess(file polochon.File, movieConf polochon.MovieConfig, show
 	// Format the title
 	title := toUpperCaseFirst(guess.Title)
 
+	// The daily shows episodes are named after their aired date, the date
+	// could be mistaken for the year of a movie
+	numbering := polochon.ParseEpisodeNumbering(filename)
+	daily := numbering != nil && numbering.AirDate != ""
+	if daily {
+		guess.Type = whatsthis.Episode
+		guess.Year = 0
+	}
+
 	var video polochon.Video
 
 	switch guess.Type {
@@ -76,13 +85,19 @@ func (g *Guessit) Guess(file polochon.File, movieConf polochon.MovieConfig, show
 		show := polochon.NewShow(showConf)
 		show.Year = guess.Year
 		show.Title = title
-		video = &polochon.ShowEpisode{
+		episode := &polochon.ShowEpisode{
 			ShowConfig: showConf,
 			Show:       show,
 			ShowTitle:  title,
 			Episode:    guess.Episode,
 			Season:     guess.Season,
 		}
+
+		// Parse the multi-episode, the absolute and the air date numbering
+		if numbering != nil {
+			numbering.Apply(episode)
+		}
+		video = episode
 	default:
 		return nil, fmt.Errorf("guessit: invalid guess type: %s", guess.Type)
 	}
@@ -0,0 +1,245 @@
+package local
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/sirupsen/logrus"
+)
+
+// Make sure that the module is a wishlist editor
+var _ polochon.WishlistEditor = (*Wishlist)(nil)
+
+// Register a new wishlister
+func init() {
+	polochon.RegisterModule(&Wishlist{})
+}
+
+// Module constants
+const (
+	moduleName = "local"
+)
+
+// Local errors
+var (
+	ErrMissingPath = errors.New("local: missing wishlist file path")
+	ErrMissingDir  = errors.New("local: the wishlist file directory does not exist")
+)
+
+// Params represents the module params
+type Params struct {
+	Path string `yaml:"path"`
+}
+
+// Wishlist holds the local wishlist
+type Wishlist struct {
+	*Params
+	configured bool
+
+	// Mutex to protect the file from concurrent reads / writes
+	mu sync.Mutex
+}
+
+// wishlistFile represents the content of the wishlist file
+type wishlistFile struct {
+	Movies []*polochon.WishedMovie `json:"movies"`
+	Shows  []*polochon.WishedShow  `json:"shows"`
+}
+
+// Init implements the module interface
+func (w *Wishlist) Init(p []byte) error {
+	if w.configured {
+		return nil
+	}
+
+	params := &Params{}
+	if err := yaml.Unmarshal(p, params); err != nil {
+		return err
+	}
+
+	return w.InitWithParams(params)
+}
+
+// InitWithParams configures the module
+func (w *Wishlist) InitWithParams(params *Params) error {
+	if params.Path == "" {
+		return ErrMissingPath
+	}
+
+	// The file is created on the first write, its directory must exist
+	info, err := os.Stat(filepath.Dir(params.Path))
+	if err != nil || !info.IsDir() {
+		return ErrMissingDir
+	}
+
+	w.Params = params
+	w.configured = true
+	return nil
+}
+
+// Name implements the Module interface
+func (w *Wishlist) Name() string {
+	return moduleName
+}
+
+// Status implements the Module interface
+func (w *Wishlist) Status() (polochon.ModuleStatus, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.read(); err != nil {
+		return polochon.StatusFail, err
+	}
+
+	return polochon.StatusOK, nil
+}
+
+// GetMovieWishlist implements the Wishlister interface
+func (w *Wishlist) GetMovieWishlist(log *logrus.Entry) ([]*polochon.WishedMovie, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wl, err := w.read()
+	if err != nil {
+		return nil, err
+	}
+
+	return wl.Movies, nil
+}
+
+// GetShowWishlist implements the Wishlister interface
+func (w *Wishlist) GetShowWishlist(log *logrus.Entry) ([]*polochon.WishedShow, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wl, err := w.read()
+	if err != nil {
+		return nil, err
+	}
+
+	return wl.Shows, nil
+}
+
+// AddMovie implements the WishlistEditor interface, if the movie is already
+// in the wishlist it is replaced
+func (w *Wishlist) AddMovie(movie *polochon.WishedMovie, log *logrus.Entry) error {
+	return w.update(func(wl *wishlistFile) error {
+		i := slices.IndexFunc(wl.Movies, func(m *polochon.WishedMovie) bool {
+			return m.ImdbID == movie.ImdbID
+		})
+
+		if i == -1 {
+			wl.Movies = append(wl.Movies, movie)
+		} else {
+			wl.Movies[i] = movie
+		}
+
+		log.WithField("imdb_id", movie.ImdbID).Info("movie added to the local wishlist")
+		return nil
+	})
+}
+
+// AddShow implements the WishlistEditor interface, if the show is already in
+// the wishlist it is replaced
+func (w *Wishlist) AddShow(show *polochon.WishedShow, log *logrus.Entry) error {
+	return w.update(func(wl *wishlistFile) error {
+		i := slices.IndexFunc(wl.Shows, func(s *polochon.WishedShow) bool {
+			return s.ImdbID == show.ImdbID
+		})
+
+		if i == -1 {
+			wl.Shows = append(wl.Shows, show)
+		} else {
+			wl.Shows[i] = show
+		}
+
+		log.WithField("imdb_id", show.ImdbID).Info("show added to the local wishlist")
+		return nil
+	})
+}
+
+// RemoveMovie implements the WishlistEditor interface
+func (w *Wishlist) RemoveMovie(imdbID string, log *logrus.Entry) error {
+	return w.update(func(wl *wishlistFile) error {
+		l := len(wl.Movies)
+		wl.Movies = slices.DeleteFunc(wl.Movies, func(m *polochon.WishedMovie) bool {
+			return m.ImdbID == imdbID
+		})
+
+		if len(wl.Movies) == l {
+			return polochon.ErrNotInWishlist
+		}
+
+		log.WithField("imdb_id", imdbID).Info("movie removed from the local wishlist")
+		return nil
+	})
+}
+
+// RemoveShow implements the WishlistEditor interface
+func (w *Wishlist) RemoveShow(imdbID string, log *logrus.Entry) error {
+	return w.update(func(wl *wishlistFile) error {
+		l := len(wl.Shows)
+		wl.Shows = slices.DeleteFunc(wl.Shows, func(s *polochon.WishedShow) bool {
+			return s.ImdbID == imdbID
+		})
+
+		if len(wl.Shows) == l {
+			return polochon.ErrNotInWishlist
+		}
+
+		log.WithField("imdb_id", imdbID).Info("show removed from the local wishlist")
+		return nil
+	})
+}
+
+// update reads the wishlist file, applies f and writes it back
+func (w *Wishlist) update(f func(*wishlistFile) error) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wl, err := w.read()
+	if err != nil {
+		return err
+	}
+
+	if err := f(wl); err != nil {
+		return err
+	}
+
+	return w.write(wl)
+}
+
+// read reads the wishlist file, a missing file is an empty wishlist
+func (w *Wishlist) read() (*wishlistFile, error) {
+	wl := &wishlistFile{
+		Movies: []*polochon.WishedMovie{},
+		Shows:  []*polochon.WishedShow{},
+	}
+
+	file, err := os.Open(w.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return wl, nil
+		}
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := json.NewDecoder(file).Decode(wl); err != nil {
+		return nil, err
+	}
+
+	return wl, nil
+}
+
+// write saves the wishlist file
+func (w *Wishlist) write(wl *wishlistFile) error {
+	return polochon.WriteJSONFile(w.Path, wl)
+}
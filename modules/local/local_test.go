@@ -0,0 +1,128 @@
+package local
+
+import (
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	polochon "github.com/odwrtw/polochon/lib"
+	"github.com/sirupsen/logrus"
+)
+
+var mockLogEntry = logrus.NewEntry(&logrus.Logger{Out: io.Discard})
+
+func newTestWishlist(t *testing.T) *Wishlist {
+	w := &Wishlist{}
+	params := &Params{Path: filepath.Join(t.TempDir(), "wishlist.json")}
+	if err := w.InitWithParams(params); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	return w
+}
+
+func TestInitWithoutPath(t *testing.T) {
+	w := &Wishlist{}
+	if err := w.Init([]byte("")); err != ErrMissingPath {
+		t.Fatalf("expected %q, got %q", ErrMissingPath, err)
+	}
+}
+
+func TestInitWithoutDir(t *testing.T) {
+	w := &Wishlist{}
+	params := &Params{Path: filepath.Join(t.TempDir(), "missing", "wishlist.json")}
+	if err := w.InitWithParams(params); err != ErrMissingDir {
+		t.Fatalf("expected %q, got %q", ErrMissingDir, err)
+	}
+}
+
+func TestEmptyWishlist(t *testing.T) {
+	w := newTestWishlist(t)
+
+	movies, err := w.GetMovieWishlist(mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if len(movies) != 0 {
+		t.Errorf("expected no movies, got %+v", movies)
+	}
+
+	status, err := w.Status()
+	if err != nil || status != polochon.StatusOK {
+		t.Errorf("expected status %q, got %q (%v)", polochon.StatusOK, status, err)
+	}
+}
+
+func TestMovies(t *testing.T) {
+	w := newTestWishlist(t)
+
+	for _, m := range []*polochon.WishedMovie{
+		{ImdbID: "tt001"},
+		{ImdbID: "tt002", Qualities: []polochon.Quality{polochon.Quality720p}},
+		{ImdbID: "tt001", Qualities: []polochon.Quality{polochon.Quality1080p}},
+	} {
+		if err := w.AddMovie(m, mockLogEntry); err != nil {
+			t.Fatalf("expected no error, got %q", err)
+		}
+	}
+
+	if err := w.RemoveMovie("tt002", mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := w.RemoveMovie("tt404", mockLogEntry); err != polochon.ErrNotInWishlist {
+		t.Fatalf("expected %q, got %q", polochon.ErrNotInWishlist, err)
+	}
+
+	got, err := w.GetMovieWishlist(mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expected := []*polochon.WishedMovie{
+		{ImdbID: "tt001", Qualities: []polochon.Quality{polochon.Quality1080p}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestShows(t *testing.T) {
+	w := newTestWishlist(t)
+
+	show := &polochon.WishedShow{
+		ImdbID:    "tt001",
+		Season:    2,
+		Episode:   5,
+		Qualities: []polochon.Quality{polochon.Quality1080p, polochon.Quality720p},
+	}
+	if err := w.AddShow(show, mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	// A new instance should read the same file
+	other := &Wishlist{}
+	if err := other.InitWithParams(w.Params); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	got, err := other.GetShowWishlist(mockLogEntry)
+	if err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	expected := []*polochon.WishedShow{show}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+
+	if err := other.RemoveShow("tt001", mockLogEntry); err != nil {
+		t.Fatalf("expected no error, got %q", err)
+	}
+
+	if err := w.RemoveShow("tt001", mockLogEntry); err != polochon.ErrNotInWishlist {
+		t.Fatalf("expected %q, got %q", polochon.ErrNotInWishlist, err)
+	}
+}
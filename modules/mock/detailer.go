@@ -71,10 +71,21 @@ func (mock *Mock) getShowEpisodeDetails(s *polochon.ShowEpisode) {
 	if s.ShowImdbID == "" {
 		s.ShowImdbID = randomImdbID()
 	}
-	if s.Season == 0 {
+	// The daily episodes are numbered after their aired date
+	aired, err := s.AiredDate()
+	daily := s.Episode == 0 && err == nil
+	if daily {
+		s.Season = aired.Year()
+		s.Episode = aired.YearDay()
+	}
+	if s.Episode == 0 && s.AbsoluteNumber != 0 {
+		// Every absolute numbered episode is in the first season
 		s.Season = 1
+		s.Episode = s.AbsoluteNumber
 	}
+	// The episodes of the season 0 are specials
 	if s.Episode == 0 {
+		s.Season = 1
 		s.Episode = 1
 	}
 	if s.Title == "" {
@@ -96,7 +107,9 @@ func (mock *Mock) getShowEpisodeDetails(s *polochon.ShowEpisode) {
 		s.Thumb = fmt.Sprintf("http://base-photo.com/thumb/%s.jpg", s.ShowImdbID)
 	}
 
-	s.Aired = "Already aired"
+	if !daily {
+		s.Aired = "Already aired"
+	}
 	s.Plot = fmt.Sprintf("This is the plot of the episode %s.S%02dE%02d", s.ShowImdbID, s.Season, s.Episode)
 	s.Runtime = 200
 	s.Rating = 5
@@ -14,6 +14,8 @@ func (mock *Mock) GetTorrents(i any, log *logrus.Entry) error {
 	switch v := i.(type) {
 	case *polochon.ShowEpisode:
 		mock.getShowEpisodeTorrents(v)
+	case *polochon.ShowSeason:
+		mock.getShowSeasonTorrents(v)
 	case *polochon.Movie:
 		mock.getMovieTorrents(v)
 	default:
@@ -41,6 +43,24 @@ func (mock *Mock) getShowEpisodeTorrents(e *polochon.ShowEpisode) {
 	}
 }
 
+func (mock *Mock) getShowSeasonTorrents(s *polochon.ShowSeason) {
+	s.Torrents = []*polochon.Torrent{}
+	for _, q := range []polochon.Quality{polochon.Quality480p, polochon.Quality720p} {
+		s.Torrents = append(s.Torrents, &polochon.Torrent{
+			ImdbID:  s.ShowImdbID,
+			Type:    "episode",
+			Season:  s.Season,
+			Quality: q,
+			Result: &polochon.TorrentResult{
+				URL:      fmt.Sprintf("https://mock.com/s%02d%s.torrent", s.Season, q),
+				Source:   moduleName,
+				Seeders:  rand.Intn(100),
+				Leechers: rand.Intn(500),
+			},
+		})
+	}
+}
+
 func (mock *Mock) getMovieTorrents(m *polochon.Movie) {
 	m.Torrents = []*polochon.Torrent{}
 	for _, q := range []polochon.Quality{polochon.Quality720p, polochon.Quality1080p, polochon.Quality3D} {
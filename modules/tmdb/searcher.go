@@ -42,5 +42,5 @@ func (t *TmDB) SearchMovie(key string, log *logrus.Entry) ([]*polochon.Movie, er
 // SearchShow implements the polochon Searcher interface
 // Not implemented
 func (t *TmDB) SearchShow(key string, log *logrus.Entry) ([]*polochon.Show, error) {
-	return nil, ErrInvalidArgument
+	return nil, polochon.ErrNotAvailable
 }
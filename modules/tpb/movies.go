@@ -37,7 +37,7 @@ func (mS *movieSearcher) imdbID() string {
 	return mS.Movie.ImdbID
 }
 
-func (mS *movieSearcher) isValidGuess(guess whatsthis.Info, log *logrus.Entry) bool {
+func (mS *movieSearcher) isValidGuess(_ string, guess whatsthis.Info, log *logrus.Entry) bool {
 	if !strings.EqualFold(guess.Title, mS.Movie.Title) {
 		log.Debugf("skipping bad movie title %s != %s", guess.Title, mS.Movie.Title)
 		return false
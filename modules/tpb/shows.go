@@ -15,6 +15,11 @@ type showSearcher struct {
 }
 
 func (sS *showSearcher) key() string {
+	// The daily shows releases are named after their aired date
+	if aired, err := sS.Episode.AiredDate(); err == nil && sS.Episode.IsDaily() {
+		return fmt.Sprintf("%s %s", sS.Episode.ShowTitle, aired.Format("2006 01 02"))
+	}
+
 	return fmt.Sprintf(
 		"%s S%02dE%02d",
 		sS.Episode.ShowTitle,
@@ -45,7 +50,18 @@ func (sS *showSearcher) imdbID() string {
 	return sS.Episode.ShowImdbID
 }
 
-func (sS *showSearcher) isValidGuess(guess whatsthis.Info, log *logrus.Entry) bool {
+func (sS *showSearcher) isValidGuess(name string, guess whatsthis.Info, log *logrus.Entry) bool {
+	// The daily shows releases are checked with their aired date
+	if sS.Episode.IsDaily() {
+		n := polochon.ParseEpisodeNumbering(name)
+		if n == nil || n.AirDate != sS.Episode.Aired {
+			log.Debugf("skipping bad daily show release %s", name)
+			return false
+		}
+
+		return true
+	}
+
 	// Check the video type
 	if guess.Type != whatsthis.Episode {
 		log.Debugf("tpb: is not an episode but a %s", guess.Type)
@@ -56,6 +72,11 @@ func (sS *showSearcher) isValidGuess(guess whatsthis.Info, log *logrus.Entry) bo
 		log.Debugf("skipping bad show title %s != %s", guess.Title, sS.Episode.ShowTitle)
 		return false
 	}
+	// The releases of the absolute numbered shows have no season
+	if guess.Season == 0 && sS.Episode.AbsoluteNumber != 0 && guess.Episode == sS.Episode.AbsoluteNumber {
+		return true
+	}
+
 	// Check if the data matches the episode
 	if guess.Season != sS.Episode.Season || guess.Episode != sS.Episode.Episode {
 		log.Debugf("skipping bad show episode/season S%dE%d != S%dE%d", guess.Season, guess.Episode, sS.Episode.Season, sS.Episode.Episode)
@@ -63,3 +84,51 @@ func (sS *showSearcher) isValidGuess(guess whatsthis.Info, log *logrus.Entry) bo
 	}
 	return true
 }
+
+type seasonSearcher struct {
+	Season *polochon.ShowSeason
+	Users  []string
+}
+
+func (sS *seasonSearcher) key() string {
+	return fmt.Sprintf(
+		"%s S%02d",
+		sS.Season.ShowTitle,
+		sS.Season.Season,
+	)
+}
+
+func (sS *seasonSearcher) users() []string {
+	return sS.Users
+}
+
+func (sS *seasonSearcher) setTorrents(torrents []*polochon.Torrent) {
+	for _, t := range torrents {
+		t.ImdbID = sS.Season.ShowImdbID
+		t.Season = sS.Season.Season
+		t.Type = polochon.TypeEpisode
+	}
+	sS.Season.Torrents = torrents
+}
+
+func (sS *seasonSearcher) defaultQuality() string {
+	return string(polochon.Quality480p)
+}
+
+func (sS *seasonSearcher) imdbID() string {
+	return sS.Season.ShowImdbID
+}
+
+func (sS *seasonSearcher) isValidGuess(_ string, guess whatsthis.Info, log *logrus.Entry) bool {
+	if !strings.EqualFold(guess.Title, sS.Season.ShowTitle) {
+		log.Debugf("skipping bad show title %s != %s", guess.Title, sS.Season.ShowTitle)
+		return false
+	}
+
+	// A season pack has a season but no episode number
+	if guess.Season != sS.Season.Season || guess.Episode != 0 {
+		log.Debugf("skipping bad show season S%dE%d != S%d", guess.Season, guess.Episode, sS.Season.Season)
+		return false
+	}
+	return true
+}
@@ -106,7 +106,7 @@ type searcher interface {
 	users() []string
 	defaultQuality() string
 	setTorrents([]*polochon.Torrent)
-	isValidGuess(guess whatsthis.Info, log *logrus.Entry) bool
+	isValidGuess(name string, guess whatsthis.Info, log *logrus.Entry) bool
 	imdbID() string
 }
 
@@ -118,6 +118,11 @@ func (t *TPB) newSearcher(i any) (searcher, error) {
 			Episode: v,
 			Users:   t.ShowUsers,
 		}, nil
+	case *polochon.ShowSeason:
+		return &seasonSearcher{
+			Season: v,
+			Users:  t.ShowUsers,
+		}, nil
 	case *polochon.Movie:
 		return &movieSearcher{
 			Movie: v,
@@ -214,7 +219,7 @@ func (t *TPB) transformTorrents(s searcher, list []*tpb.Torrent, log *logrus.Ent
 		guess := whatsthis.Video(torrentStr)
 
 		// Check the guess validity
-		if !s.isValidGuess(guess, log) {
+		if !s.isValidGuess(torrentStr, guess, log) {
 			continue
 		}
 
@@ -256,8 +261,8 @@ func (t *TPB) transformTorrents(s searcher, list []*tpb.Torrent, log *logrus.Ent
 			},
 		})
 	}
-	// Filter the torrents to keep only the best ones
-	return polochon.FilterTorrents(torrents)
+
+	return torrents
 }
 
 func torrentGuessitStr(t *tpb.Torrent) string {
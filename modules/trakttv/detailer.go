@@ -148,7 +148,8 @@ func (trakt *TraktTV) getShowEpisodes(show *polochon.Show, _ *logrus.Entry) erro
 
 // getShowEpisodeDetails gets details for the polochon ShowEpisode
 func (trakt *TraktTV) getShowEpisodeDetails(e *polochon.ShowEpisode, _ *logrus.Entry) error {
-	if e.Season == 0 || e.Episode == 0 {
+	// The season 0 holds the specials
+	if e.Episode == 0 {
 		return ErrInvalidArgument
 	}
 
@@ -8,7 +8,7 @@ import (
 )
 
 func labels(torrent *polochon.Torrent) []string {
-	if !torrent.HasVideo() {
+	if !torrent.HasVideo() && !torrent.IsSeasonPack() {
 		return nil
 	}
 
@@ -71,6 +71,22 @@ func TestLabels(t *testing.T) {
 				"episode=3",
 			},
 		},
+		{
+			name: "season pack",
+			torrent: &polochon.Torrent{
+				ImdbID:  "tt000000",
+				Quality: polochon.Quality720p,
+				Type:    "episode",
+				Season:  2,
+			},
+			expected: []string{
+				"type=episode",
+				"imdb_id=tt000000",
+				"quality=720p",
+				"season=2",
+				"episode=0",
+			},
+		},
 	}
 
 	for _, tc := range tt {
@@ -285,6 +285,7 @@ func (t *TvDB) getShowEpisodes(s *polochon.Show, show *tvdb.Series, params url.V
 		episode.ShowTitle = s.Title
 		episode.Season = e.AiredSeason
 		episode.Episode = e.AiredEpisodeNumber
+		episode.AbsoluteNumber = e.AbsoluteNumber
 		episode.TvdbID = e.ID
 		episode.Aired = e.FirstAired
 		episode.Plot = e.Overview
@@ -403,8 +404,9 @@ func (t *TvDB) getShowDetails(s *polochon.Show, params url.Values) error {
 }
 
 func (t *TvDB) getEpisodeDetails(s *polochon.ShowEpisode) error {
-	// The season / episode infos are needed
-	if s.Season == 0 || s.Episode == 0 {
+	// The episode number, the absolute number or the aired date is needed,
+	// the season 0 holds the specials
+	if s.Episode == 0 && s.AbsoluteNumber == 0 && s.Aired == "" {
 		return ErrMissingShowEpisodeInformations
 	}
 
@@ -432,6 +434,29 @@ func (t *TvDB) getEpisodeDetails(s *polochon.ShowEpisode) error {
 		"airedSeason":  {strconv.Itoa(s.Season)},
 		"airedEpisode": {strconv.Itoa(s.Episode)},
 	}
+	match := func(e *polochon.ShowEpisode) bool {
+		return e.Season == s.Season && e.Episode == s.Episode
+	}
+
+	// Find the episode from its absolute number or its aired date if its
+	// season and episode are unknown
+	switch {
+	case s.Episode != 0:
+	case s.AbsoluteNumber != 0:
+		params = url.Values{
+			"absoluteNumber": {strconv.Itoa(s.AbsoluteNumber)},
+		}
+		match = func(e *polochon.ShowEpisode) bool {
+			return e.AbsoluteNumber == s.AbsoluteNumber
+		}
+	default:
+		params = url.Values{
+			"firstAired": {s.Aired},
+		}
+		match = func(e *polochon.ShowEpisode) bool {
+			return e.Aired == s.Aired
+		}
+	}
 
 	err := t.getShowDetails(show, params)
 	if err != nil {
@@ -440,11 +465,12 @@ func (t *TvDB) getEpisodeDetails(s *polochon.ShowEpisode) error {
 
 	var updated bool
 	for _, e := range show.Episodes {
-		if e.Season == s.Season && e.Episode == s.Episode {
+		if match(e) {
 			s.Title = e.Title
 			s.ShowTitle = e.ShowTitle
 			s.Season = e.Season
 			s.Episode = e.Episode
+			s.AbsoluteNumber = e.AbsoluteNumber
 			s.TvdbID = e.TvdbID
 			s.Aired = e.Aired
 			s.Plot = e.Plot
@@ -490,9 +516,10 @@ func (t *TvDB) GetShowCalendar(show *polochon.Show, log *logrus.Entry) (*polocho
 		}
 
 		calendar.Episodes = append(calendar.Episodes, &polochon.ShowCalendarEpisode{
-			Season:    e.Season,
-			Episode:   e.Episode,
-			AiredDate: &aired,
+			Season:         e.Season,
+			Episode:        e.Episode,
+			AbsoluteNumber: e.AbsoluteNumber,
+			AiredDate:      &aired,
 		})
 	}
 
@@ -1,8 +1,6 @@
 package yts
 
 import (
-	"fmt"
-
 	polochon "github.com/odwrtw/polochon/lib"
 	"github.com/odwrtw/yts"
 	"github.com/sirupsen/logrus"
@@ -30,5 +28,5 @@ func (y *Yts) SearchMovie(key string, log *logrus.Entry) ([]*polochon.Movie, err
 
 // SearchShow implements polochon Searcher interface
 func (y *Yts) SearchShow(key string, log *logrus.Entry) ([]*polochon.Show, error) {
-	return nil, fmt.Errorf("yts: not implemented")
+	return nil, polochon.ErrNotAvailable
 }